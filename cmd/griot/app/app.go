@@ -17,7 +17,11 @@ package app
 import (
 	"context"
 
+	"github.com/z5labs/griot/cmd/griot/collection"
 	"github.com/z5labs/griot/cmd/griot/content"
+	"github.com/z5labs/griot/cmd/griot/library"
+	"github.com/z5labs/griot/cmd/griot/login"
+	"github.com/z5labs/griot/cmd/griot/logout"
 	"github.com/z5labs/griot/internal/command"
 )
 
@@ -26,7 +30,11 @@ type Config struct{}
 func Init(ctx context.Context, cfg Config) (*command.App, error) {
 	app := command.NewApp(
 		"griot",
+		command.Sub(collection.New()),
 		command.Sub(content.New()),
+		command.Sub(library.New()),
+		command.Sub(login.New()),
+		command.Sub(logout.New()),
 	)
 	return app, nil
 }
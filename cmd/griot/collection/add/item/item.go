@@ -0,0 +1,201 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package item
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"item",
+		command.Args(args...),
+		command.Short("Add content or another collection to a collection"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("collection-id", "", "Specify the id of the collection to add the item to.")
+			fs.String("item-id", "", "Specify the id of the content or collection to add.")
+			fs.String("item-type", "", "Specify whether the item is content or a collection. If omitted, the item is a collection if one exists with its id and otherwise content. (values content,collection)")
+			fs.Int("order", 0, "Specify the position of the item in the collection, starting from 1. If omitted, the item is appended.")
+		}),
+		command.Handle(initItemHandler),
+	)
+}
+
+type config struct {
+	Host         string `flag:"content-host"`
+	CollectionId string `flag:"collection-id"`
+	ItemId       string `flag:"item-id"`
+	ItemType     string `flag:"item-type"`
+	Order        int    `flag:"order"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateRequired("collection-id", c.CollectionId),
+		validateRequired("item-id", c.ItemId),
+		validateItemType(c.ItemType),
+		validateOrder(c.Order),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateRequired(name, value string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(value) == 0 {
+			return command.InvalidFlagError{
+				Name:  name,
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+var ErrUnknownItemType = errors.New("must be either content or collection")
+
+func validateItemType(typ string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(typ) == 0 {
+			return nil
+		}
+		if _, ok := collectionpb.ItemType_value[strings.ToUpper(typ)]; !ok {
+			return command.InvalidFlagError{
+				Name:  "item-type",
+				Cause: ErrUnknownItemType,
+			}
+		}
+		return nil
+	}
+}
+
+var ErrNegativeOrder = errors.New("must not be negative")
+
+func validateOrder(n int) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if n < 0 {
+			return command.InvalidFlagError{
+				Name:  "order",
+				Cause: ErrNegativeOrder,
+			}
+		}
+		return nil
+	}
+}
+
+type addClient interface {
+	GetCollection(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+	AddCollectionItem(context.Context, *content.AddCollectionItemRequest) (*collectionpb.Collection, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	collectionId string
+	itemId       string
+
+	// itemType is nil if it should be determined from the item id.
+	itemType *collectionpb.ItemType
+	order    int
+
+	content addClient
+}
+
+func initItemHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	log := humus.Logger("item")
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:          log,
+		collectionId: cfg.CollectionId,
+		itemId:       cfg.ItemId,
+		order:        cfg.Order,
+		content:      content.NewClient(hc, cfg.Host, opts...),
+	}
+	if len(cfg.ItemType) > 0 {
+		h.itemType = collectionpb.ItemType(collectionpb.ItemType_value[strings.ToUpper(cfg.ItemType)]).Enum()
+	}
+	return h, nil
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("item").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	typ, err := h.resolveItemType(spanCtx)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to determine item type", slog.String("error", err.Error()))
+		return err
+	}
+
+	_, err = h.content.AddCollectionItem(spanCtx, &content.AddCollectionItemRequest{
+		CollectionId: h.collectionId,
+		Type:         typ,
+		ItemId:       h.itemId,
+		Position:     uint32(h.order),
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to add collection item", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// resolveItemType returns the given item type or, if none was given, looks
+// up whether a collection exists with the item id.
+func (h *handler) resolveItemType(ctx context.Context) (collectionpb.ItemType, error) {
+	if h.itemType != nil {
+		return *h.itemType, nil
+	}
+
+	_, err := h.content.GetCollection(ctx, &content.GetCollectionRequest{
+		Id: h.itemId,
+	})
+	var status *humuspb.Status
+	if errors.As(err, &status) && status.GetCode() == humuspb.Code_NOT_FOUND {
+		return collectionpb.ItemType_CONTENT, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return collectionpb.ItemType_COLLECTION, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package item
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the collection id is not set", func(t *testing.T) {
+			app := New("--item-id", "content-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "collection-id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the item id is not set", func(t *testing.T) {
+			app := New("--collection-id", "collection-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "item-id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the item type is unknown", func(t *testing.T) {
+			app := New("--collection-id", "collection-1", "--item-id", "content-1", "--item-type", "library")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "item-type", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrUnknownItemType) {
+				return
+			}
+		})
+
+		t.Run("if the order is negative", func(t *testing.T) {
+			app := New("--collection-id", "collection-1", "--item-id", "content-1", "--order", "-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "order", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNegativeOrder) {
+				return
+			}
+		})
+	})
+}
+
+type addClientStub struct {
+	getCollection     func(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+	addCollectionItem func(context.Context, *content.AddCollectionItemRequest) (*collectionpb.Collection, error)
+}
+
+func (s addClientStub) GetCollection(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+	return s.getCollection(ctx, req)
+}
+
+func (s addClientStub) AddCollectionItem(ctx context.Context, req *content.AddCollectionItemRequest) (*collectionpb.Collection, error) {
+	return s.addCollectionItem(ctx, req)
+}
+
+func collectionNotFound(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+	return nil, &humuspb.Status{
+		Code: humuspb.Code_NOT_FOUND.Enum(),
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to look up the item type", func(t *testing.T) {
+			getErr := errors.New("failed to get")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				content: addClientStub{
+					getCollection: func(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+						return nil, getErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, getErr, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to add the item", func(t *testing.T) {
+			addErr := errors.New("failed to add")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				content: addClientStub{
+					getCollection: collectionNotFound,
+					addCollectionItem: func(ctx context.Context, req *content.AddCollectionItemRequest) (*collectionpb.Collection, error) {
+						return nil, addErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, addErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will add the item", func(t *testing.T) {
+		testCases := []struct {
+			Name          string
+			ItemType      *collectionpb.ItemType
+			GetCollection func(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+			Expected      collectionpb.ItemType
+		}{
+			{
+				Name:     "as the given item type",
+				ItemType: collectionpb.ItemType_CONTENT.Enum(),
+				Expected: collectionpb.ItemType_CONTENT,
+			},
+			{
+				Name:          "as content if no collection exists with the item id",
+				GetCollection: collectionNotFound,
+				Expected:      collectionpb.ItemType_CONTENT,
+			},
+			{
+				Name: "as a collection if one exists with the item id",
+				GetCollection: func(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+					return &collectionpb.Collection{Id: &req.Id}, nil
+				},
+				Expected: collectionpb.ItemType_COLLECTION,
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				var gotReq *content.AddCollectionItemRequest
+				h := &handler{
+					log:          slog.New(noop.LogHandler{}),
+					collectionId: "collection-2",
+					itemId:       "collection-1",
+					itemType:     testCase.ItemType,
+					order:        1,
+					content: addClientStub{
+						getCollection: testCase.GetCollection,
+						addCollectionItem: func(ctx context.Context, req *content.AddCollectionItemRequest) (*collectionpb.Collection, error) {
+							gotReq = req
+							return &collectionpb.Collection{}, nil
+						},
+					},
+				}
+
+				err := h.Handle(context.Background())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, testCase.Expected, gotReq.Type) {
+					return
+				}
+				if !assert.Equal(t, "collection-2", gotReq.CollectionId) {
+					return
+				}
+				if !assert.Equal(t, "collection-1", gotReq.ItemId) {
+					return
+				}
+				if !assert.Equal(t, uint32(1), gotReq.Position) {
+					return
+				}
+			})
+		}
+	})
+}
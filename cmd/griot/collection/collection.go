@@ -0,0 +1,38 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"github.com/z5labs/griot/cmd/griot/collection/add"
+	"github.com/z5labs/griot/cmd/griot/collection/create"
+	"github.com/z5labs/griot/cmd/griot/collection/list"
+	"github.com/z5labs/griot/cmd/griot/collection/remove"
+	"github.com/z5labs/griot/cmd/griot/collection/rename"
+	"github.com/z5labs/griot/cmd/griot/collection/reorder"
+	"github.com/z5labs/griot/internal/command"
+)
+
+func New() *command.App {
+	return command.NewApp(
+		"collection",
+		command.Short("Manage collections"),
+		command.Sub(add.New()),
+		command.Sub(create.New()),
+		command.Sub(list.New()),
+		command.Sub(remove.New()),
+		command.Sub(rename.New()),
+		command.Sub(reorder.New()),
+	)
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"create",
+		command.Args(args...),
+		command.Short("Create a collection"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("name", "", "Specify the name of the collection.")
+		}),
+		command.Handle(initCreateHandler),
+	)
+}
+
+type config struct {
+	Host string `flag:"content-host"`
+	Name string `flag:"name"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateName(c.Name),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateName(name string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(name) == 0 {
+			return command.InvalidFlagError{
+				Name:  "name",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+type createClient interface {
+	CreateCollection(context.Context, *content.CreateCollectionRequest) (*collectionpb.Collection, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	name string
+	out  io.Writer
+
+	content createClient
+}
+
+func initCreateHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	log := humus.Logger("create")
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:     log,
+		name:    cfg.Name,
+		out:     os.Stdout,
+		content: content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+type response struct {
+	Id string `json:"id"`
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("create").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	col, err := h.content.CreateCollection(spanCtx, &content.CreateCollectionRequest{
+		Name: h.name,
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to create collection", slog.String("error", err.Error()))
+		return err
+	}
+
+	enc := json.NewEncoder(h.out)
+	err = enc.Encode(response{
+		Id: col.GetId(),
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write collection id", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
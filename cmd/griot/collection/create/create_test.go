@@ -0,0 +1,111 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the name is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "name", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type createClientFunc func(context.Context, *content.CreateCollectionRequest) (*collectionpb.Collection, error)
+
+func (f createClientFunc) CreateCollection(ctx context.Context, req *content.CreateCollectionRequest) (*collectionpb.Collection, error) {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to create the collection", func(t *testing.T) {
+			createErr := errors.New("failed to create")
+			client := createClientFunc(func(ctx context.Context, req *content.CreateCollectionRequest) (*collectionpb.Collection, error) {
+				return nil, createErr
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, createErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print the collection id", func(t *testing.T) {
+		t.Run("if the collection is successfully created", func(t *testing.T) {
+			var gotReq *content.CreateCollectionRequest
+			client := createClientFunc(func(ctx context.Context, req *content.CreateCollectionRequest) (*collectionpb.Collection, error) {
+				gotReq = req
+				col := &collectionpb.Collection{
+					Id:   ptr.Ref("collection-1"),
+					Name: ptr.Ref(req.Name),
+				}
+				return col, nil
+			})
+
+			var out bytes.Buffer
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				name:    "Season 1",
+				out:     &out,
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "Season 1", gotReq.Name) {
+				return
+			}
+			if !assert.Equal(t, `{"id":"collection-1"}`+"\n", out.String()) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"list",
+		command.Args(args...),
+		command.Short("List collections or the items of a collection"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("collection-id", "", "List the items of this collection, instead of every collection.")
+		}),
+		command.Handle(initListHandler),
+	)
+}
+
+type config struct {
+	Host         string `flag:"content-host"`
+	CollectionId string `flag:"collection-id"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	return nil
+}
+
+type listClient interface {
+	ListCollections(context.Context) (*content.ListCollectionsResponse, error)
+	GetCollection(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	collectionId string
+	out          io.Writer
+
+	content listClient
+}
+
+func initListHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	log := humus.Logger("list")
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:          log,
+		collectionId: cfg.CollectionId,
+		out:          os.Stdout,
+		content:      content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+type collectionSummary struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type item struct {
+	Type  string `json:"type"`
+	Id    string `json:"id"`
+	Order int    `json:"order"`
+}
+
+// Handle prints every collection, or the items of the collection in order,
+// as a single JSON array.
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("list").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	var v any
+	if len(h.collectionId) == 0 {
+		collections, err := h.listCollections(spanCtx)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list collections", slog.String("error", err.Error()))
+			return err
+		}
+		v = collections
+	} else {
+		items, err := h.listItems(spanCtx)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to get collection", slog.String("error", err.Error()))
+			return err
+		}
+		v = items
+	}
+
+	enc := json.NewEncoder(h.out)
+	err := enc.Encode(v)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write list", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (h *handler) listCollections(ctx context.Context) ([]collectionSummary, error) {
+	resp, err := h.content.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]collectionSummary, 0, len(resp.Collections))
+	for _, col := range resp.Collections {
+		collections = append(collections, collectionSummary{
+			Id:   col.GetId(),
+			Name: col.GetName(),
+		})
+	}
+	return collections, nil
+}
+
+func (h *handler) listItems(ctx context.Context) ([]item, error) {
+	col, err := h.content.GetCollection(ctx, &content.GetCollectionRequest{
+		Id: h.collectionId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(col.GetItems()))
+	for i, it := range col.GetItems() {
+		items = append(items, item{
+			Type:  strings.ToLower(it.GetType().String()),
+			Id:    it.GetId(),
+			Order: i + 1,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+type listClientStub struct {
+	listCollections func(context.Context) (*content.ListCollectionsResponse, error)
+	getCollection   func(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+}
+
+func (s listClientStub) ListCollections(ctx context.Context) (*content.ListCollectionsResponse, error) {
+	return s.listCollections(ctx)
+}
+
+func (s listClientStub) GetCollection(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+	return s.getCollection(ctx, req)
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(b []byte) (int, error) {
+	return f(b)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to list the collections", func(t *testing.T) {
+			listErr := errors.New("failed to list")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				content: listClientStub{
+					listCollections: func(ctx context.Context) (*content.ListCollectionsResponse, error) {
+						return nil, listErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, listErr, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to get the collection", func(t *testing.T) {
+			getErr := errors.New("failed to get")
+			h := &handler{
+				log:          slog.New(noop.LogHandler{}),
+				collectionId: "collection-1",
+				content: listClientStub{
+					getCollection: func(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+						return nil, getErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, getErr, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to write the list", func(t *testing.T) {
+			writeErr := errors.New("failed to write")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				out: writerFunc(func(b []byte) (int, error) {
+					return 0, writeErr
+				}),
+				content: listClientStub{
+					listCollections: func(ctx context.Context) (*content.ListCollectionsResponse, error) {
+						return &content.ListCollectionsResponse{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, writeErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print every collection", func(t *testing.T) {
+		t.Run("if no collection id is given", func(t *testing.T) {
+			var out bytes.Buffer
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				out: &out,
+				content: listClientStub{
+					listCollections: func(ctx context.Context) (*content.ListCollectionsResponse, error) {
+						resp := &content.ListCollectionsResponse{
+							Collections: []*collectionpb.Collection{
+								{Id: ptr.Ref("collection-1"), Name: ptr.Ref("Season 1")},
+								{Id: ptr.Ref("collection-2"), Name: ptr.Ref("Naruto")},
+							},
+						}
+						return resp, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, `[{"id":"collection-1","name":"Season 1"},{"id":"collection-2","name":"Naruto"}]`+"\n", out.String()) {
+				return
+			}
+		})
+
+		t.Run("as an empty array if there are none", func(t *testing.T) {
+			var out bytes.Buffer
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				out: &out,
+				content: listClientStub{
+					listCollections: func(ctx context.Context) (*content.ListCollectionsResponse, error) {
+						return &content.ListCollectionsResponse{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "[]\n", out.String()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print the items of the collection in order", func(t *testing.T) {
+		t.Run("if a collection id is given", func(t *testing.T) {
+			var gotReq *content.GetCollectionRequest
+			var out bytes.Buffer
+			h := &handler{
+				log:          slog.New(noop.LogHandler{}),
+				collectionId: "collection-2",
+				out:          &out,
+				content: listClientStub{
+					getCollection: func(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+						gotReq = req
+						col := &collectionpb.Collection{
+							Id: ptr.Ref("collection-2"),
+							Items: []*collectionpb.Item{
+								{Type: collectionpb.ItemType_COLLECTION.Enum(), Id: ptr.Ref("collection-1"), Rank: ptr.Ref("1")},
+								{Type: collectionpb.ItemType_CONTENT.Enum(), Id: ptr.Ref("content-1"), Rank: ptr.Ref("2")},
+							},
+						}
+						return col, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "collection-2", gotReq.Id) {
+				return
+			}
+			if !assert.Equal(t, `[{"type":"collection","id":"collection-1","order":1},{"type":"content","id":"content-1","order":2}]`+"\n", out.String()) {
+				return
+			}
+		})
+	})
+}
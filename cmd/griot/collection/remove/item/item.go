@@ -0,0 +1,122 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package item
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"item",
+		command.Args(args...),
+		command.Short("Remove content or another collection from a collection"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("collection-id", "", "Specify the id of the collection to remove the item from.")
+			fs.String("item-id", "", "Specify the id of the content or collection to remove.")
+		}),
+		command.Handle(initItemHandler),
+	)
+}
+
+type config struct {
+	Host         string `flag:"content-host"`
+	CollectionId string `flag:"collection-id"`
+	ItemId       string `flag:"item-id"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateRequired("collection-id", c.CollectionId),
+		validateRequired("item-id", c.ItemId),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateRequired(name, value string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(value) == 0 {
+			return command.InvalidFlagError{
+				Name:  name,
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+type removeClient interface {
+	RemoveCollectionItem(context.Context, *content.RemoveCollectionItemRequest) error
+}
+
+type handler struct {
+	log *slog.Logger
+
+	collectionId string
+	itemId       string
+
+	content removeClient
+}
+
+func initItemHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	log := humus.Logger("item")
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:          log,
+		collectionId: cfg.CollectionId,
+		itemId:       cfg.ItemId,
+		content:      content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("item").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	err := h.content.RemoveCollectionItem(spanCtx, &content.RemoveCollectionItemRequest{
+		CollectionId: h.collectionId,
+		ItemId:       h.itemId,
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to remove collection item", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rename
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the collection id is not set", func(t *testing.T) {
+			app := New("--name", "Naruto")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "collection-id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the name is not set", func(t *testing.T) {
+			app := New("--collection-id", "collection-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "name", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type renameClientFunc func(context.Context, *content.RenameCollectionRequest) (*collectionpb.Collection, error)
+
+func (f renameClientFunc) RenameCollection(ctx context.Context, req *content.RenameCollectionRequest) (*collectionpb.Collection, error) {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to rename the collection", func(t *testing.T) {
+			renameErr := errors.New("failed to rename")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				content: renameClientFunc(func(ctx context.Context, req *content.RenameCollectionRequest) (*collectionpb.Collection, error) {
+					return nil, renameErr
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, renameErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will rename the collection", func(t *testing.T) {
+		t.Run("to the given name", func(t *testing.T) {
+			var gotReq *content.RenameCollectionRequest
+			h := &handler{
+				log:          slog.New(noop.LogHandler{}),
+				collectionId: "collection-1",
+				name:         "Naruto Season 1",
+				content: renameClientFunc(func(ctx context.Context, req *content.RenameCollectionRequest) (*collectionpb.Collection, error) {
+					gotReq = req
+					return &collectionpb.Collection{}, nil
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "collection-1", gotReq.Id) {
+				return
+			}
+			if !assert.Equal(t, "Naruto Season 1", gotReq.Name) {
+				return
+			}
+		})
+	})
+}
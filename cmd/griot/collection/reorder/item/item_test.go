@@ -0,0 +1,120 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package item
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the collection id is not set", func(t *testing.T) {
+			app := New("--item-id", "content-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "collection-id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the order is negative", func(t *testing.T) {
+			app := New("--collection-id", "collection-1", "--item-id", "content-1", "--order", "-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "order", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNegativeOrder) {
+				return
+			}
+		})
+	})
+}
+
+type reorderClientFunc func(context.Context, *content.ReorderCollectionItemRequest) (*collectionpb.Collection, error)
+
+func (f reorderClientFunc) ReorderCollectionItem(ctx context.Context, req *content.ReorderCollectionItemRequest) (*collectionpb.Collection, error) {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to reorder the item", func(t *testing.T) {
+			reorderErr := errors.New("failed to reorder")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				content: reorderClientFunc(func(ctx context.Context, req *content.ReorderCollectionItemRequest) (*collectionpb.Collection, error) {
+					return nil, reorderErr
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, reorderErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will move the item", func(t *testing.T) {
+		t.Run("to the given order", func(t *testing.T) {
+			var gotReq *content.ReorderCollectionItemRequest
+			h := &handler{
+				log:          slog.New(noop.LogHandler{}),
+				collectionId: "collection-1",
+				itemId:       "content-2",
+				order:        1,
+				content: reorderClientFunc(func(ctx context.Context, req *content.ReorderCollectionItemRequest) (*collectionpb.Collection, error) {
+					gotReq = req
+					return &collectionpb.Collection{}, nil
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "collection-1", gotReq.CollectionId) {
+				return
+			}
+			if !assert.Equal(t, "content-2", gotReq.ItemId) {
+				return
+			}
+			if !assert.Equal(t, uint32(1), gotReq.Position) {
+				return
+			}
+		})
+	})
+}
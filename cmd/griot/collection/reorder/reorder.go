@@ -0,0 +1,28 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reorder
+
+import (
+	"github.com/z5labs/griot/cmd/griot/collection/reorder/item"
+	"github.com/z5labs/griot/internal/command"
+)
+
+func New() *command.App {
+	return command.NewApp(
+		"reorder",
+		command.Short("Move an item within a collection"),
+		command.Sub(item.New()),
+	)
+}
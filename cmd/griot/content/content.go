@@ -15,6 +15,11 @@
 package content
 
 import (
+	"github.com/z5labs/griot/cmd/griot/content/delete"
+	"github.com/z5labs/griot/cmd/griot/content/describe"
+	"github.com/z5labs/griot/cmd/griot/content/download"
+	"github.com/z5labs/griot/cmd/griot/content/id"
+	"github.com/z5labs/griot/cmd/griot/content/list"
 	"github.com/z5labs/griot/cmd/griot/content/upload"
 	"github.com/z5labs/griot/internal/command"
 )
@@ -23,6 +28,11 @@ func New() *command.App {
 	return command.NewApp(
 		"content",
 		command.Short("Manage content"),
+		command.Sub(delete.New()),
+		command.Sub(describe.New()),
+		command.Sub(download.New()),
+		command.Sub(id.New()),
+		command.Sub(list.New()),
 		command.Sub(upload.New()),
 	)
 }
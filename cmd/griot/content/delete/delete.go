@@ -0,0 +1,113 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"delete",
+		command.Args(args...),
+		command.Short("Delete content"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("id", "", "Specify the id of the content to delete.")
+		}),
+		command.Handle(initDeleteHandler),
+	)
+}
+
+type config struct {
+	Host string `flag:"content-host"`
+	Id   string `flag:"id"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateId(c.Id),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateId(id string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(id) == 0 {
+			return command.InvalidFlagError{
+				Name:  "id",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+type deleteClient interface {
+	DeleteContent(context.Context, *content.DeleteContentRequest) error
+}
+
+type handler struct {
+	log *slog.Logger
+
+	id string
+
+	content deleteClient
+}
+
+func initDeleteHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		log:     humus.Logger("delete"),
+		id:      cfg.Id,
+		content: content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("delete").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	err := h.content.DeleteContent(spanCtx, &content.DeleteContentRequest{
+		Id: h.id,
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to delete content", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the id is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type deleteClientFunc func(context.Context, *content.DeleteContentRequest) error
+
+func (f deleteClientFunc) DeleteContent(ctx context.Context, req *content.DeleteContentRequest) error {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to delete the content", func(t *testing.T) {
+			deleteErr := errors.New("failed to delete")
+			client := deleteClientFunc(func(ctx context.Context, req *content.DeleteContentRequest) error {
+				return deleteErr
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, deleteErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will delete the content", func(t *testing.T) {
+		t.Run("with the given id", func(t *testing.T) {
+			var deletedId string
+			client := deleteClientFunc(func(ctx context.Context, req *content.DeleteContentRequest) error {
+				deletedId = req.Id
+				return nil
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				id:      "abc",
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "abc", deletedId) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"describe",
+		command.Args(args...),
+		command.Short("Describe content"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("id", "", "Specify the id of the content to describe.")
+		}),
+		command.Handle(initDescribeHandler),
+	)
+}
+
+type config struct {
+	Host string `flag:"content-host"`
+	Id   string `flag:"id"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateId(c.Id),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateId(id string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(id) == 0 {
+			return command.InvalidFlagError{
+				Name:  "id",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+type describeClient interface {
+	GetContentMetadata(context.Context, *content.GetContentMetadataRequest) (*content.GetContentMetadataResponse, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	id  string
+	out io.Writer
+
+	content describeClient
+}
+
+func initDescribeHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		log:     humus.Logger("describe"),
+		id:      cfg.Id,
+		out:     os.Stdout,
+		content: content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("describe").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	resp, err := h.content.GetContentMetadata(spanCtx, &content.GetContentMetadataRequest{
+		Id: h.id,
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to get content metadata", slog.String("error", err.Error()))
+		return err
+	}
+
+	b, err := protojson.Marshal(resp.Record)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to marshal content metadata", slog.String("error", err.Error()))
+		return err
+	}
+
+	_, err = h.out.Write(append(b, '\n'))
+	return err
+}
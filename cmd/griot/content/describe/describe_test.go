@@ -0,0 +1,116 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the id is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type describeClientFunc func(context.Context, *content.GetContentMetadataRequest) (*content.GetContentMetadataResponse, error)
+
+func (f describeClientFunc) GetContentMetadata(ctx context.Context, req *content.GetContentMetadataRequest) (*content.GetContentMetadataResponse, error) {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to get the content metadata", func(t *testing.T) {
+			getErr := errors.New("failed to get")
+			client := describeClientFunc(func(ctx context.Context, req *content.GetContentMetadataRequest) (*content.GetContentMetadataResponse, error) {
+				return nil, getErr
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, getErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print the content metadata as json", func(t *testing.T) {
+		t.Run("if the content exists", func(t *testing.T) {
+			client := describeClientFunc(func(ctx context.Context, req *content.GetContentMetadataRequest) (*content.GetContentMetadataResponse, error) {
+				resp := &content.GetContentMetadataResponse{
+					Record: &indexpb.Record{
+						ContentId:   &contentpb.ContentId{Value: &req.Id},
+						ContentName: ptr.Ref("hello.txt"),
+					},
+				}
+				return resp, nil
+			})
+
+			var out bytes.Buffer
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				id:      "abc",
+				out:     &out,
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			var record map[string]any
+			err = json.Unmarshal(out.Bytes(), &record)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello.txt", record["contentName"]) {
+				return
+			}
+		})
+	})
+}
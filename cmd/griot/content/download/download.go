@@ -0,0 +1,216 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"download",
+		command.Args(args...),
+		command.Short("Download content"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("id", "", "Specify the id of the content to download.")
+			fs.String("output", "", "Specify the file the content will be written to.")
+		}),
+		command.Handle(initDownloadHandler),
+	)
+}
+
+type config struct {
+	Host   string `flag:"content-host"`
+	Id     string `flag:"id"`
+	Output string `flag:"output"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateId(c.Id),
+		validateOutput(c.Output),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateId(id string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(id) == 0 {
+			return command.InvalidFlagError{
+				Name:  "id",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+func validateOutput(filename string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(filename) == 0 {
+			return command.InvalidFlagError{
+				Name:  "output",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+
+		info, err := os.Stat(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return command.InvalidFlagError{
+				Name:  "output",
+				Cause: err,
+			}
+		}
+		if info.IsDir() {
+			return command.InvalidFlagError{
+				Name:  "output",
+				Cause: command.ErrMustBeAFile,
+			}
+		}
+		return nil
+	}
+}
+
+type downloadClient interface {
+	DownloadContent(context.Context, *content.DownloadContentRequest) (*content.DownloadContentResponse, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	id     string
+	output string
+
+	content downloadClient
+}
+
+func initDownloadHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		log:     humus.Logger("download"),
+		id:      cfg.Id,
+		output:  cfg.Output,
+		content: content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+var ErrNoSupportedChecksum = errors.New("content has no checksum with a supported hash function")
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("download").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	resp, err := h.content.DownloadContent(spanCtx, &content.DownloadContentRequest{
+		Id: h.id,
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to download content", slog.String("error", err.Error()))
+		return err
+	}
+	defer resp.Content.Close()
+
+	r, err := verifyContent(resp)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to verify content", slog.String("error", err.Error()))
+		return err
+	}
+
+	err = writeFile(h.output, r)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write content", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// verifyContent wraps the downloaded content so that it's re-hashed
+// while being written using the first checksum which has a supported
+// hash function.
+func verifyContent(resp *content.DownloadContentResponse) (io.Reader, error) {
+	for _, checksum := range resp.Checksums {
+		r, err := content.NewVerifyingReader(resp.Content, checksum)
+		var uerr content.UnsupportedHashFuncError
+		if errors.As(err, &uerr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	return nil, ErrNoSupportedChecksum
+}
+
+// writeFile writes to a temporary file next to filename and only renames
+// it to filename once all content has been successfully written. This
+// ensures content which fails verification never ends up at filename.
+func writeFile(filename string, r io.Reader) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	err = os.Rename(f.Name(), filename)
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
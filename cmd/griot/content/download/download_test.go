@@ -0,0 +1,209 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the id is not set", func(t *testing.T) {
+			app := New("--output", filepath.Join(t.TempDir(), "test.txt"))
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the output is not set", func(t *testing.T) {
+			app := New("--id", "abc")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "output", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the output is a directory instead of a file", func(t *testing.T) {
+			app := New("--id", "abc", "--output", t.TempDir())
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "output", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrMustBeAFile) {
+				return
+			}
+		})
+	})
+}
+
+type downloadClientFunc func(context.Context, *content.DownloadContentRequest) (*content.DownloadContentResponse, error)
+
+func (f downloadClientFunc) DownloadContent(ctx context.Context, req *content.DownloadContentRequest) (*content.DownloadContentResponse, error) {
+	return f(ctx, req)
+}
+
+func sha256Checksum(s string) *contentpb.Checksum {
+	hash := sha256.Sum256([]byte(s))
+	return &contentpb.Checksum{
+		HashFunc: contentpb.HashFunc_SHA256.Enum(),
+		Hash:     hash[:],
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to download the content", func(t *testing.T) {
+			downloadErr := errors.New("failed to download")
+			client := downloadClientFunc(func(ctx context.Context, req *content.DownloadContentRequest) (*content.DownloadContentResponse, error) {
+				return nil, downloadErr
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				output:  filepath.Join(t.TempDir(), "test.txt"),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, downloadErr, err) {
+				return
+			}
+		})
+
+		t.Run("if the content has no checksum with a supported hash function", func(t *testing.T) {
+			client := downloadClientFunc(func(ctx context.Context, req *content.DownloadContentRequest) (*content.DownloadContentResponse, error) {
+				resp := &content.DownloadContentResponse{
+					Content: io.NopCloser(strings.NewReader("hello world")),
+				}
+				return resp, nil
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				output:  filepath.Join(t.TempDir(), "test.txt"),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.ErrorIs(t, err, ErrNoSupportedChecksum) {
+				return
+			}
+		})
+
+		t.Run("if the content does not match the checksum", func(t *testing.T) {
+			client := downloadClientFunc(func(ctx context.Context, req *content.DownloadContentRequest) (*content.DownloadContentResponse, error) {
+				resp := &content.DownloadContentResponse{
+					Checksums: []*contentpb.Checksum{sha256Checksum("goodbye world")},
+					Content:   io.NopCloser(strings.NewReader("hello world")),
+				}
+				return resp, nil
+			})
+
+			dir := t.TempDir()
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				output:  filepath.Join(dir, "test.txt"),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+
+			var merr content.ChecksumMismatchError
+			if !assert.ErrorAs(t, err, &merr) {
+				return
+			}
+
+			entries, err := os.ReadDir(dir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, entries) {
+				return
+			}
+		})
+	})
+
+	t.Run("will write the content to the output file", func(t *testing.T) {
+		t.Run("if the content matches the checksum", func(t *testing.T) {
+			client := downloadClientFunc(func(ctx context.Context, req *content.DownloadContentRequest) (*content.DownloadContentResponse, error) {
+				resp := &content.DownloadContentResponse{
+					Checksums: []*contentpb.Checksum{
+						{HashFunc: contentpb.HashFunc(-1).Enum()},
+						sha256Checksum("hello world"),
+					},
+					Content: io.NopCloser(strings.NewReader("hello world")),
+				}
+				return resp, nil
+			})
+
+			output := filepath.Join(t.TempDir(), "test.txt")
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				output:  output,
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			b, err := os.ReadFile(output)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package id
+
+import (
+	"context"
+	"encoding/json"
+	"hash"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/contentflag"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"id",
+		command.Args(args...),
+		command.Short("Compute the Content ID of a file without uploading it"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("source-file", "", "Specify the content source file.")
+			contentflag.HashFunc(fs)
+		}),
+		command.Handle(initIdHandler),
+	)
+}
+
+type config struct {
+	SourceFile string `flag:"source-file"`
+	HashFunc   string `flag:"hash-func"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		contentflag.ValidateSourceFile(c.SourceFile),
+		contentflag.ValidateHashFunc(c.HashFunc),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	hashFunc contentpb.HashFunc
+	hasher   hash.Hash
+	src      io.ReadCloser
+	out      io.Writer
+}
+
+func initIdHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	spanCtx, span := otel.Tracer("id").Start(ctx, "initIdHandler")
+	defer span.End()
+
+	log := humus.Logger("id")
+
+	hashFunc, err := content.ParseHashFunc(cfg.HashFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := content.NewHash(hashFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := os.Open(cfg.SourceFile)
+	if err != nil {
+		log.ErrorContext(spanCtx, "failed to open source file", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:      log,
+		hashFunc: hashFunc,
+		hasher:   hasher,
+		src:      src,
+		out:      os.Stdout,
+	}
+	return h, nil
+}
+
+type response struct {
+	Id string `json:"id"`
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("id").Start(ctx, "handler.Handle")
+	defer span.End()
+	defer h.src.Close()
+
+	_, err := io.Copy(h.hasher, h.src)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to compute hash", slog.String("error", err.Error()))
+		return err
+	}
+
+	id := content.NewContentId(&contentpb.Checksum{
+		HashFunc: h.hashFunc.Enum(),
+		Hash:     h.hasher.Sum(nil),
+	})
+
+	enc := json.NewEncoder(h.out)
+	return enc.Encode(response{
+		Id: id.GetValue(),
+	})
+}
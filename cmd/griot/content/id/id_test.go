@@ -0,0 +1,172 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package id
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the source file is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "source-file", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the source file name is a directory instead of a file", func(t *testing.T) {
+			app := New("--source-file", t.TempDir())
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "source-file", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrMustBeAFile) {
+				return
+			}
+		})
+
+		t.Run("if the hash func is set to an unknown value", func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "*")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = f.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			app := New("--source-file", f.Name(), "--hash-func", "SHA")
+			err = app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "hash-func", iferr.Name) {
+				return
+			}
+
+			var uerr content.UnknownHashFuncError
+			if !assert.ErrorAs(t, iferr, &uerr) {
+				return
+			}
+			if !assert.Equal(t, "SHA", uerr.Value) {
+				return
+			}
+		})
+	})
+}
+
+func TestInitIdHandler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to open the source file", func(t *testing.T) {
+			cfg := config{
+				HashFunc:   contentpb.HashFunc_SHA256.String(),
+				SourceFile: filepath.Join(t.TempDir(), "test.txt"),
+			}
+
+			_, err := initIdHandler(context.Background(), cfg)
+
+			var perr *os.PathError
+			if !assert.ErrorAs(t, err, &perr) {
+				return
+			}
+			if !assert.Equal(t, "open", perr.Op) {
+				return
+			}
+		})
+	})
+}
+
+type readFunc func([]byte) (int, error)
+
+func (f readFunc) Read(b []byte) (int, error) {
+	return f(b)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to compute the content hash", func(t *testing.T) {
+			readErr := errors.New("read failed")
+			src := readFunc(func(b []byte) (int, error) {
+				return 0, readErr
+			})
+
+			h := &handler{
+				log:      slog.New(noop.LogHandler{}),
+				hashFunc: contentpb.HashFunc_SHA256,
+				hasher:   sha256.New(),
+				src:      io.NopCloser(src),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, readErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print the content id", func(t *testing.T) {
+		t.Run("if the content hash is computed", func(t *testing.T) {
+			var out bytes.Buffer
+			h := &handler{
+				log:      slog.New(noop.LogHandler{}),
+				hashFunc: contentpb.HashFunc_SHA256,
+				hasher:   sha256.New(),
+				src:      io.NopCloser(strings.NewReader("hello world")),
+				out:      &out,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, `{"id":"fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok="}`+"\n", out.String()) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"list",
+		command.Args(args...),
+		command.Short("List content"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("media-type", "", "Only list content with this media type, e.g. video or video/mp4.")
+			fs.String("name-prefix", "", "Only list content whose name begins with this prefix.")
+			fs.Int("page-size", 0, "Specify how many records are requested at a time.")
+		}),
+		command.Handle(initListHandler),
+	)
+}
+
+type config struct {
+	Host       string `flag:"content-host"`
+	MediaType  string `flag:"media-type"`
+	NamePrefix string `flag:"name-prefix"`
+	PageSize   int    `flag:"page-size"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateMediaType(c.MediaType),
+		validatePageSize(c.PageSize),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+var ErrInvalidMediaType = errors.New("must be formatted as type or type/subtype")
+
+func validateMediaType(mediaType string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(mediaType) == 0 {
+			return nil
+		}
+
+		typ, subtype, found := strings.Cut(mediaType, "/")
+		if typ == "" || (found && (subtype == "" || strings.Contains(subtype, "/"))) {
+			return command.InvalidFlagError{
+				Name:  "media-type",
+				Cause: ErrInvalidMediaType,
+			}
+		}
+		return nil
+	}
+}
+
+var ErrNegativePageSize = errors.New("must not be negative")
+
+func validatePageSize(n int) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if n < 0 {
+			return command.InvalidFlagError{
+				Name:  "page-size",
+				Cause: ErrNegativePageSize,
+			}
+		}
+		return nil
+	}
+}
+
+type listClient interface {
+	ListContent(context.Context, *content.ListContentRequest) iter.Seq2[*indexpb.Record, error]
+}
+
+type handler struct {
+	log *slog.Logger
+
+	mediaType  string
+	namePrefix string
+	pageSize   int
+	out        io.Writer
+
+	content listClient
+}
+
+func initListHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		log:        humus.Logger("list"),
+		mediaType:  cfg.MediaType,
+		namePrefix: cfg.NamePrefix,
+		pageSize:   cfg.PageSize,
+		out:        os.Stdout,
+		content:    content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+// Handle prints every matching record as a single line of JSON.
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("list").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	req := &content.ListContentRequest{
+		MediaType:  h.mediaType,
+		NamePrefix: h.namePrefix,
+		PageSize:   h.pageSize,
+	}
+	for record, err := range h.content.ListContent(spanCtx, req) {
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list content", slog.String("error", err.Error()))
+			return err
+		}
+
+		b, err := protojson.Marshal(record)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to marshal record", slog.String("error", err.Error()))
+			return err
+		}
+
+		_, err = h.out.Write(append(b, '\n'))
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the media type has an empty subtype", func(t *testing.T) {
+			app := New("--media-type", "video/")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "media-type", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrInvalidMediaType) {
+				return
+			}
+		})
+
+		t.Run("if the page size is negative", func(t *testing.T) {
+			app := New("--page-size", "-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "page-size", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNegativePageSize) {
+				return
+			}
+		})
+	})
+}
+
+type listClientFunc func(context.Context, *content.ListContentRequest) iter.Seq2[*indexpb.Record, error]
+
+func (f listClientFunc) ListContent(ctx context.Context, req *content.ListContentRequest) iter.Seq2[*indexpb.Record, error] {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to list the content", func(t *testing.T) {
+			listErr := errors.New("failed to list")
+			client := listClientFunc(func(ctx context.Context, req *content.ListContentRequest) iter.Seq2[*indexpb.Record, error] {
+				return func(yield func(*indexpb.Record, error) bool) {
+					yield(nil, listErr)
+				}
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, listErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print every record as a line of json", func(t *testing.T) {
+		t.Run("if the content is successfully listed", func(t *testing.T) {
+			var gotReq *content.ListContentRequest
+			client := listClientFunc(func(ctx context.Context, req *content.ListContentRequest) iter.Seq2[*indexpb.Record, error] {
+				gotReq = req
+				return func(yield func(*indexpb.Record, error) bool) {
+					for _, name := range []string{"Naruto S01E01", "Naruto S01E02"} {
+						if !yield(&indexpb.Record{ContentName: ptr.Ref(name)}, nil) {
+							return
+						}
+					}
+				}
+			})
+
+			var out bytes.Buffer
+			h := &handler{
+				log:        slog.New(noop.LogHandler{}),
+				mediaType:  "video",
+				namePrefix: "Naruto",
+				out:        &out,
+				content:    client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "video", gotReq.MediaType) {
+				return
+			}
+			if !assert.Equal(t, "Naruto", gotReq.NamePrefix) {
+				return
+			}
+
+			var names []string
+			scanner := bufio.NewScanner(&out)
+			for scanner.Scan() {
+				var record map[string]any
+				err := json.Unmarshal(scanner.Bytes(), &record)
+				if !assert.Nil(t, err) {
+					return
+				}
+				names = append(names, record["contentName"].(string))
+			}
+			if !assert.Equal(t, []string{"Naruto S01E01", "Naruto S01E02"}, names) {
+				return
+			}
+		})
+	})
+}
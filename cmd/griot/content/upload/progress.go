@@ -0,0 +1,307 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+)
+
+const (
+	progressAuto = "auto"
+	progressBar  = "bar"
+	progressJSON = "json"
+	progressNone = "none"
+)
+
+var ErrUnknownProgressMode = errors.New("must be one of auto, bar, json or none")
+
+func validateProgress(mode string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		switch mode {
+		case progressAuto, progressBar, progressJSON, progressNone:
+			return nil
+		default:
+			return command.InvalidFlagError{
+				Name:  "progress",
+				Cause: ErrUnknownProgressMode,
+			}
+		}
+	}
+}
+
+// progressInterval is the minimum time between printing progress of the same file.
+const progressInterval = 250 * time.Millisecond
+
+// newProgressPrinter returns nil if progress shouldn't be printed. In auto
+// mode a progress bar is printed if f is a terminal and otherwise JSON lines.
+func newProgressPrinter(mode string, f *os.File) *progressPrinter {
+	if mode == progressAuto {
+		mode = progressJSON
+		if isTerminal(f) {
+			mode = progressBar
+		}
+	}
+
+	var r progressRenderer
+	switch mode {
+	case progressBar:
+		r = &barRenderer{}
+	case progressJSON:
+		r = jsonRenderer{}
+	default:
+		return nil
+	}
+
+	return &progressPrinter{
+		w:        f,
+		interval: progressInterval,
+		now:      time.Now,
+		renderer: r,
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+type progressEvent struct {
+	SourceFile     string  `json:"source_file"`
+	BytesSent      int64   `json:"bytes_sent"`
+	TotalBytes     int64   `json:"total_bytes,omitempty"`
+	BytesPerSecond float64 `json:"bytes_per_second"`
+	EtaSeconds     int64   `json:"eta_seconds,omitempty"`
+}
+
+type progressRenderer interface {
+	// start is called before the progress of a file is first rendered.
+	start(io.Writer) error
+
+	render(io.Writer, progressEvent) error
+
+	// finish is called with the latest progress once the upload of a file is done.
+	finish(io.Writer, progressEvent) error
+}
+
+// progressPrinter serializes printing the progress of concurrent uploads.
+type progressPrinter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	interval time.Duration
+	now      func() time.Time
+	renderer progressRenderer
+}
+
+// track returns a tracker for the upload progress of a single file.
+func (p *progressPrinter) track(sourceFile string) *progressTracker {
+	if p == nil {
+		return nil
+	}
+	return &progressTracker{
+		printer:    p,
+		sourceFile: sourceFile,
+	}
+}
+
+func (p *progressPrinter) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_ = p.renderer.start(p.w)
+}
+
+func (p *progressPrinter) print(ev progressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// progress is best effort so it's not worth failing the upload for
+	_ = p.renderer.render(p.w, ev)
+}
+
+func (p *progressPrinter) finish(ev progressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_ = p.renderer.finish(p.w, ev)
+}
+
+// progressTracker computes the throughput and ETA of a single upload and
+// throttles how often its progress is printed.
+type progressTracker struct {
+	printer    *progressPrinter
+	sourceFile string
+
+	start      time.Time
+	startBytes int64
+	printed    time.Time
+	last       progressEvent
+	pending    bool
+}
+
+func (t *progressTracker) update(p content.Progress) {
+	now := t.printer.now()
+	if t.start.IsZero() {
+		// resumed uploads start from where they left off so
+		// those bytes shouldn't count towards the throughput
+		t.start = now
+		t.startBytes = p.BytesSent
+		t.printer.start()
+	}
+
+	ev := progressEvent{
+		SourceFile: t.sourceFile,
+		BytesSent:  p.BytesSent,
+		TotalBytes: p.TotalBytes,
+	}
+	if elapsed := now.Sub(t.start); elapsed > 0 {
+		ev.BytesPerSecond = float64(p.BytesSent-t.startBytes) / elapsed.Seconds()
+	}
+	if ev.BytesPerSecond > 0 && p.TotalBytes > 0 {
+		ev.EtaSeconds = int64(math.Ceil(float64(p.TotalBytes-p.BytesSent) / ev.BytesPerSecond))
+	}
+	t.last = ev
+	t.pending = true
+
+	if !t.printed.IsZero() && now.Sub(t.printed) < t.printer.interval {
+		return
+	}
+	t.printed = now
+	t.pending = false
+	t.printer.print(ev)
+}
+
+// finish prints the latest progress, if it was throttled, and ends the upload.
+func (t *progressTracker) finish() {
+	if t.start.IsZero() {
+		return
+	}
+	if t.pending {
+		t.printer.print(t.last)
+	}
+	t.printer.finish(t.last)
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) start(w io.Writer) error {
+	return nil
+}
+
+func (jsonRenderer) render(w io.Writer, ev progressEvent) error {
+	enc := json.NewEncoder(w)
+	return enc.Encode(ev)
+}
+
+func (jsonRenderer) finish(w io.Writer, ev progressEvent) error {
+	return nil
+}
+
+// barWidth is the number of characters in a progress bar.
+const barWidth = 25
+
+// barRenderer redraws the progress bar of a file in place on a single line. Since
+// the bars of concurrent uploads would overwrite each other, no bars are drawn
+// while more than one file is uploading and only the final progress of every file
+// is printed on its own line once it's done.
+type barRenderer struct {
+	inFlight int
+	drawn    bool
+}
+
+func (r *barRenderer) start(w io.Writer) error {
+	r.inFlight++
+	if r.inFlight == 1 || !r.drawn {
+		return nil
+	}
+
+	r.drawn = false
+	_, err := io.WriteString(w, "\r\x1b[K")
+	return err
+}
+
+func (r *barRenderer) render(w io.Writer, ev progressEvent) error {
+	if r.inFlight > 1 {
+		return nil
+	}
+
+	r.drawn = true
+	_, err := io.WriteString(w, "\r\x1b[K"+formatBar(ev))
+	return err
+}
+
+func (r *barRenderer) finish(w io.Writer, ev progressEvent) error {
+	r.inFlight--
+	if r.drawn {
+		r.drawn = false
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+
+	_, err := io.WriteString(w, "\r\x1b[K"+formatBar(ev)+"\n")
+	return err
+}
+
+func formatBar(ev progressEvent) string {
+	rate := formatBytes(ev.BytesPerSecond) + "/s"
+	if ev.TotalBytes <= 0 {
+		return fmt.Sprintf("%s %s %s", ev.SourceFile, formatBytes(float64(ev.BytesSent)), rate)
+	}
+
+	ratio := min(float64(ev.BytesSent)/float64(ev.TotalBytes), 1)
+	filled := int(ratio * barWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+
+	eta := "--"
+	if ev.BytesPerSecond > 0 {
+		eta = (time.Duration(ev.EtaSeconds) * time.Second).String()
+	}
+
+	return fmt.Sprintf(
+		"%s [%s] %5.1f%% %s / %s %s ETA %s",
+		ev.SourceFile,
+		bar,
+		ratio*100,
+		formatBytes(float64(ev.BytesSent)),
+		formatBytes(float64(ev.TotalBytes)),
+		rate,
+		eta,
+	)
+}
+
+// formatBytes formats n bytes using IEC units, e.g. 1.5 MiB.
+func formatBytes(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
@@ -0,0 +1,265 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+// newTestProgressPrinter returns a printer whose clock advances by step every time it's read.
+func newTestProgressPrinter(w io.Writer, r progressRenderer, step time.Duration) *progressPrinter {
+	now := time.Unix(0, 0)
+	return &progressPrinter{
+		w:        w,
+		interval: time.Second,
+		now: func() time.Time {
+			now = now.Add(step)
+			return now
+		},
+		renderer: r,
+	}
+}
+
+func readProgressEvents(t *testing.T, r io.Reader) []progressEvent {
+	t.Helper()
+
+	var events []progressEvent
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var ev progressEvent
+		err := json.Unmarshal(sc.Bytes(), &ev)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestApp_Progress(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the progress mode is unknown", func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "*")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = f.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			app := New("--source-file", f.Name(), "--progress", "loud")
+			err = app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "progress", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrUnknownProgressMode) {
+				return
+			}
+		})
+	})
+}
+
+func TestProgressTracker(t *testing.T) {
+	t.Run("will throttle progress events", func(t *testing.T) {
+		t.Run("if they are reported more often than the interval", func(t *testing.T) {
+			var out bytes.Buffer
+			tracker := newTestProgressPrinter(&out, jsonRenderer{}, 400*time.Millisecond).track("a.txt")
+
+			for sent := int64(100); sent <= 1000; sent += 100 {
+				tracker.update(content.Progress{BytesSent: sent, TotalBytes: 1000})
+			}
+			tracker.finish()
+
+			events := readProgressEvents(t, &out)
+			if !assert.Len(t, events, 4) {
+				return
+			}
+
+			last := events[len(events)-1]
+			if !assert.Equal(t, int64(1000), last.BytesSent) {
+				return
+			}
+			if !assert.Zero(t, last.EtaSeconds) {
+				return
+			}
+		})
+	})
+
+	t.Run("will compute the throughput and eta", func(t *testing.T) {
+		t.Run("excluding bytes sent before the upload was resumed", func(t *testing.T) {
+			var out bytes.Buffer
+			tracker := newTestProgressPrinter(&out, jsonRenderer{}, time.Second).track("a.txt")
+
+			tracker.update(content.Progress{BytesSent: 500, TotalBytes: 1000})
+			tracker.update(content.Progress{BytesSent: 600, TotalBytes: 1000})
+			tracker.finish()
+
+			events := readProgressEvents(t, &out)
+			if !assert.Len(t, events, 2) {
+				return
+			}
+
+			expected := progressEvent{
+				SourceFile:     "a.txt",
+				BytesSent:      600,
+				TotalBytes:     1000,
+				BytesPerSecond: 100,
+				EtaSeconds:     4,
+			}
+			if !assert.Equal(t, expected, events[1]) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print a progress bar", func(t *testing.T) {
+		t.Run("and end the line once the upload is finished", func(t *testing.T) {
+			var out bytes.Buffer
+			tracker := newTestProgressPrinter(&out, &barRenderer{}, time.Second).track("a.txt")
+
+			tracker.update(content.Progress{BytesSent: 0, TotalBytes: 2048})
+			tracker.update(content.Progress{BytesSent: 1024, TotalBytes: 2048})
+			tracker.finish()
+
+			lines := strings.Split(out.String(), "\r\x1b[K")
+			if !assert.Len(t, lines, 3) {
+				return
+			}
+			if !assert.Equal(t, "a.txt [============             ]  50.0% 1.0 KiB / 2.0 KiB 1.0 KiB/s ETA 1s\n", lines[2]) {
+				return
+			}
+		})
+
+		t.Run("on its own line for every file if files are uploaded concurrently", func(t *testing.T) {
+			var out bytes.Buffer
+			printer := newTestProgressPrinter(&out, &barRenderer{}, time.Second)
+			a := printer.track("a.txt")
+			b := printer.track("b.txt")
+
+			a.update(content.Progress{BytesSent: 0, TotalBytes: 2048})
+			b.update(content.Progress{BytesSent: 0, TotalBytes: 1024})
+			a.update(content.Progress{BytesSent: 1024, TotalBytes: 2048})
+			b.update(content.Progress{BytesSent: 512, TotalBytes: 1024})
+			a.update(content.Progress{BytesSent: 2048, TotalBytes: 2048})
+			a.finish()
+			b.update(content.Progress{BytesSent: 1024, TotalBytes: 1024})
+			b.finish()
+
+			lines := terminalLines(out.String())
+			if !assert.Len(t, lines, 2) {
+				return
+			}
+			for i, file := range []string{"a.txt", "b.txt"} {
+				for _, draw := range lines[i] {
+					if !assert.True(t, strings.HasPrefix(draw, file+" "), "expected line %d to only show %s: %q", i, file, draw) {
+						return
+					}
+				}
+				last := lines[i][len(lines[i])-1]
+				if !assert.Contains(t, last, "[=========================] 100.0%") {
+					return
+				}
+			}
+		})
+	})
+}
+
+// terminalLines splits the output into its lines and every line into what was drawn
+// on it, i.e. the text following every carriage return and erase in the line.
+func terminalLines(out string) [][]string {
+	var lines [][]string
+	for _, line := range strings.SplitAfter(out, "\n") {
+		if line == "" {
+			continue
+		}
+		var draws []string
+		for _, draw := range strings.Split(line, "\r\x1b[K") {
+			if draw != "" {
+				draws = append(draws, draw)
+			}
+		}
+		lines = append(lines, draws)
+	}
+	return lines
+}
+
+func TestHandler_Handle_Progress(t *testing.T) {
+	t.Run("will print the upload progress", func(t *testing.T) {
+		t.Run("if a progress tracker is set", func(t *testing.T) {
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				if !assert.Equal(t, int64(len("hello world")), req.Size) {
+					return nil, errors.New("unexpected size")
+				}
+				if !assert.NotNil(t, req.OnProgress) {
+					return nil, errors.New("missing progress func")
+				}
+				req.OnProgress(content.Progress{BytesSent: req.Size, TotalBytes: req.Size})
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			var progress bytes.Buffer
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("hello world"),
+				},
+				out:      io.Discard,
+				content:  client,
+				progress: newTestProgressPrinter(&progress, jsonRenderer{}, time.Second).track("hello.txt"),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			events := readProgressEvents(t, &progress)
+			if !assert.Len(t, events, 1) {
+				return
+			}
+			if !assert.Equal(t, "hello.txt", events[0].SourceFile) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), events[0].BytesSent) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,272 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"go.opentelemetry.io/otel"
+	"golang.org/x/sync/errgroup"
+)
+
+var ErrNotAllowedWithSourceDir = errors.New("not allowed with source-dir")
+
+func validateSourceDir(dir, filename, name string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(dir) == 0 {
+			return nil
+		}
+		if len(filename) > 0 {
+			return command.InvalidFlagError{
+				Name:  "source-file",
+				Cause: ErrNotAllowedWithSourceDir,
+			}
+		}
+		if len(name) > 0 {
+			// names are derived from the file paths instead
+			return command.InvalidFlagError{
+				Name:  "name",
+				Cause: ErrNotAllowedWithSourceDir,
+			}
+		}
+
+		info, err := os.Stat(dir)
+		if err != nil {
+			return command.InvalidFlagError{
+				Name:  "source-dir",
+				Cause: err,
+			}
+		}
+		if !info.IsDir() {
+			return command.InvalidFlagError{
+				Name:  "source-dir",
+				Cause: command.ErrMustBeADir,
+			}
+		}
+		return nil
+	}
+}
+
+func validatePatterns(flag string, patterns []string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		for _, pattern := range patterns {
+			_, err := path.Match(pattern, "")
+			if err != nil {
+				return command.InvalidFlagError{
+					Name:  flag,
+					Cause: err,
+				}
+			}
+		}
+		return nil
+	}
+}
+
+var ErrParallelNotPositive = errors.New("must be at least 1")
+
+func validateParallel(n int) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if n < 1 {
+			return command.InvalidFlagError{
+				Name:  "parallel",
+				Cause: ErrParallelNotPositive,
+			}
+		}
+		return nil
+	}
+}
+
+// UploadFailuresError is returned once every file in the source
+// directory has been attempted if any of them failed to upload.
+type UploadFailuresError struct {
+	Failed int
+	Total  int
+}
+
+func (e UploadFailuresError) Error() string {
+	return fmt.Sprintf("failed to upload %d of %d files", e.Failed, e.Total)
+}
+
+// fileResult is written as a JSON line for every file in the source directory.
+type fileResult struct {
+	SourceFile string `json:"source_file"`
+	Id         string `json:"id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type dirHandler struct {
+	log *slog.Logger
+
+	sourceDir string
+	include   []string
+	exclude   []string
+	parallel  int
+	out       io.Writer
+	progress  *progressPrinter
+
+	// file is copied for uploading each file in sourceDir. Its
+	// hasher is replaced with a new one using hashFunc.
+	hashFunc contentpb.HashFunc
+	file     handler
+
+	mu     sync.Mutex
+	total  int
+	failed int
+}
+
+func (h *dirHandler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("upload").Start(ctx, "dirHandler.Handle")
+	defer span.End()
+
+	var g errgroup.Group
+	g.SetLimit(h.parallel)
+
+	err := filepath.WalkDir(h.sourceDir, func(filename string, d fs.DirEntry, err error) error {
+		if ctxErr := spanCtx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d == nil {
+			// the source dir itself couldn't be read
+			return err
+		}
+
+		rel, relErr := filepath.Rel(h.sourceDir, filename)
+		if relErr != nil {
+			return relErr
+		}
+		name := filepath.ToSlash(rel)
+		if err != nil {
+			h.report(spanCtx, name, nil, err)
+			return nil
+		}
+		if d.IsDir() {
+			if name != "." && matchAny(h.exclude, name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			h.log.DebugContext(spanCtx, "skipping non-regular file", slog.String("source_file", name))
+			return nil
+		}
+		if !h.included(name) {
+			return nil
+		}
+
+		g.Go(func() error {
+			resp, err := h.uploadFile(spanCtx, name)
+			h.report(spanCtx, name, resp, err)
+			return nil
+		})
+		return nil
+	})
+	g.Wait()
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to walk source directory", slog.String("error", err.Error()))
+		return err
+	}
+	if h.failed > 0 {
+		err = UploadFailuresError{
+			Failed: h.failed,
+			Total:  h.total,
+		}
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// included reports whether the file with the given slash separated
+// path, relative to the source directory, should be uploaded.
+func (h *dirHandler) included(name string) bool {
+	if matchAny(h.exclude, name) {
+		return false
+	}
+	return len(h.include) == 0 || matchAny(h.include, name)
+}
+
+// matchAny reports whether any of the patterns match either the
+// whole name or just its base name, so "*.mkv" matches nested files.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *dirHandler) uploadFile(ctx context.Context, name string) (*content.UploadContentResponse, error) {
+	contentHash, err := content.NewHash(h.hashFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := filepath.Join(h.sourceDir, filepath.FromSlash(name))
+	src, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fh := h.file
+	fh.log = h.log.With(slog.String("source_file", name))
+	fh.contentName = name
+	fh.hasher = funcHasher{Hash: contentHash, hashFunc: h.hashFunc}
+	fh.src = src
+	fh.sourceFile = filename
+	fh.progress = h.progress.track(name)
+	return fh.upload(ctx)
+}
+
+// report writes the result of uploading a single file as a JSON line.
+func (h *dirHandler) report(ctx context.Context, name string, resp *content.UploadContentResponse, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.total++
+	result := fileResult{
+		SourceFile: name,
+	}
+	if err != nil {
+		h.failed++
+		result.Error = err.Error()
+	} else {
+		result.Id = resp.Id
+	}
+
+	enc := json.NewEncoder(h.out)
+	encErr := enc.Encode(result)
+	if encErr != nil {
+		h.log.ErrorContext(ctx, "failed to write upload result", slog.String("error", encErr.Error()))
+	}
+}
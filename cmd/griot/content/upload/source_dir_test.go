@@ -0,0 +1,295 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func writeSourceDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, data := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(filename), 0o755)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		err = os.WriteFile(filename, []byte(data), 0o644)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+	return dir
+}
+
+func readResults(t *testing.T, r io.Reader) []fileResult {
+	t.Helper()
+
+	var results []fileResult
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var result fileResult
+		err := json.Unmarshal(sc.Bytes(), &result)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].SourceFile < results[j].SourceFile
+	})
+	return results
+}
+
+func TestApp_SourceDir(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the source file is also set", func(t *testing.T) {
+			dir := writeSourceDir(t, map[string]string{
+				"a.txt": "a",
+			})
+
+			app := New("--source-dir", dir, "--source-file", filepath.Join(dir, "a.txt"))
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "source-file", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNotAllowedWithSourceDir) {
+				return
+			}
+		})
+
+		t.Run("if the name is also set", func(t *testing.T) {
+			app := New("--source-dir", t.TempDir(), "--name", "hello")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "name", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNotAllowedWithSourceDir) {
+				return
+			}
+		})
+
+		t.Run("if the source dir is a file instead of a directory", func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "*")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = f.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			app := New("--source-dir", f.Name())
+			err = app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "source-dir", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrMustBeADir) {
+				return
+			}
+		})
+
+		t.Run("if an include pattern is malformed", func(t *testing.T) {
+			app := New("--source-dir", t.TempDir(), "--include", "*.mkv", "--include", "[")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "include", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, path.ErrBadPattern) {
+				return
+			}
+		})
+
+		t.Run("if parallel is less than 1", func(t *testing.T) {
+			app := New("--source-dir", t.TempDir(), "--parallel", "0")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "parallel", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrParallelNotPositive) {
+				return
+			}
+		})
+	})
+}
+
+func TestDirHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if any file fails to upload", func(t *testing.T) {
+			dir := writeSourceDir(t, map[string]string{
+				"a.txt":     "a",
+				"bad.txt":   "bad",
+				"sub/c.txt": "c",
+			})
+
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				if req.Metadata.GetName() == "bad.txt" {
+					return nil, errors.New("failed")
+				}
+				return &content.UploadContentResponse{Id: req.Metadata.GetName()}, nil
+			})
+
+			var out bytes.Buffer
+			h := &dirHandler{
+				log:       slog.New(noop.LogHandler{}),
+				sourceDir: dir,
+				parallel:  2,
+				out:       &out,
+				hashFunc:  contentpb.HashFunc_SHA256,
+				file: handler{
+					log:     slog.New(noop.LogHandler{}),
+					content: client,
+				},
+			}
+
+			err := h.Handle(context.Background())
+
+			var uerr UploadFailuresError
+			if !assert.ErrorAs(t, err, &uerr) {
+				return
+			}
+			if !assert.Equal(t, UploadFailuresError{Failed: 1, Total: 3}, uerr) {
+				return
+			}
+
+			expected := []fileResult{
+				{SourceFile: "a.txt", Id: "a.txt"},
+				{SourceFile: "bad.txt", Error: "failed"},
+				{SourceFile: "sub/c.txt", Id: "sub/c.txt"},
+			}
+			if !assert.Equal(t, expected, readResults(t, &out)) {
+				return
+			}
+		})
+	})
+
+	t.Run("will upload every included file", func(t *testing.T) {
+		t.Run("if it does not match any exclude pattern", func(t *testing.T) {
+			dir := writeSourceDir(t, map[string]string{
+				"show/s01/e01.mkv":   "e01",
+				"show/s01/e01.nfo":   "nfo",
+				"show/s01/e02.mkv":   "e02",
+				"show/extras/b.mkv":  "extra",
+				"movie.mkv":          "movie",
+				".cache/thumb.mkv":   "thumb",
+				"show/s01/.hidden":   "hidden",
+				"show/s01/notes.txt": "notes",
+			})
+
+			var mu sync.Mutex
+			uploaded := make(map[string]string)
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				b, err := io.ReadAll(req.Content)
+				if err != nil {
+					return nil, err
+				}
+				hash := sha256.Sum256(b)
+				if !assert.Equal(t, hash[:], req.Metadata.GetChecksum().GetHash()) {
+					return nil, errors.New("unexpected checksum")
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				uploaded[req.Metadata.GetName()] = string(b)
+				return &content.UploadContentResponse{Id: string(b)}, nil
+			})
+
+			var out bytes.Buffer
+			h := &dirHandler{
+				log:       slog.New(noop.LogHandler{}),
+				sourceDir: dir,
+				include:   []string{"*.mkv"},
+				exclude:   []string{".cache", "extras"},
+				parallel:  4,
+				out:       &out,
+				hashFunc:  contentpb.HashFunc_SHA256,
+				file: handler{
+					log:     slog.New(noop.LogHandler{}),
+					content: client,
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			expectedUploads := map[string]string{
+				"movie.mkv":        "movie",
+				"show/s01/e01.mkv": "e01",
+				"show/s01/e02.mkv": "e02",
+			}
+			if !assert.Equal(t, expectedUploads, uploaded) {
+				return
+			}
+
+			expected := []fileResult{
+				{SourceFile: "movie.mkv", Id: "movie"},
+				{SourceFile: "show/s01/e01.mkv", Id: "e01"},
+				{SourceFile: "show/s01/e02.mkv", Id: "e02"},
+			}
+			if !assert.Equal(t, expected, readResults(t, &out)) {
+				return
+			}
+		})
+	})
+}
@@ -15,21 +15,23 @@
 package upload
 
 import (
+	"bufio"
 	"context"
-	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
+	"io/fs"
 	"log/slog"
-	"maps"
-	"mime"
 	"net/http"
 	"os"
-	"slices"
-	"strings"
+	"path/filepath"
 
 	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/contentflag"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/internal/mediatype"
 	"github.com/z5labs/griot/services/content"
 	"github.com/z5labs/griot/services/content/contentpb"
 
@@ -47,34 +49,50 @@ func New(args ...string) *command.App {
 		command.Flags(func(fs *pflag.FlagSet) {
 			fs.String("content-host", "", "Specify the host for reaching griot.")
 			fs.String("name", "", "Provide an optional name to help identify this content later.")
-			fs.String("media-type", "", "Specify the content Media Type.")
-			fs.String("source-file", "", "Specify the content source file.")
-			fs.String(
-				"hash-func",
-				contentpb.HashFunc_SHA256.String(),
-				fmt.Sprintf(
-					"Specify hash function used for calculating content checksum. (values %s)",
-					strings.Join(slices.Collect(maps.Values(contentpb.HashFunc_name)), ","),
-				),
-			)
+			fs.String("media-type", "", "Specify the content Media Type. If omitted, it's detected from the content and source file extension.")
+			fs.String("media-type-map", "", "Specify a mime.types formatted file mapping file extensions to Media Types. (default <user config dir>/griot/mime.types)")
+			fs.String("source-file", "", "Specify the content source file. Use - to read from stdin.")
+			fs.String("source-dir", "", "Specify a directory to recursively upload every file in. Each file is named after its path relative to the directory.")
+			fs.StringSlice("include", nil, "Only upload files in the source directory whose relative path or base name matches one of these globs.")
+			fs.StringSlice("exclude", nil, "Skip files and directories in the source directory whose relative path or base name matches one of these globs.")
+			fs.Int("parallel", 1, "Specify how many files in the source directory are uploaded concurrently.")
+			contentflag.HashFunc(fs)
+			fs.Bool("skip-existing", false, "Skip uploading the content if content with the same checksum already exists.")
+			fs.Bool("resume", false, "Upload the content through a resumable upload session, resuming a previously interrupted upload if there is one.")
+			fs.String("progress", progressAuto, "Specify how upload progress is printed to stderr. auto prints a progress bar if stderr is a terminal and otherwise JSON lines. (values auto,bar,json,none)")
 		}),
 		command.Handle(initUploadHandler),
 	)
 }
 
 type config struct {
-	Host       string `flag:"content-host"`
-	Name       string `flag:"name"`
-	MediaType  string `flag:"media-type"`
-	SourceFile string `flag:"source-file"`
-	HashFunc   string `flag:"hash-func"`
+	Host         string   `flag:"content-host"`
+	Name         string   `flag:"name"`
+	MediaType    string   `flag:"media-type"`
+	MediaTypeMap string   `flag:"media-type-map"`
+	SourceFile   string   `flag:"source-file"`
+	SourceDir    string   `flag:"source-dir"`
+	Include      []string `flag:"include"`
+	Exclude      []string `flag:"exclude"`
+	Parallel     int      `flag:"parallel"`
+	HashFunc     string   `flag:"hash-func"`
+	SkipExisting bool     `flag:"skip-existing"`
+	Resume       bool     `flag:"resume"`
+	Progress     string   `flag:"progress"`
 }
 
 func (c config) Validate(ctx context.Context) error {
 	validators := []command.Validator{
 		validateMediaType(c.MediaType),
-		validateSourceFile(c.SourceFile),
-		validateHashFunc(c.HashFunc),
+		validateSourceFile(c.SourceFile, c.SourceDir),
+		validateSourceDir(c.SourceDir, c.SourceFile, c.Name),
+		validatePatterns("include", c.Include),
+		validatePatterns("exclude", c.Exclude),
+		validateParallel(c.Parallel),
+		contentflag.ValidateHashFunc(c.HashFunc),
+		validateRequiresRegularFile("skip-existing", c.SkipExisting, c.SourceFile),
+		validateRequiresRegularFile("resume", c.Resume, c.SourceFile),
+		validateProgress(c.Progress),
 	}
 
 	return command.ValidateAll(ctx, validators...)
@@ -83,12 +101,10 @@ func (c config) Validate(ctx context.Context) error {
 func validateMediaType(mediaType string) command.ValidatorFunc {
 	return func(ctx context.Context) error {
 		if len(mediaType) == 0 {
-			return command.InvalidFlagError{
-				Name:  "media-type",
-				Cause: command.ErrFlagRequired,
-			}
+			// it will be detected instead
+			return nil
 		}
-		_, _, err := mime.ParseMediaType(mediaType)
+		_, err := contentpb.ParseMediaType(mediaType)
 		if err != nil {
 			return command.InvalidFlagError{
 				Name:  "media-type",
@@ -99,59 +115,51 @@ func validateMediaType(mediaType string) command.ValidatorFunc {
 	}
 }
 
-func validateSourceFile(filename string) command.ValidatorFunc {
+func validateSourceFile(filename, dir string) command.ValidatorFunc {
 	return func(ctx context.Context) error {
 		if len(filename) == 0 {
-			return command.InvalidFlagError{
-				Name:  "source-file",
-				Cause: command.ErrFlagRequired,
+			if len(dir) > 0 {
+				return nil
 			}
-		}
-
-		info, err := os.Stat(filename)
-		if err != nil {
 			return command.InvalidFlagError{
 				Name:  "source-file",
-				Cause: err,
+				Cause: command.ErrFlagRequired,
 			}
 		}
-		if info.IsDir() {
-			return command.InvalidFlagError{
-				Name:  "source-file",
-				Cause: command.ErrMustBeAFile,
-			}
+		if filename == stdinSourceFile {
+			return nil
 		}
-		return nil
+		return contentflag.ValidateSourceFile(filename)(ctx)
 	}
 }
 
-type UnknownHashFuncError struct {
-	Value string
-}
+// stdinSourceFile is the source file name which means to read the content from stdin.
+const stdinSourceFile = "-"
 
-func (e UnknownHashFuncError) Error() string {
-	return fmt.Sprintf("unknown hash func value: %s", e.Value)
+// isSinglePass reports whether the source file can only be read once, e.g.
+// stdin or a named pipe, which means its checksum has to be computed while
+// it's uploaded instead of beforehand.
+func isSinglePass(filename string) bool {
+	if filename == stdinSourceFile {
+		return true
+	}
+	info, err := os.Stat(filename)
+	return err == nil && !info.Mode().IsRegular()
 }
 
-func validateHashFunc(name string) command.ValidatorFunc {
+var ErrRequiresRegularFile = errors.New("requires the source file to be a regular file")
+
+// validateRequiresRegularFile checks flags which need the checksum
+// before uploading aren't enabled for single pass source files.
+func validateRequiresRegularFile(name string, enabled bool, filename string) command.ValidatorFunc {
 	return func(ctx context.Context) error {
-		if len(name) == 0 {
-			return command.InvalidFlagError{
-				Name:  "hash-func",
-				Cause: command.ErrFlagRequired,
-			}
+		if !enabled || !isSinglePass(filename) {
+			return nil
 		}
-
-		_, found := contentpb.HashFunc_value[name]
-		if !found {
-			return command.InvalidFlagError{
-				Name: "hash-func",
-				Cause: UnknownHashFuncError{
-					Value: name,
-				},
-			}
+		return command.InvalidFlagError{
+			Name:  name,
+			Cause: ErrRequiresRegularFile,
 		}
-		return nil
 	}
 }
 
@@ -159,18 +167,28 @@ type uploadClient interface {
 	UploadContent(context.Context, *content.UploadContentRequest) (*content.UploadContentResponse, error)
 }
 
+type contentExistsClient interface {
+	ContentExists(context.Context, *content.ContentExistsRequest) (*content.ContentExistsResponse, error)
+}
+
+type resumableUploadClient interface {
+	ResumableUploadContent(context.Context, *content.ResumableUploadContentRequest) (*content.UploadContentResponse, error)
+}
+
 type hasher interface {
 	hash.Hash
 
 	HashFunc() contentpb.HashFunc
 }
 
-type sha256Hasher struct {
+type funcHasher struct {
 	hash.Hash
+
+	hashFunc contentpb.HashFunc
 }
 
-func (sha256Hasher) HashFunc() contentpb.HashFunc {
-	return contentpb.HashFunc_SHA256
+func (h funcHasher) HashFunc() contentpb.HashFunc {
+	return h.hashFunc
 }
 
 type handler struct {
@@ -182,7 +200,28 @@ type handler struct {
 	src         io.ReadSeekCloser
 	out         io.Writer
 
+	// sourceFile and detector are used for detecting the media type
+	// of the content, or warning if it differs from mediaType.
+	sourceFile string
+	detector   *mediatype.Detector
+
+	// singlePass means src can't be seeked so its checksum is
+	// computed while it's uploaded instead of beforehand.
+	singlePass bool
+
 	content uploadClient
+
+	// existing is only set if uploads of content
+	// which already exists should be skipped.
+	existing contentExistsClient
+
+	// resumeDir is where the upload session ids of resumable uploads are
+	// kept so an interrupted upload can be resumed by a later invocation.
+	resumeDir string
+	resumable resumableUploadClient
+
+	// progress is only set if the upload progress should be printed.
+	progress *progressTracker
 }
 
 func initUploadHandler(ctx context.Context, cfg config) (command.Handler, error) {
@@ -191,24 +230,19 @@ func initUploadHandler(ctx context.Context, cfg config) (command.Handler, error)
 
 	log := humus.Logger("upload")
 
-	hashFunc, exists := contentpb.HashFunc_value[cfg.HashFunc]
-	if !exists {
-		return nil, UnknownHashFuncError{
-			Value: cfg.HashFunc,
-		}
+	hashFunc, err := content.ParseHashFunc(cfg.HashFunc)
+	if err != nil {
+		return nil, err
 	}
 
-	var contentHasher hasher
-	switch contentpb.HashFunc(hashFunc) {
-	case contentpb.HashFunc_SHA256:
-		contentHasher = sha256Hasher{Hash: sha256.New()}
-	default:
-		return nil, fmt.Errorf("unsupported hash function: %s", cfg.HashFunc)
+	contentHash, err := content.NewHash(hashFunc)
+	if err != nil {
+		return nil, err
 	}
 
-	src, err := os.Open(cfg.SourceFile)
+	detector, err := newDetector(cfg.MediaTypeMap)
 	if err != nil {
-		log.ErrorContext(spanCtx, "failed to open source file", slog.String("error", err.Error()))
+		log.ErrorContext(spanCtx, "failed to load media type mappings", slog.String("error", err.Error()))
 		return nil, err
 	}
 
@@ -216,18 +250,87 @@ func initUploadHandler(ctx context.Context, cfg config) (command.Handler, error)
 		Transport: otelhttp.NewTransport(http.DefaultTransport),
 	}
 
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(spanCtx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	client := content.NewClient(hc, cfg.Host, opts...)
+
 	h := &handler{
 		log:         log,
 		contentName: cfg.Name,
 		mediaType:   cfg.MediaType,
-		hasher:      contentHasher,
-		src:         src,
+		hasher:      funcHasher{Hash: contentHash, hashFunc: hashFunc},
 		out:         os.Stdout,
-		content:     content.NewClient(hc, cfg.Host),
+		sourceFile:  cfg.SourceFile,
+		detector:    detector,
+		singlePass:  isSinglePass(cfg.SourceFile),
+		content:     client,
+	}
+	if cfg.SkipExisting {
+		h.existing = client
+	}
+	if cfg.Resume {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			log.ErrorContext(spanCtx, "failed to find user cache directory", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		h.resumeDir = filepath.Join(cacheDir, "griot", "uploads")
+		h.resumable = client
+	}
+
+	progress := newProgressPrinter(cfg.Progress, os.Stderr)
+	if cfg.SourceDir != "" {
+		return &dirHandler{
+			log:       log,
+			sourceDir: cfg.SourceDir,
+			include:   cfg.Include,
+			exclude:   cfg.Exclude,
+			parallel:  cfg.Parallel,
+			out:       os.Stdout,
+			progress:  progress,
+			hashFunc:  hashFunc,
+			file:      *h,
+		}, nil
+	}
+
+	h.progress = progress.track(cfg.SourceFile)
+
+	h.src = os.Stdin
+	if cfg.SourceFile != stdinSourceFile {
+		h.src, err = os.Open(cfg.SourceFile)
+		if err != nil {
+			log.ErrorContext(spanCtx, "failed to open source file", slog.String("error", err.Error()))
+			return nil, err
+		}
 	}
 	return h, nil
 }
 
+// newDetector loads the user's media type mappings. If no mapping file is
+// given, the default one is only loaded if it exists.
+func newDetector(mappingFile string) (*mediatype.Detector, error) {
+	detector := mediatype.NewDetector()
+	if mappingFile != "" {
+		return detector, detector.LoadMappingFile(mappingFile)
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return detector, nil
+	}
+
+	err = detector.LoadMappingFile(filepath.Join(configDir, "griot", "mime.types"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return detector, nil
+}
+
 type FailedToSeekReadBytesError struct {
 	BytesRead   int64
 	BytesSeeked int64
@@ -240,56 +343,222 @@ func (e FailedToSeekReadBytesError) Error() string {
 func (h *handler) Handle(ctx context.Context) error {
 	spanCtx, span := otel.Tracer("upload").Start(ctx, "handler.Handle")
 	defer span.End()
-	defer h.src.Close()
 
-	bytesRead, err := io.Copy(h.hasher, h.src)
+	resp, err := h.upload(spanCtx)
 	if err != nil {
 		span.RecordError(err)
-		h.log.ErrorContext(spanCtx, "failed to compute hash", slog.String("error", err.Error()))
 		return err
 	}
 
-	bytesSeeked, err := h.src.Seek(0, 0)
+	enc := json.NewEncoder(h.out)
+	return enc.Encode(resp)
+}
+
+// upload uploads the content of src, unless skipping existing content is
+// enabled and the content already exists, in which case its id is returned.
+func (h *handler) upload(ctx context.Context) (*content.UploadContentResponse, error) {
+	spanCtx, span := otel.Tracer("upload").Start(ctx, "handler.upload")
+	defer span.End()
+	defer h.src.Close()
+
+	checksum := &contentpb.Checksum{
+		HashFunc: h.hasher.HashFunc().Enum(),
+	}
+	var size int64
+	if !h.singlePass {
+		bytesRead, err := io.Copy(h.hasher, h.src)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to compute hash", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		bytesSeeked, err := h.src.Seek(0, io.SeekCurrent)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to perform seek on the source file", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if bytesRead != bytesSeeked {
+			err = FailedToSeekReadBytesError{
+				BytesRead:   bytesRead,
+				BytesSeeked: bytesSeeked,
+			}
+
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to seek to the start of the source file", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		_, err = h.src.Seek(0, io.SeekStart)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to perform seek on the source file", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		checksum.Hash = h.hasher.Sum(nil)
+		size = bytesRead
+	}
+
+	declaredMediaType, src, err := h.detectMediaType(spanCtx)
 	if err != nil {
 		span.RecordError(err)
-		h.log.ErrorContext(spanCtx, "failed to perform seek on the source file", slog.String("error", err.Error()))
-		return err
+		h.log.ErrorContext(spanCtx, "failed to detect media type", slog.String("error", err.Error()))
+		return nil, err
 	}
-	if bytesRead != bytesSeeked {
-		err = FailedToSeekReadBytesError{
-			BytesRead:   bytesRead,
-			BytesSeeked: bytesSeeked,
-		}
 
+	mediaType, err := contentpb.ParseMediaType(declaredMediaType)
+	if err != nil {
 		span.RecordError(err)
-		h.log.ErrorContext(spanCtx, "failed to seek to the start of the source file", slog.String("error", err.Error()))
-		return err
+		h.log.ErrorContext(spanCtx, "failed to parse media type", slog.String("error", err.Error()))
+		return nil, err
 	}
 
-	mediaType, params, _ := mime.ParseMediaType(h.mediaType)
+	meta := &contentpb.Metadata{
+		Name:      &h.contentName,
+		MediaType: mediaType,
+		Checksum:  checksum,
+	}
 
-	req := &content.UploadContentRequest{
-		Metadata: &contentpb.Metadata{
-			Name: &h.contentName,
-			MediaType: &contentpb.MediaType{
-				Type:       &mediaType,
-				Parameters: params,
-			},
-			Checksum: &contentpb.Checksum{
-				HashFunc: h.hasher.HashFunc().Enum(),
-				Hash:     h.hasher.Sum(nil),
-			},
-		},
-		Content: h.src,
+	if h.existing != nil {
+		existsResp, err := h.existing.ContentExists(spanCtx, &content.ContentExistsRequest{
+			Checksum: meta.Checksum,
+		})
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to check if content exists", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if existsResp.Exists {
+			h.log.InfoContext(spanCtx, "skipping upload of existing content", slog.String("content_id", existsResp.Id))
+
+			return &content.UploadContentResponse{
+				Id: existsResp.Id,
+			}, nil
+		}
+	}
+
+	var onProgress func(content.Progress)
+	if h.progress != nil {
+		onProgress = h.progress.update
+		defer h.progress.finish()
 	}
 
-	resp, err := h.content.UploadContent(spanCtx, req)
+	var resp *content.UploadContentResponse
+	if h.resumable != nil {
+		resp, err = h.resumableUpload(spanCtx, meta, onProgress)
+	} else {
+		req := &content.UploadContentRequest{
+			Metadata:   meta,
+			Content:    src,
+			Size:       size,
+			OnProgress: onProgress,
+		}
+		if !h.singlePass {
+			// the source can be read again so failed uploads can be retried
+			req.OpenContent = h.reopenSource
+		}
+		resp, err = h.content.UploadContent(spanCtx, req)
+	}
 	if err != nil {
 		span.RecordError(err)
 		h.log.ErrorContext(spanCtx, "failed to upload content", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return resp, nil
+}
+
+// resumableUpload uploads the content through an upload session whose id is
+// kept in a state file named after the content checksum. If a previous
+// upload of the same content was interrupted then its session is resumed.
+func (h *handler) resumableUpload(ctx context.Context, meta *contentpb.Metadata, onProgress func(content.Progress)) (*content.UploadContentResponse, error) {
+	spanCtx, span := otel.Tracer("upload").Start(ctx, "handler.resumableUpload")
+	defer span.End()
+
+	checksum := meta.GetChecksum()
+	stateFile := filepath.Join(h.resumeDir, fmt.Sprintf("%s-%x", checksum.GetHashFunc(), checksum.GetHash()))
+
+	sessionId, err := os.ReadFile(stateFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to read upload session state", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if len(sessionId) > 0 {
+		h.log.InfoContext(spanCtx, "resuming upload session", slog.String("session_id", string(sessionId)))
+	}
+
+	resp, err := h.resumable.ResumableUploadContent(spanCtx, &content.ResumableUploadContentRequest{
+		Metadata:  meta,
+		SessionId: string(sessionId),
+		Content:   h.src,
+		OnSession: func(id string) error {
+			return writeSessionState(h.resumeDir, stateFile, id)
+		},
+		OnProgress: onProgress,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	err = os.Remove(stateFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		// the upload itself succeeded so a stale state file isn't worth failing for
+		h.log.WarnContext(spanCtx, "failed to remove upload session state", slog.String("error", err.Error()))
+	}
+	return resp, nil
+}
+
+// reopenSource seeks back to the start of src for retrying an upload.
+// src is only closed once the upload is done so closing it is a no-op.
+func (h *handler) reopenSource() (io.ReadCloser, error) {
+	_, err := h.src.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(h.src), nil
+}
+
+func writeSessionState(dir, filename, sessionId string) error {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
 		return err
 	}
+	return os.WriteFile(filename, []byte(sessionId), 0o600)
+}
+
+// detectMediaType returns the media type to upload the content with. The
+// declared media type always wins but if it disagrees with the detected
+// media type a warning is logged, since it's likely a mistake. The returned
+// io.Reader must be used to read the content from the start instead of src.
+func (h *handler) detectMediaType(ctx context.Context) (string, io.Reader, error) {
+	if h.detector == nil {
+		if h.mediaType == "" {
+			return mediatype.Unknown, h.src, nil
+		}
+		return h.mediaType, h.src, nil
+	}
 
-	enc := json.NewEncoder(h.out)
-	return enc.Encode(resp)
+	br := bufio.NewReaderSize(h.src, mediatype.SniffLen)
+	header, err := br.Peek(mediatype.SniffLen)
+	if err != nil && err != io.EOF {
+		return "", nil, err
+	}
+
+	detected := h.detector.Detect(h.sourceFile, header)
+	if h.mediaType == "" {
+		h.log.InfoContext(ctx, "detected media type", slog.String("media_type", detected))
+		return detected, br, nil
+	}
+	if detected != mediatype.Unknown && !mediatype.Equal(detected, h.mediaType) {
+		h.log.WarnContext(
+			ctx,
+			"declared media type does not match detected media type",
+			slog.String("declared_media_type", h.mediaType),
+			slog.String("detected_media_type", detected),
+		)
+	}
+	return h.mediaType, br, nil
 }
@@ -15,6 +15,7 @@
 package upload
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"errors"
@@ -27,6 +28,7 @@ import (
 	"testing"
 
 	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/mediatype"
 	"github.com/z5labs/griot/services/content"
 	"github.com/z5labs/griot/services/content/contentpb"
 
@@ -36,7 +38,7 @@ import (
 
 func TestApp(t *testing.T) {
 	t.Run("will return an error", func(t *testing.T) {
-		t.Run("if the media type is not set", func(t *testing.T) {
+		t.Run("if the media type is invalid", func(t *testing.T) {
 			f, err := os.CreateTemp(t.TempDir(), "*")
 			if !assert.Nil(t, err) {
 				return
@@ -46,7 +48,7 @@ func TestApp(t *testing.T) {
 				return
 			}
 
-			app := New("--source-file", f.Name())
+			app := New("--media-type", "text/plain; hello=", "--source-file", f.Name())
 			err = app.Run(context.Background())
 
 			var iferr command.InvalidFlagError
@@ -56,12 +58,12 @@ func TestApp(t *testing.T) {
 			if !assert.Equal(t, "media-type", iferr.Name) {
 				return
 			}
-			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+			if !assert.ErrorIs(t, iferr, mime.ErrInvalidMediaParameter) {
 				return
 			}
 		})
 
-		t.Run("if the media type is invalid", func(t *testing.T) {
+		t.Run("if the media type has no subtype", func(t *testing.T) {
 			f, err := os.CreateTemp(t.TempDir(), "*")
 			if !assert.Nil(t, err) {
 				return
@@ -71,7 +73,7 @@ func TestApp(t *testing.T) {
 				return
 			}
 
-			app := New("--media-type", "text/plain; hello=", "--source-file", f.Name())
+			app := New("--media-type", "text", "--source-file", f.Name())
 			err = app.Run(context.Background())
 
 			var iferr command.InvalidFlagError
@@ -81,7 +83,7 @@ func TestApp(t *testing.T) {
 			if !assert.Equal(t, "media-type", iferr.Name) {
 				return
 			}
-			if !assert.ErrorIs(t, iferr, mime.ErrInvalidMediaParameter) {
+			if !assert.ErrorIs(t, iferr, contentpb.ErrMissingSubtype) {
 				return
 			}
 		})
@@ -184,7 +186,7 @@ func TestApp(t *testing.T) {
 				return
 			}
 
-			var uerr UnknownHashFuncError
+			var uerr content.UnknownHashFuncError
 			if !assert.ErrorAs(t, iferr, &uerr) {
 				return
 			}
@@ -195,6 +197,38 @@ func TestApp(t *testing.T) {
 				return
 			}
 		})
+
+		t.Run("if skip existing is set and the source file is stdin", func(t *testing.T) {
+			app := New("--media-type", "text/plain", "--source-file", "-", "--skip-existing")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "skip-existing", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrRequiresRegularFile) {
+				return
+			}
+		})
+
+		t.Run("if resume is set and the source file is stdin", func(t *testing.T) {
+			app := New("--media-type", "text/plain", "--source-file", "-", "--resume")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "resume", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrRequiresRegularFile) {
+				return
+			}
+		})
 	})
 }
 
@@ -207,7 +241,7 @@ func TestInitUploadHandler(t *testing.T) {
 
 			_, err := initUploadHandler(context.Background(), cfg)
 
-			var uerr UnknownHashFuncError
+			var uerr content.UnknownHashFuncError
 			if !assert.ErrorAs(t, err, &uerr) {
 				return
 			}
@@ -235,6 +269,28 @@ func TestInitUploadHandler(t *testing.T) {
 				return
 			}
 		})
+
+		t.Run("if the media type map file does not exist", func(t *testing.T) {
+			f, err := os.CreateTemp(t.TempDir(), "*")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = f.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			cfg := config{
+				HashFunc:     contentpb.HashFunc_SHA256.String(),
+				SourceFile:   f.Name(),
+				MediaTypeMap: filepath.Join(t.TempDir(), "mime.types"),
+			}
+
+			_, err = initUploadHandler(context.Background(), cfg)
+			if !assert.ErrorIs(t, err, os.ErrNotExist) {
+				return
+			}
+		})
 	})
 }
 
@@ -272,6 +328,18 @@ func (f uploadClientFunc) UploadContent(ctx context.Context, req *content.Upload
 	return f(ctx, req)
 }
 
+type contentExistsClientFunc func(context.Context, *content.ContentExistsRequest) (*content.ContentExistsResponse, error)
+
+func (f contentExistsClientFunc) ContentExists(ctx context.Context, req *content.ContentExistsRequest) (*content.ContentExistsResponse, error) {
+	return f(ctx, req)
+}
+
+type resumableUploadClientFunc func(context.Context, *content.ResumableUploadContentRequest) (*content.UploadContentResponse, error)
+
+func (f resumableUploadClientFunc) ResumableUploadContent(ctx context.Context, req *content.ResumableUploadContentRequest) (*content.UploadContentResponse, error) {
+	return f(ctx, req)
+}
+
 func TestHandler_Handle(t *testing.T) {
 	t.Run("will return an error", func(t *testing.T) {
 		t.Run("if it fails to compute the content hash", func(t *testing.T) {
@@ -282,7 +350,7 @@ func TestHandler_Handle(t *testing.T) {
 
 			h := &handler{
 				log:    slog.New(noop.LogHandler{}),
-				hasher: sha256Hasher{Hash: sha256.New()},
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
 				src: readSeekerNopCloser{
 					ReadSeeker: src,
 				},
@@ -302,7 +370,7 @@ func TestHandler_Handle(t *testing.T) {
 
 			h := &handler{
 				log:    slog.New(noop.LogHandler{}),
-				hasher: sha256Hasher{Hash: sha256.New()},
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
 				src: readSeekerNopCloser{
 					ReadSeeker: src,
 				},
@@ -322,7 +390,7 @@ func TestHandler_Handle(t *testing.T) {
 
 			h := &handler{
 				log:    slog.New(noop.LogHandler{}),
-				hasher: sha256Hasher{Hash: sha256.New()},
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
 				src: readSeekerNopCloser{
 					ReadSeeker: src,
 				},
@@ -355,7 +423,7 @@ func TestHandler_Handle(t *testing.T) {
 
 			h := &handler{
 				log:    slog.New(noop.LogHandler{}),
-				hasher: sha256Hasher{Hash: sha256.New()},
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
 				src: readSeekerNopCloser{
 					ReadSeeker: src,
 				},
@@ -367,5 +435,341 @@ func TestHandler_Handle(t *testing.T) {
 				return
 			}
 		})
+
+		t.Run("if it fails to check if the content exists", func(t *testing.T) {
+			existsErr := errors.New("failed to check")
+			existing := contentExistsClientFunc(func(ctx context.Context, req *content.ContentExistsRequest) (*content.ContentExistsResponse, error) {
+				return nil, existsErr
+			})
+
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("hello world"),
+				},
+				existing: existing,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, existsErr, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to resume the upload", func(t *testing.T) {
+			uploadErr := errors.New("failed to upload")
+			client := resumableUploadClientFunc(func(ctx context.Context, req *content.ResumableUploadContentRequest) (*content.UploadContentResponse, error) {
+				err := req.OnSession("session")
+				if err != nil {
+					return nil, err
+				}
+				return nil, uploadErr
+			})
+
+			resumeDir := t.TempDir()
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("hello world"),
+				},
+				resumeDir: resumeDir,
+				resumable: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, uploadErr, err) {
+				return
+			}
+
+			entries, err := os.ReadDir(resumeDir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, entries, 1) {
+				return
+			}
+		})
+	})
+
+	t.Run("will skip uploading the content", func(t *testing.T) {
+		t.Run("if content with the same checksum already exists", func(t *testing.T) {
+			hash := sha256.Sum256([]byte("hello world"))
+			existing := contentExistsClientFunc(func(ctx context.Context, req *content.ContentExistsRequest) (*content.ContentExistsResponse, error) {
+				if !assert.Equal(t, hash[:], req.Checksum.GetHash()) {
+					return nil, errors.New("unexpected checksum")
+				}
+				return &content.ContentExistsResponse{Exists: true, Id: "id"}, nil
+			})
+			client := uploadClientFunc(func(ctx context.Context, ucr *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				return nil, errors.New("should not upload")
+			})
+
+			var out strings.Builder
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("hello world"),
+				},
+				out:      &out,
+				content:  client,
+				existing: existing,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.JSONEq(t, `{"id":"id"}`, out.String()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will upload the content", func(t *testing.T) {
+		t.Run("if content with the same checksum does not exist", func(t *testing.T) {
+			existing := contentExistsClientFunc(func(ctx context.Context, req *content.ContentExistsRequest) (*content.ContentExistsResponse, error) {
+				return &content.ContentExistsResponse{}, nil
+			})
+
+			var uploaded bool
+			client := uploadClientFunc(func(ctx context.Context, ucr *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				uploaded = true
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("hello world"),
+				},
+				out:      io.Discard,
+				content:  client,
+				existing: existing,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.True(t, uploaded) {
+				return
+			}
+		})
+	})
+
+	t.Run("will re-open the source file", func(t *testing.T) {
+		t.Run("if the upload is retried", func(t *testing.T) {
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				if !assert.NotNil(t, req.OpenContent) {
+					return nil, errors.New("missing open content func")
+				}
+				for range 2 {
+					rc, err := req.OpenContent()
+					if err != nil {
+						return nil, err
+					}
+					b, err := io.ReadAll(rc)
+					if err != nil {
+						return nil, err
+					}
+					if !assert.Equal(t, "hello world", string(b)) {
+						return nil, errors.New("unexpected content")
+					}
+				}
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("hello world"),
+				},
+				out:     io.Discard,
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will detect the media type", func(t *testing.T) {
+		t.Run("if the media type is not set", func(t *testing.T) {
+			png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 1024)...)
+
+			var uploaded bytes.Buffer
+			var meta *contentpb.Metadata
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				meta = req.Metadata
+				_, err := io.Copy(&uploaded, req.Content)
+				if err != nil {
+					return nil, err
+				}
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: bytes.NewReader(png),
+				},
+				out:        io.Discard,
+				sourceFile: "image.bin",
+				detector:   mediatype.NewDetector(),
+				content:    client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "image", meta.GetMediaType().GetType()) {
+				return
+			}
+			if !assert.Equal(t, "png", meta.GetMediaType().GetSubtype()) {
+				return
+			}
+			if !assert.Equal(t, png, uploaded.Bytes()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will warn about the declared media type", func(t *testing.T) {
+		t.Run("if it does not match the detected media type", func(t *testing.T) {
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				if !assert.Equal(t, "text/plain", contentpb.FormatMediaType(req.Metadata.GetMediaType())) {
+					return nil, errors.New("unexpected media type")
+				}
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			var logs bytes.Buffer
+			h := &handler{
+				log:       slog.New(slog.NewTextHandler(&logs, nil)),
+				mediaType: "text/plain",
+				hasher:    funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: strings.NewReader("%PDF-1.7"),
+				},
+				out:        io.Discard,
+				sourceFile: "doc.txt",
+				detector:   mediatype.NewDetector(),
+				content:    client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Contains(t, logs.String(), "declared media type does not match detected media type") {
+				return
+			}
+			if !assert.Contains(t, logs.String(), "application/pdf") {
+				return
+			}
+		})
+	})
+
+	t.Run("will upload the content in a single pass", func(t *testing.T) {
+		t.Run("if the source can only be read once", func(t *testing.T) {
+			var uploaded strings.Builder
+			client := uploadClientFunc(func(ctx context.Context, req *content.UploadContentRequest) (*content.UploadContentResponse, error) {
+				if !assert.Empty(t, req.Metadata.GetChecksum().GetHash()) {
+					return nil, errors.New("unexpected checksum hash")
+				}
+				if !assert.Equal(t, contentpb.HashFunc_SHA256, req.Metadata.GetChecksum().GetHashFunc()) {
+					return nil, errors.New("unexpected checksum hash func")
+				}
+
+				_, err := io.Copy(&uploaded, req.Content)
+				if err != nil {
+					return nil, err
+				}
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			src := readNoopSeeker(strings.NewReader("hello world").Read)
+
+			h := &handler{
+				log:    slog.New(noop.LogHandler{}),
+				hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+				src: readSeekerNopCloser{
+					ReadSeeker: src,
+				},
+				out:        io.Discard,
+				singlePass: true,
+				content:    client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", uploaded.String()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will resume the upload session", func(t *testing.T) {
+		t.Run("if a previous upload of the content was interrupted", func(t *testing.T) {
+			var sessionIds []string
+			client := resumableUploadClientFunc(func(ctx context.Context, req *content.ResumableUploadContentRequest) (*content.UploadContentResponse, error) {
+				sessionIds = append(sessionIds, req.SessionId)
+
+				err := req.OnSession("session")
+				if err != nil {
+					return nil, err
+				}
+				if req.SessionId == "" {
+					return nil, errors.New("interrupted")
+				}
+				return &content.UploadContentResponse{Id: "id"}, nil
+			})
+
+			resumeDir := t.TempDir()
+			newHandler := func() *handler {
+				return &handler{
+					log:    slog.New(noop.LogHandler{}),
+					hasher: funcHasher{Hash: sha256.New(), hashFunc: contentpb.HashFunc_SHA256},
+					src: readSeekerNopCloser{
+						ReadSeeker: strings.NewReader("hello world"),
+					},
+					out:       io.Discard,
+					resumeDir: resumeDir,
+					resumable: client,
+				}
+			}
+
+			err := newHandler().Handle(context.Background())
+			if !assert.NotNil(t, err) {
+				return
+			}
+
+			err = newHandler().Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"", "session"}, sessionIds) {
+				return
+			}
+
+			entries, err := os.ReadDir(resumeDir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, entries) {
+				return
+			}
+		})
 	})
 }
@@ -0,0 +1,243 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package item
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"item",
+		command.Args(args...),
+		command.Short("Add content or a collection to a library"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("library-id", "", "Specify the id of the library to add the item to.")
+			fs.String("library-name", "", "Specify the name of the library to add the item to, instead of its id.")
+			fs.String("item-id", "", "Specify the id of the content or collection to add.")
+			fs.String("item-type", "", "Specify whether the item is content or a collection. If omitted, the item is a collection if one exists with its id and otherwise content. (values content,collection)")
+		}),
+		command.Handle(initItemHandler),
+	)
+}
+
+type config struct {
+	Host        string `flag:"content-host"`
+	LibraryId   string `flag:"library-id"`
+	LibraryName string `flag:"library-name"`
+	ItemId      string `flag:"item-id"`
+	ItemType    string `flag:"item-type"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateLibrary(c.LibraryId, c.LibraryName),
+		validateRequired("item-id", c.ItemId),
+		validateItemType(c.ItemType),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+var ErrNotAllowedWithLibraryId = errors.New("not allowed with library-id")
+
+func validateLibrary(id, name string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(id) > 0 && len(name) > 0 {
+			return command.InvalidFlagError{
+				Name:  "library-name",
+				Cause: ErrNotAllowedWithLibraryId,
+			}
+		}
+		if len(id) == 0 && len(name) == 0 {
+			return command.InvalidFlagError{
+				Name:  "library-id",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+func validateRequired(name, value string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(value) == 0 {
+			return command.InvalidFlagError{
+				Name:  name,
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+var ErrUnknownItemType = errors.New("must be either content or collection")
+
+func validateItemType(typ string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(typ) == 0 {
+			return nil
+		}
+		if _, ok := collectionpb.ItemType_value[strings.ToUpper(typ)]; !ok {
+			return command.InvalidFlagError{
+				Name:  "item-type",
+				Cause: ErrUnknownItemType,
+			}
+		}
+		return nil
+	}
+}
+
+type addClient interface {
+	ListLibraries(context.Context, *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error)
+	GetCollection(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+	AddLibraryItem(context.Context, *content.AddLibraryItemRequest) (*librarypb.Library, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	// libraryId is empty if the library should be looked up by libraryName.
+	libraryId   string
+	libraryName string
+	itemId      string
+
+	// itemType is nil if it should be determined from the item id.
+	itemType *collectionpb.ItemType
+
+	content addClient
+}
+
+func initItemHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	log := humus.Logger("item")
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:         log,
+		libraryId:   cfg.LibraryId,
+		libraryName: cfg.LibraryName,
+		itemId:      cfg.ItemId,
+		content:     content.NewClient(hc, cfg.Host, opts...),
+	}
+	if len(cfg.ItemType) > 0 {
+		h.itemType = collectionpb.ItemType(collectionpb.ItemType_value[strings.ToUpper(cfg.ItemType)]).Enum()
+	}
+	return h, nil
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("item").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	libraryId, err := h.resolveLibraryId(spanCtx)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to find library", slog.String("error", err.Error()))
+		return err
+	}
+
+	typ, err := h.resolveItemType(spanCtx)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to determine item type", slog.String("error", err.Error()))
+		return err
+	}
+
+	_, err = h.content.AddLibraryItem(spanCtx, &content.AddLibraryItemRequest{
+		LibraryId: libraryId,
+		Type:      typ,
+		ItemId:    h.itemId,
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to add library item", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+type LibraryNotFoundError struct {
+	Name string
+}
+
+func (e LibraryNotFoundError) Error() string {
+	return fmt.Sprintf("no library named %q", e.Name)
+}
+
+// resolveLibraryId returns the given library id or, if none was
+// given, looks up the id of the library with the given name.
+func (h *handler) resolveLibraryId(ctx context.Context) (string, error) {
+	if len(h.libraryId) > 0 {
+		return h.libraryId, nil
+	}
+
+	resp, err := h.content.ListLibraries(ctx, &content.ListLibrariesRequest{
+		Name: h.libraryName,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Libraries) == 0 {
+		return "", LibraryNotFoundError{Name: h.libraryName}
+	}
+	return resp.Libraries[0].GetId(), nil
+}
+
+// resolveItemType returns the given item type or, if none was given, looks
+// up whether a collection exists with the item id.
+func (h *handler) resolveItemType(ctx context.Context) (collectionpb.ItemType, error) {
+	if h.itemType != nil {
+		return *h.itemType, nil
+	}
+
+	_, err := h.content.GetCollection(ctx, &content.GetCollectionRequest{
+		Id: h.itemId,
+	})
+	var status *humuspb.Status
+	if errors.As(err, &status) && status.GetCode() == humuspb.Code_NOT_FOUND {
+		return collectionpb.ItemType_CONTENT, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return collectionpb.ItemType_COLLECTION, nil
+}
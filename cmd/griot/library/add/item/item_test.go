@@ -0,0 +1,261 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package item
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if neither the library id or name are set", func(t *testing.T) {
+			app := New("--item-id", "collection-2")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "library-id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if both the library id and name are set", func(t *testing.T) {
+			app := New("--library-id", "library-1", "--library-name", "Anime", "--item-id", "collection-2")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "library-name", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNotAllowedWithLibraryId) {
+				return
+			}
+		})
+
+		t.Run("if the item id is not set", func(t *testing.T) {
+			app := New("--library-id", "library-1")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "item-id", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the item type is unknown", func(t *testing.T) {
+			app := New("--library-id", "library-1", "--item-id", "collection-2", "--item-type", "library")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "item-type", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrUnknownItemType) {
+				return
+			}
+		})
+	})
+}
+
+type addClientStub struct {
+	listLibraries  func(context.Context, *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error)
+	getCollection  func(context.Context, *content.GetCollectionRequest) (*collectionpb.Collection, error)
+	addLibraryItem func(context.Context, *content.AddLibraryItemRequest) (*librarypb.Library, error)
+}
+
+func (s addClientStub) ListLibraries(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+	return s.listLibraries(ctx, req)
+}
+
+func (s addClientStub) GetCollection(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+	return s.getCollection(ctx, req)
+}
+
+func (s addClientStub) AddLibraryItem(ctx context.Context, req *content.AddLibraryItemRequest) (*librarypb.Library, error) {
+	return s.addLibraryItem(ctx, req)
+}
+
+func collectionExists(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+	return &collectionpb.Collection{Id: &req.Id}, nil
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if there is no library with the given name", func(t *testing.T) {
+			h := &handler{
+				log:         slog.New(noop.LogHandler{}),
+				libraryName: "Anime",
+				content: addClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						return &content.ListLibrariesResponse{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+
+			var lnferr LibraryNotFoundError
+			if !assert.ErrorAs(t, err, &lnferr) {
+				return
+			}
+			if !assert.Equal(t, "Anime", lnferr.Name) {
+				return
+			}
+		})
+
+		t.Run("if it fails to look up the item type", func(t *testing.T) {
+			getErr := errors.New("failed to get")
+			h := &handler{
+				log:       slog.New(noop.LogHandler{}),
+				libraryId: "library-1",
+				content: addClientStub{
+					getCollection: func(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+						return nil, getErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, getErr, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to add the item", func(t *testing.T) {
+			addErr := errors.New("failed to add")
+			h := &handler{
+				log:       slog.New(noop.LogHandler{}),
+				libraryId: "library-1",
+				content: addClientStub{
+					getCollection: collectionExists,
+					addLibraryItem: func(ctx context.Context, req *content.AddLibraryItemRequest) (*librarypb.Library, error) {
+						return nil, addErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, addErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will add the item", func(t *testing.T) {
+		t.Run("to the library with the given name", func(t *testing.T) {
+			var gotListReq *content.ListLibrariesRequest
+			var gotReq *content.AddLibraryItemRequest
+			h := &handler{
+				log:         slog.New(noop.LogHandler{}),
+				libraryName: "Anime",
+				itemId:      "collection-2",
+				content: addClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						gotListReq = req
+						resp := &content.ListLibrariesResponse{
+							Libraries: []*librarypb.Library{
+								{Id: ptr.Ref("library-1"), Name: ptr.Ref("Anime")},
+							},
+						}
+						return resp, nil
+					},
+					getCollection: collectionExists,
+					addLibraryItem: func(ctx context.Context, req *content.AddLibraryItemRequest) (*librarypb.Library, error) {
+						gotReq = req
+						return &librarypb.Library{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "Anime", gotListReq.Name) {
+				return
+			}
+			if !assert.Equal(t, "library-1", gotReq.LibraryId) {
+				return
+			}
+			if !assert.Equal(t, collectionpb.ItemType_COLLECTION, gotReq.Type) {
+				return
+			}
+			if !assert.Equal(t, "collection-2", gotReq.ItemId) {
+				return
+			}
+		})
+
+		t.Run("as content if no collection exists with the item id", func(t *testing.T) {
+			var gotReq *content.AddLibraryItemRequest
+			h := &handler{
+				log:       slog.New(noop.LogHandler{}),
+				libraryId: "library-1",
+				itemId:    "content-1",
+				content: addClientStub{
+					getCollection: func(ctx context.Context, req *content.GetCollectionRequest) (*collectionpb.Collection, error) {
+						return nil, &humuspb.Status{
+							Code: humuspb.Code_NOT_FOUND.Enum(),
+						}
+					},
+					addLibraryItem: func(ctx context.Context, req *content.AddLibraryItemRequest) (*librarypb.Library, error) {
+						gotReq = req
+						return &librarypb.Library{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "library-1", gotReq.LibraryId) {
+				return
+			}
+			if !assert.Equal(t, collectionpb.ItemType_CONTENT, gotReq.Type) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the name is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "name", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type createClientFunc func(context.Context, *content.CreateLibraryRequest) (*librarypb.Library, error)
+
+func (f createClientFunc) CreateLibrary(ctx context.Context, req *content.CreateLibraryRequest) (*librarypb.Library, error) {
+	return f(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to create the library", func(t *testing.T) {
+			createErr := errors.New("failed to create")
+			client := createClientFunc(func(ctx context.Context, req *content.CreateLibraryRequest) (*librarypb.Library, error) {
+				return nil, createErr
+			})
+
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, createErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print the library id", func(t *testing.T) {
+		t.Run("if the library is successfully created", func(t *testing.T) {
+			var gotReq *content.CreateLibraryRequest
+			client := createClientFunc(func(ctx context.Context, req *content.CreateLibraryRequest) (*librarypb.Library, error) {
+				gotReq = req
+				l := &librarypb.Library{
+					Id:   ptr.Ref("library-1"),
+					Name: ptr.Ref(req.Name),
+				}
+				return l, nil
+			})
+
+			var out bytes.Buffer
+			h := &handler{
+				log:     slog.New(noop.LogHandler{}),
+				name:    "Anime",
+				out:     &out,
+				content: client,
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "Anime", gotReq.Name) {
+				return
+			}
+			if !assert.Equal(t, `{"id":"library-1"}`+"\n", out.String()) {
+				return
+			}
+		})
+	})
+}
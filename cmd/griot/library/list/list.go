@@ -0,0 +1,221 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"list",
+		command.Args(args...),
+		command.Short("List libraries or the items of a library"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+			fs.String("library-id", "", "List the items of this library, instead of every library.")
+			fs.String("library-name", "", "List the items of the library with this name, instead of every library.")
+		}),
+		command.Handle(initListHandler),
+	)
+}
+
+type config struct {
+	Host        string `flag:"content-host"`
+	LibraryId   string `flag:"library-id"`
+	LibraryName string `flag:"library-name"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateLibrary(c.LibraryId, c.LibraryName),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+var ErrNotAllowedWithLibraryId = errors.New("not allowed with library-id")
+
+func validateLibrary(id, name string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(id) > 0 && len(name) > 0 {
+			return command.InvalidFlagError{
+				Name:  "library-name",
+				Cause: ErrNotAllowedWithLibraryId,
+			}
+		}
+		return nil
+	}
+}
+
+type listClient interface {
+	ListLibraries(context.Context, *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error)
+	GetLibrary(context.Context, *content.GetLibraryRequest) (*librarypb.Library, error)
+}
+
+type handler struct {
+	log *slog.Logger
+
+	libraryId   string
+	libraryName string
+	out         io.Writer
+
+	content listClient
+}
+
+func initListHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	log := humus.Logger("list")
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	opts, err := credentials.ClientOptions(cfg.Host)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to load credentials", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	h := &handler{
+		log:         log,
+		libraryId:   cfg.LibraryId,
+		libraryName: cfg.LibraryName,
+		out:         os.Stdout,
+		content:     content.NewClient(hc, cfg.Host, opts...),
+	}
+	return h, nil
+}
+
+type librarySummary struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type item struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+// Handle prints every library, or the items of the library,
+// as a single JSON array.
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("list").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	var v any
+	if len(h.libraryId) == 0 && len(h.libraryName) == 0 {
+		libraries, err := h.listLibraries(spanCtx)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list libraries", slog.String("error", err.Error()))
+			return err
+		}
+		v = libraries
+	} else {
+		items, err := h.listItems(spanCtx)
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to get library", slog.String("error", err.Error()))
+			return err
+		}
+		v = items
+	}
+
+	enc := json.NewEncoder(h.out)
+	err := enc.Encode(v)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write list", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (h *handler) listLibraries(ctx context.Context) ([]librarySummary, error) {
+	resp, err := h.content.ListLibraries(ctx, &content.ListLibrariesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	libraries := make([]librarySummary, 0, len(resp.Libraries))
+	for _, l := range resp.Libraries {
+		libraries = append(libraries, librarySummary{
+			Id:   l.GetId(),
+			Name: l.GetName(),
+		})
+	}
+	return libraries, nil
+}
+
+func (h *handler) listItems(ctx context.Context) ([]item, error) {
+	l, err := h.getLibrary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]item, 0, len(l.GetItems()))
+	for _, it := range l.GetItems() {
+		items = append(items, item{
+			Type: strings.ToLower(it.GetType().String()),
+			Id:   it.GetId(),
+		})
+	}
+	return items, nil
+}
+
+type LibraryNotFoundError struct {
+	Name string
+}
+
+func (e LibraryNotFoundError) Error() string {
+	return fmt.Sprintf("no library named %q", e.Name)
+}
+
+func (h *handler) getLibrary(ctx context.Context) (*librarypb.Library, error) {
+	if len(h.libraryId) > 0 {
+		return h.content.GetLibrary(ctx, &content.GetLibraryRequest{
+			Id: h.libraryId,
+		})
+	}
+
+	resp, err := h.content.ListLibraries(ctx, &content.ListLibrariesRequest{
+		Name: h.libraryName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Libraries) == 0 {
+		return nil, LibraryNotFoundError{Name: h.libraryName}
+	}
+	return resp.Libraries[0], nil
+}
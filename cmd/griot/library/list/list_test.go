@@ -0,0 +1,250 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if both the library id and name are set", func(t *testing.T) {
+			app := New("--library-id", "library-1", "--library-name", "Anime")
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "library-name", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, ErrNotAllowedWithLibraryId) {
+				return
+			}
+		})
+	})
+}
+
+type listClientStub struct {
+	listLibraries func(context.Context, *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error)
+	getLibrary    func(context.Context, *content.GetLibraryRequest) (*librarypb.Library, error)
+}
+
+func (s listClientStub) ListLibraries(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+	return s.listLibraries(ctx, req)
+}
+
+func (s listClientStub) GetLibrary(ctx context.Context, req *content.GetLibraryRequest) (*librarypb.Library, error) {
+	return s.getLibrary(ctx, req)
+}
+
+func anime() *librarypb.Library {
+	return &librarypb.Library{
+		Id:   ptr.Ref("library-1"),
+		Name: ptr.Ref("Anime"),
+		Items: []*librarypb.LibraryItem{
+			{Type: collectionpb.ItemType_COLLECTION.Enum(), Id: ptr.Ref("collection-2")},
+			{Type: collectionpb.ItemType_CONTENT.Enum(), Id: ptr.Ref("content-1")},
+		},
+	}
+}
+
+const animeItems = `[{"type":"collection","id":"collection-2"},{"type":"content","id":"content-1"}]` + "\n"
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(b []byte) (int, error) {
+	return f(b)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to list the libraries", func(t *testing.T) {
+			listErr := errors.New("failed to list")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				content: listClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						return nil, listErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, listErr, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to get the library", func(t *testing.T) {
+			getErr := errors.New("failed to get")
+			h := &handler{
+				log:       slog.New(noop.LogHandler{}),
+				libraryId: "library-1",
+				content: listClientStub{
+					getLibrary: func(ctx context.Context, req *content.GetLibraryRequest) (*librarypb.Library, error) {
+						return nil, getErr
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, getErr, err) {
+				return
+			}
+		})
+
+		t.Run("if there is no library with the given name", func(t *testing.T) {
+			h := &handler{
+				log:         slog.New(noop.LogHandler{}),
+				libraryName: "Anime",
+				content: listClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						return &content.ListLibrariesResponse{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+
+			var lnferr LibraryNotFoundError
+			if !assert.ErrorAs(t, err, &lnferr) {
+				return
+			}
+			if !assert.Equal(t, "Anime", lnferr.Name) {
+				return
+			}
+		})
+
+		t.Run("if it fails to write the list", func(t *testing.T) {
+			writeErr := errors.New("failed to write")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				out: writerFunc(func(b []byte) (int, error) {
+					return 0, writeErr
+				}),
+				content: listClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						return &content.ListLibrariesResponse{}, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, writeErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print every library", func(t *testing.T) {
+		t.Run("if no library is given", func(t *testing.T) {
+			var out bytes.Buffer
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				out: &out,
+				content: listClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						resp := &content.ListLibrariesResponse{
+							Libraries: []*librarypb.Library{anime()},
+						}
+						return resp, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, `[{"id":"library-1","name":"Anime"}]`+"\n", out.String()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will print the items of the library", func(t *testing.T) {
+		t.Run("if a library id is given", func(t *testing.T) {
+			var gotReq *content.GetLibraryRequest
+			var out bytes.Buffer
+			h := &handler{
+				log:       slog.New(noop.LogHandler{}),
+				libraryId: "library-1",
+				out:       &out,
+				content: listClientStub{
+					getLibrary: func(ctx context.Context, req *content.GetLibraryRequest) (*librarypb.Library, error) {
+						gotReq = req
+						return anime(), nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "library-1", gotReq.Id) {
+				return
+			}
+			if !assert.Equal(t, animeItems, out.String()) {
+				return
+			}
+		})
+
+		t.Run("if a library name is given", func(t *testing.T) {
+			var gotReq *content.ListLibrariesRequest
+			var out bytes.Buffer
+			h := &handler{
+				log:         slog.New(noop.LogHandler{}),
+				libraryName: "Anime",
+				out:         &out,
+				content: listClientStub{
+					listLibraries: func(ctx context.Context, req *content.ListLibrariesRequest) (*content.ListLibrariesResponse, error) {
+						gotReq = req
+						resp := &content.ListLibrariesResponse{
+							Libraries: []*librarypb.Library{anime()},
+						}
+						return resp, nil
+					},
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "Anime", gotReq.Name) {
+				return
+			}
+			if !assert.Equal(t, animeItems, out.String()) {
+				return
+			}
+		})
+	})
+}
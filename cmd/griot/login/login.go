@@ -0,0 +1,170 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"login",
+		command.Args(args...),
+		command.Short("Store an API token for authenticating with griot"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+		}),
+		command.Handle(initLoginHandler),
+	)
+}
+
+type config struct {
+	Host string `flag:"content-host"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateHost(c.Host),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateHost(host string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(host) == 0 {
+			return command.InvalidFlagError{
+				Name:  "content-host",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+var ErrMissingToken = errors.New("no token was provided on stdin")
+
+type credentialsStore interface {
+	Set(host, secret string) error
+}
+
+type listClient interface {
+	ListContent(context.Context, *content.ListContentRequest) iter.Seq2[*indexpb.Record, error]
+}
+
+type handler struct {
+	log *slog.Logger
+
+	host  string
+	in    io.Reader
+	creds credentialsStore
+
+	// newClient returns a client authenticated with the
+	// given secret for verifying it before it's stored.
+	newClient func(secret string) listClient
+}
+
+func initLoginHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	spanCtx, span := otel.Tracer("login").Start(ctx, "initLoginHandler")
+	defer span.End()
+
+	log := humus.Logger("login")
+
+	creds, err := credentials.DefaultFile()
+	if err != nil {
+		log.ErrorContext(spanCtx, "failed to find user config directory", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	hc := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	h := &handler{
+		log:   log,
+		host:  cfg.Host,
+		in:    os.Stdin,
+		creds: creds,
+		newClient: func(secret string) listClient {
+			return content.NewClient(hc, cfg.Host, content.Credentials(secret))
+		},
+	}
+	return h, nil
+}
+
+// Handle reads the token from stdin, instead of a flag,
+// so it doesn't end up in the user's shell history.
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("login").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	secret, err := h.readToken()
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to read token", slog.String("error", err.Error()))
+		return err
+	}
+
+	// any valid token grants at least the READ scope
+	// which is all listing content requires
+	client := h.newClient(secret)
+	for _, err := range client.ListContent(spanCtx, &content.ListContentRequest{PageSize: 1}) {
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to verify token", slog.String("error", err.Error()))
+			return err
+		}
+		break
+	}
+
+	err = h.creds.Set(h.host, secret)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to store token", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (h *handler) readToken() (string, error) {
+	line, err := bufio.NewReader(h.in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(line)
+	if secret == "" {
+		return "", ErrMissingToken
+	}
+	return secret, nil
+}
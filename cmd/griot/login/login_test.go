@@ -0,0 +1,166 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content host is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "content-host", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type credentialsStoreFunc func(host, secret string) error
+
+func (f credentialsStoreFunc) Set(host, secret string) error {
+	return f(host, secret)
+}
+
+type listClientFunc func(context.Context, *content.ListContentRequest) iter.Seq2[*indexpb.Record, error]
+
+func (f listClientFunc) ListContent(ctx context.Context, req *content.ListContentRequest) iter.Seq2[*indexpb.Record, error] {
+	return f(ctx, req)
+}
+
+func listResult(err error) listClientFunc {
+	return func(ctx context.Context, req *content.ListContentRequest) iter.Seq2[*indexpb.Record, error] {
+		return func(yield func(*indexpb.Record, error) bool) {
+			if err != nil {
+				yield(nil, err)
+			}
+		}
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if no token is given on stdin", func(t *testing.T) {
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				in:  strings.NewReader("\n"),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.ErrorIs(t, err, ErrMissingToken) {
+				return
+			}
+		})
+
+		t.Run("if the token fails to authenticate", func(t *testing.T) {
+			authErr := errors.New("unauthenticated")
+			stored := false
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				in:  strings.NewReader("secret\n"),
+				creds: credentialsStoreFunc(func(host, secret string) error {
+					stored = true
+					return nil
+				}),
+				newClient: func(secret string) listClient {
+					return listResult(authErr)
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, authErr, err) {
+				return
+			}
+			if !assert.False(t, stored) {
+				return
+			}
+		})
+
+		t.Run("if it fails to store the token", func(t *testing.T) {
+			storeErr := errors.New("failed to store")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				in:  strings.NewReader("secret\n"),
+				creds: credentialsStoreFunc(func(host, secret string) error {
+					return storeErr
+				}),
+				newClient: func(secret string) listClient {
+					return listResult(nil)
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, storeErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will store the token", func(t *testing.T) {
+		t.Run("for the given host", func(t *testing.T) {
+			var storedHost, storedSecret, verifiedSecret string
+			h := &handler{
+				log:  slog.New(noop.LogHandler{}),
+				host: "http://localhost:8080",
+				in:   strings.NewReader("  secret  \n"),
+				creds: credentialsStoreFunc(func(host, secret string) error {
+					storedHost = host
+					storedSecret = secret
+					return nil
+				}),
+				newClient: func(secret string) listClient {
+					verifiedSecret = secret
+					return listResult(nil)
+				},
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "http://localhost:8080", storedHost) {
+				return
+			}
+			if !assert.Equal(t, "secret", storedSecret) {
+				return
+			}
+			if !assert.Equal(t, "secret", verifiedSecret) {
+				return
+			}
+		})
+	})
+}
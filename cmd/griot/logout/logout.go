@@ -0,0 +1,107 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logout
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+
+	"github.com/spf13/pflag"
+	"github.com/z5labs/humus"
+	"go.opentelemetry.io/otel"
+)
+
+func New(args ...string) *command.App {
+	return command.NewApp(
+		"logout",
+		command.Args(args...),
+		command.Short("Remove the stored API token for a griot host"),
+		command.Flags(func(fs *pflag.FlagSet) {
+			fs.String("content-host", "", "Specify the host for reaching griot.")
+		}),
+		command.Handle(initLogoutHandler),
+	)
+}
+
+type config struct {
+	Host string `flag:"content-host"`
+}
+
+func (c config) Validate(ctx context.Context) error {
+	validators := []command.Validator{
+		validateHost(c.Host),
+	}
+
+	return command.ValidateAll(ctx, validators...)
+}
+
+func validateHost(host string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(host) == 0 {
+			return command.InvalidFlagError{
+				Name:  "content-host",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+		return nil
+	}
+}
+
+type credentialsStore interface {
+	Delete(host string) error
+}
+
+type handler struct {
+	log *slog.Logger
+
+	host  string
+	creds credentialsStore
+}
+
+func initLogoutHandler(ctx context.Context, cfg config) (command.Handler, error) {
+	creds, err := credentials.DefaultFile()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		log:   humus.Logger("logout"),
+		host:  cfg.Host,
+		creds: creds,
+	}
+	return h, nil
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	spanCtx, span := otel.Tracer("logout").Start(ctx, "handler.Handle")
+	defer span.End()
+
+	err := h.creds.Delete(h.host)
+	if errors.Is(err, credentials.ErrNotFound) {
+		// logging out is idempotent
+		h.log.InfoContext(spanCtx, "not logged in", slog.String("content_host", h.host))
+		return nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to remove token", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logout
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/internal/credentials"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/bedrock/pkg/noop"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content host is not set", func(t *testing.T) {
+			app := New()
+			err := app.Run(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "content-host", iferr.Name) {
+				return
+			}
+			if !assert.ErrorIs(t, iferr, command.ErrFlagRequired) {
+				return
+			}
+		})
+	})
+}
+
+type credentialsStoreFunc func(host string) error
+
+func (f credentialsStoreFunc) Delete(host string) error {
+	return f(host)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if it fails to remove the token", func(t *testing.T) {
+			deleteErr := errors.New("failed to delete")
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				creds: credentialsStoreFunc(func(host string) error {
+					return deleteErr
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Equal(t, deleteErr, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will not return an error", func(t *testing.T) {
+		t.Run("if there is no token for the host", func(t *testing.T) {
+			h := &handler{
+				log: slog.New(noop.LogHandler{}),
+				creds: credentialsStoreFunc(func(host string) error {
+					return credentials.ErrNotFound
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the token", func(t *testing.T) {
+		t.Run("for the given host", func(t *testing.T) {
+			var removedHost string
+			h := &handler{
+				log:  slog.New(noop.LogHandler{}),
+				host: "http://localhost:8080",
+				creds: credentialsStoreFunc(func(host string) error {
+					removedHost = host
+					return nil
+				}),
+			}
+
+			err := h.Handle(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "http://localhost:8080", removedHost) {
+				return
+			}
+		})
+	})
+}
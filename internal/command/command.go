@@ -151,6 +151,10 @@ func (a *App) Run(ctx context.Context) error {
 func decodeFlags(fs *pflag.FlagSet, v interface{}) error {
 	m := make(map[string]any)
 	fs.VisitAll(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			m[f.Name] = sv.GetSlice()
+			return
+		}
 		m[f.Name] = f.Value
 	})
 
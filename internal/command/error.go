@@ -22,6 +22,7 @@ import (
 var (
 	ErrFlagRequired = errors.New("required")
 	ErrMustBeAFile  = errors.New("must be a file and not a directory")
+	ErrMustBeADir   = errors.New("must be a directory and not a file")
 )
 
 type InvalidFlagError struct {
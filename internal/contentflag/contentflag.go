@@ -0,0 +1,97 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contentflag provides the flags shared by the commands which compute checksums of content.
+package contentflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/spf13/pflag"
+)
+
+// HashFunc registers the hash-func flag, defaulting to SHA256.
+func HashFunc(fs *pflag.FlagSet) {
+	fs.String(
+		"hash-func",
+		contentpb.HashFunc_SHA256.String(),
+		fmt.Sprintf(
+			"Specify hash function used for calculating content checksum. (values %s)",
+			hashFuncNames(),
+		),
+	)
+}
+
+func hashFuncNames() string {
+	var names []string
+	for _, hf := range content.SupportedHashFuncs() {
+		names = append(names, hf.String())
+	}
+	return strings.Join(names, ",")
+}
+
+// ValidateHashFunc checks the hash-func flag names a supported hash function.
+func ValidateHashFunc(name string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(name) == 0 {
+			return command.InvalidFlagError{
+				Name:  "hash-func",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+
+		_, err := content.ParseHashFunc(name)
+		if err != nil {
+			return command.InvalidFlagError{
+				Name:  "hash-func",
+				Cause: err,
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateSourceFile checks the source-file flag names an existing file.
+func ValidateSourceFile(filename string) command.ValidatorFunc {
+	return func(ctx context.Context) error {
+		if len(filename) == 0 {
+			return command.InvalidFlagError{
+				Name:  "source-file",
+				Cause: command.ErrFlagRequired,
+			}
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			return command.InvalidFlagError{
+				Name:  "source-file",
+				Cause: err,
+			}
+		}
+		if info.IsDir() {
+			return command.InvalidFlagError{
+				Name:  "source-file",
+				Cause: command.ErrMustBeAFile,
+			}
+		}
+		return nil
+	}
+}
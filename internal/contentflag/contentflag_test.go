@@ -0,0 +1,111 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contentflag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/z5labs/griot/internal/command"
+	"github.com/z5labs/griot/services/content"
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHashFunc(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the hash func is not set", func(t *testing.T) {
+			err := ValidateHashFunc("")(context.Background())
+			if !assert.ErrorIs(t, err, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the hash func is unknown", func(t *testing.T) {
+			err := ValidateHashFunc("SHA")(context.Background())
+
+			var iferr command.InvalidFlagError
+			if !assert.ErrorAs(t, err, &iferr) {
+				return
+			}
+			if !assert.Equal(t, "hash-func", iferr.Name) {
+				return
+			}
+
+			var uerr content.UnknownHashFuncError
+			if !assert.ErrorAs(t, iferr, &uerr) {
+				return
+			}
+			if !assert.Equal(t, "SHA", uerr.Value) {
+				return
+			}
+		})
+	})
+
+	t.Run("will not return an error", func(t *testing.T) {
+		t.Run("if the hash func is supported", func(t *testing.T) {
+			for _, hf := range content.SupportedHashFuncs() {
+				err := ValidateHashFunc(hf.String())(context.Background())
+				if !assert.Nil(t, err, hf.String()) {
+					return
+				}
+			}
+		})
+	})
+}
+
+func TestValidateSourceFile(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the source file is not set", func(t *testing.T) {
+			err := ValidateSourceFile("")(context.Background())
+			if !assert.ErrorIs(t, err, command.ErrFlagRequired) {
+				return
+			}
+		})
+
+		t.Run("if the source file does not exist", func(t *testing.T) {
+			err := ValidateSourceFile(filepath.Join(t.TempDir(), "missing"))(context.Background())
+			if !assert.ErrorIs(t, err, os.ErrNotExist) {
+				return
+			}
+		})
+
+		t.Run("if the source file is a directory", func(t *testing.T) {
+			err := ValidateSourceFile(t.TempDir())(context.Background())
+			if !assert.ErrorIs(t, err, command.ErrMustBeAFile) {
+				return
+			}
+		})
+	})
+}
+
+func TestHashFunc(t *testing.T) {
+	t.Run("will default to SHA256", func(t *testing.T) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		HashFunc(fs)
+
+		name, err := fs.GetString("hash-func")
+		if !assert.Nil(t, err) {
+			return
+		}
+		if !assert.Equal(t, contentpb.HashFunc_SHA256.String(), name) {
+			return
+		}
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credentials stores the API tokens griot authenticates with, per host.
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/z5labs/griot/services/content"
+)
+
+var ErrNotFound = errors.New("no credentials found for host")
+
+// File is a JSON file mapping hosts to the API token secret used for them.
+// Since it contains secrets, it's only ever written readable by its owner.
+type File struct {
+	filename string
+}
+
+func NewFile(filename string) *File {
+	return &File{
+		filename: filename,
+	}
+}
+
+// DefaultFile is <user config dir>/griot/credentials.json.
+func DefaultFile() (*File, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewFile(filepath.Join(configDir, "griot", "credentials.json")), nil
+}
+
+// ClientOptions authenticates a content.Client for the host with the token
+// stored in the DefaultFile. No options are returned if there isn't one.
+func ClientOptions(host string) ([]content.ClientOption, error) {
+	f, err := DefaultFile()
+	if err != nil {
+		// without a config dir there can't be any stored credentials
+		return nil, nil
+	}
+
+	secret, err := f.Get(host)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []content.ClientOption{content.Credentials(secret)}, nil
+}
+
+type hostCredentials struct {
+	Token string `json:"token"`
+}
+
+type fileContent struct {
+	Hosts map[string]hostCredentials `json:"hosts"`
+}
+
+// hostKey normalizes the host so e.g. a trailing slash doesn't matter.
+func hostKey(host string) string {
+	return strings.TrimSuffix(host, "/")
+}
+
+// Get returns the token secret for the host. ErrNotFound is
+// returned if there is none, including if the file doesn't exist.
+func (f *File) Get(host string) (string, error) {
+	c, err := f.read()
+	if err != nil {
+		return "", err
+	}
+
+	creds, ok := c.Hosts[hostKey(host)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return creds.Token, nil
+}
+
+// Set stores the token secret for the host, replacing any existing one.
+func (f *File) Set(host, secret string) error {
+	c, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	c.Hosts[hostKey(host)] = hostCredentials{
+		Token: secret,
+	}
+	return f.write(c)
+}
+
+// Delete removes the token secret for the host. ErrNotFound is returned if there is none.
+func (f *File) Delete(host string) error {
+	c, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	key := hostKey(host)
+	if _, ok := c.Hosts[key]; !ok {
+		return ErrNotFound
+	}
+	delete(c.Hosts, key)
+	return f.write(c)
+}
+
+func (f *File) read() (*fileContent, error) {
+	c := &fileContent{
+		Hosts: make(map[string]hostCredentials),
+	}
+
+	b, err := os.ReadFile(f.filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, c)
+	if err != nil {
+		return nil, err
+	}
+	if c.Hosts == nil {
+		c.Hosts = make(map[string]hostCredentials)
+	}
+	return c, nil
+}
+
+// write replaces the file through a rename so it's never left partially written
+// and always ends up with 0600 permissions, even if it previously existed with others.
+func (f *File) write(c *fileContent) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(f.filename)
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".credentials-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Chmod(0o600)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.filename)
+}
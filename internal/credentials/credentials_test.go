@@ -0,0 +1,192 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile_Get(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the file does not exist", func(t *testing.T) {
+			f := NewFile(filepath.Join(t.TempDir(), "credentials.json"))
+
+			_, err := f.Get("http://localhost:8080")
+			if !assert.ErrorIs(t, err, ErrNotFound) {
+				return
+			}
+		})
+
+		t.Run("if there are no credentials for the host", func(t *testing.T) {
+			f := NewFile(filepath.Join(t.TempDir(), "credentials.json"))
+
+			err := f.Set("http://localhost:8080", "secret")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = f.Get("http://localhost:9090")
+			if !assert.ErrorIs(t, err, ErrNotFound) {
+				return
+			}
+		})
+
+		t.Run("if the file is malformed", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "credentials.json")
+			err := os.WriteFile(filename, []byte("{"), 0o600)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = NewFile(filename).Get("http://localhost:8080")
+			if !assert.Error(t, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the secret", func(t *testing.T) {
+		t.Run("regardless of a trailing slash on the host", func(t *testing.T) {
+			f := NewFile(filepath.Join(t.TempDir(), "credentials.json"))
+
+			err := f.Set("http://localhost:8080/", "secret")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			secret, err := f.Get("http://localhost:8080")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "secret", secret) {
+				return
+			}
+		})
+	})
+}
+
+func TestFile_Set(t *testing.T) {
+	t.Run("will write the file", func(t *testing.T) {
+		t.Run("only readable by its owner", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "griot", "credentials.json")
+			err := NewFile(filename).Set("http://localhost:8080", "secret")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			info, err := os.Stat(filename)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, os.FileMode(0o600), info.Mode().Perm()) {
+				return
+			}
+		})
+
+		t.Run("even if it previously had broader permissions", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "credentials.json")
+			err := os.WriteFile(filename, []byte("{}"), 0o644)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = NewFile(filename).Set("http://localhost:8080", "secret")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			info, err := os.Stat(filename)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, os.FileMode(0o600), info.Mode().Perm()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will keep the credentials", func(t *testing.T) {
+		t.Run("of other hosts", func(t *testing.T) {
+			f := NewFile(filepath.Join(t.TempDir(), "credentials.json"))
+
+			err := f.Set("http://a:8080", "a")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = f.Set("http://b:8080", "b")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			secret, err := f.Get("http://a:8080")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "a", secret) {
+				return
+			}
+		})
+	})
+}
+
+func TestFile_Delete(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if there are no credentials for the host", func(t *testing.T) {
+			f := NewFile(filepath.Join(t.TempDir(), "credentials.json"))
+
+			err := f.Delete("http://localhost:8080")
+			if !assert.ErrorIs(t, err, ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the credentials", func(t *testing.T) {
+		t.Run("of only the given host", func(t *testing.T) {
+			f := NewFile(filepath.Join(t.TempDir(), "credentials.json"))
+
+			err := f.Set("http://a:8080", "a")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = f.Set("http://b:8080", "b")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = f.Delete("http://a:8080")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = f.Get("http://a:8080")
+			if !assert.ErrorIs(t, err, ErrNotFound) {
+				return
+			}
+
+			secret, err := f.Get("http://b:8080")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "b", secret) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,236 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mediatype detects the media type of content from its leading bytes and file extension.
+package mediatype
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SniffLen is the number of leading content bytes needed by Sniff.
+const SniffLen = 512
+
+// Unknown is the media type of content which couldn't be detected.
+const Unknown = "application/octet-stream"
+
+type signature struct {
+	offset    int
+	magic     []byte
+	mediaType string
+}
+
+// signatures are checked in order so more specific signatures,
+// e.g. EPUB which is a ZIP archive, must come before general ones.
+var signatures = []signature{
+	{offset: 0, magic: []byte("\x89PNG\r\n\x1a\n"), mediaType: "image/png"},
+	{offset: 0, magic: []byte("\xff\xd8\xff"), mediaType: "image/jpeg"},
+	{offset: 0, magic: []byte("GIF87a"), mediaType: "image/gif"},
+	{offset: 0, magic: []byte("GIF89a"), mediaType: "image/gif"},
+	{offset: 0, magic: []byte("%PDF-"), mediaType: "application/pdf"},
+	{offset: 0, magic: []byte("fLaC"), mediaType: "audio/flac"},
+	{offset: 0, magic: []byte("ID3"), mediaType: "audio/mpeg"},
+	{offset: 30, magic: []byte("mimetypeapplication/epub+zip"), mediaType: "application/epub+zip"},
+	{offset: 0, magic: []byte("PK\x03\x04"), mediaType: "application/zip"},
+}
+
+// Sniff detects the media type of content from its leading bytes. At most SniffLen bytes are considered.
+func Sniff(header []byte) (string, bool) {
+	if len(header) > SniffLen {
+		header = header[:SniffLen]
+	}
+
+	for _, sig := range signatures {
+		if hasMagic(header, sig.offset, sig.magic) {
+			return sig.mediaType, true
+		}
+	}
+
+	switch {
+	case hasMagic(header, 4, []byte("ftyp")):
+		return sniffISOBMFF(header), true
+	case hasMagic(header, 0, []byte("\x1a\x45\xdf\xa3")):
+		return sniffEBML(header), true
+	case hasMagic(header, 0, []byte("RIFF")) && hasMagic(header, 8, []byte("WEBP")):
+		return "image/webp", true
+	case hasMagic(header, 0, []byte("DKIF")) && hasMagic(header, 8, []byte("AV01")):
+		return "video/AV1", true
+	case isMP3Frame(header):
+		return "audio/mpeg", true
+	}
+	return "", false
+}
+
+func hasMagic(header []byte, offset int, magic []byte) bool {
+	return len(header) >= offset+len(magic) && bytes.Equal(header[offset:offset+len(magic)], magic)
+}
+
+// sniffISOBMFF uses the major brand of an ISO base media file to
+// distinguish between the formats built on it.
+func sniffISOBMFF(header []byte) string {
+	if len(header) < 12 {
+		return "video/mp4"
+	}
+	switch string(header[8:12]) {
+	case "M4A ", "M4B ":
+		return "audio/mp4"
+	case "qt  ":
+		return "video/quicktime"
+	case "avif":
+		return "image/avif"
+	default:
+		return "video/mp4"
+	}
+}
+
+// sniffEBML uses the EBML DocType to distinguish WebM from Matroska.
+func sniffEBML(header []byte) string {
+	if bytes.Contains(header, []byte("webm")) {
+		return "video/webm"
+	}
+	return "video/x-matroska"
+}
+
+// isMP3Frame checks for the frame sync of an MPEG audio layer III frame,
+// which is how MP3 files without an ID3 tag begin.
+func isMP3Frame(header []byte) bool {
+	if len(header) < 2 {
+		return false
+	}
+	return header[0] == 0xff && header[1]&0xe0 == 0xe0 && header[1]&0x06 == 0x02
+}
+
+var builtinExtensions = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".m4a":  "audio/mp4",
+	".mov":  "video/quicktime",
+	".mkv":  "video/x-matroska",
+	".mka":  "audio/x-matroska",
+	".webm": "video/webm",
+	".flac": "audio/flac",
+	".mp3":  "audio/mpeg",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".avif": "image/avif",
+	".pdf":  "application/pdf",
+	".epub": "application/epub+zip",
+	".zip":  "application/zip",
+	".txt":  "text/plain",
+}
+
+// Detector detects media types using magic bytes and file extensions.
+// Extensions can be mapped to media types by the user, which take
+// precedence over everything else so misdetections can be corrected.
+type Detector struct {
+	userExtensions map[string]string
+}
+
+func NewDetector() *Detector {
+	return &Detector{
+		userExtensions: make(map[string]string),
+	}
+}
+
+type InvalidMappingError struct {
+	Line  int
+	Cause error
+}
+
+func (e InvalidMappingError) Error() string {
+	return fmt.Sprintf("invalid media type mapping on line %d: %s", e.Line, e.Cause)
+}
+
+func (e InvalidMappingError) Unwrap() error {
+	return e.Cause
+}
+
+var ErrMissingExtensions = errors.New("media type must be followed by at least one file extension")
+
+// AddMappings reads extension mappings in the same format as mime.types files,
+// where each line is a media type followed by its file extensions, e.g.
+//
+//	video/x-matroska mkv mk3d
+//
+// Blank lines and lines beginning with # are ignored.
+func (d *Detector) AddMappings(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) == 1 {
+			return InvalidMappingError{Line: line, Cause: ErrMissingExtensions}
+		}
+
+		_, _, err := mime.ParseMediaType(fields[0])
+		if err != nil {
+			return InvalidMappingError{Line: line, Cause: err}
+		}
+		for _, ext := range fields[1:] {
+			d.userExtensions["."+strings.ToLower(strings.TrimPrefix(ext, "."))] = fields[0]
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadMappingFile adds the extension mappings in the named file. See AddMappings for its format.
+func (d *Detector) LoadMappingFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return d.AddMappings(f)
+}
+
+// Detect returns the media type of content with the given file name and leading bytes.
+// User mapped extensions are checked first, then magic bytes and finally the
+// builtin extensions. If none of them match, Unknown is returned.
+func (d *Detector) Detect(filename string, header []byte) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if mediaType, ok := d.userExtensions[ext]; ok {
+		return mediaType
+	}
+	if mediaType, ok := Sniff(header); ok {
+		return mediaType
+	}
+	if mediaType, ok := builtinExtensions[ext]; ok {
+		return mediaType
+	}
+	return Unknown
+}
+
+// Equal reports whether a and b are the same media type, ignoring parameters and case.
+func Equal(a, b string) bool {
+	a, _, errA := mime.ParseMediaType(a)
+	b, _, errB := mime.ParseMediaType(b)
+	return errA == nil && errB == nil && a == b
+}
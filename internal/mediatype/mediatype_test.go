@@ -0,0 +1,158 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mediatype
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Header    string
+		MediaType string
+	}{
+		{Name: "MP4", Header: "\x00\x00\x00\x20ftypisom\x00\x00\x02\x00", MediaType: "video/mp4"},
+		{Name: "M4A", Header: "\x00\x00\x00\x20ftypM4A \x00\x00\x02\x00", MediaType: "audio/mp4"},
+		{Name: "MKV", Header: "\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x88matroska", MediaType: "video/x-matroska"},
+		{Name: "WebM", Header: "\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x84webm", MediaType: "video/webm"},
+		{Name: "AV1 in IVF", Header: "DKIF\x00\x00\x20\x00AV01", MediaType: "video/AV1"},
+		{Name: "FLAC", Header: "fLaC\x00\x00\x00\x22", MediaType: "audio/flac"},
+		{Name: "MP3 with ID3", Header: "ID3\x03\x00", MediaType: "audio/mpeg"},
+		{Name: "MP3 without ID3", Header: "\xff\xfb\x90\x64", MediaType: "audio/mpeg"},
+		{Name: "JPEG", Header: "\xff\xd8\xff\xe0\x00\x10JFIF", MediaType: "image/jpeg"},
+		{Name: "PNG", Header: "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR", MediaType: "image/png"},
+		{Name: "GIF", Header: "GIF89a\x01\x00", MediaType: "image/gif"},
+		{Name: "WebP", Header: "RIFF\x24\x00\x00\x00WEBPVP8 ", MediaType: "image/webp"},
+		{Name: "PDF", Header: "%PDF-1.7\n", MediaType: "application/pdf"},
+		{Name: "EPUB", Header: "PK\x03\x04\x0a\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00\x00\x00mimetypeapplication/epub+zip", MediaType: "application/epub+zip"},
+		{Name: "ZIP", Header: "PK\x03\x04\x14\x00\x00\x00", MediaType: "application/zip"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run("will detect "+testCase.Name, func(t *testing.T) {
+			mediaType, ok := Sniff([]byte(testCase.Header))
+			if !assert.True(t, ok) {
+				return
+			}
+			if !assert.Equal(t, testCase.MediaType, mediaType) {
+				return
+			}
+		})
+	}
+
+	t.Run("will not detect a media type", func(t *testing.T) {
+		t.Run("if the header has no known signature", func(t *testing.T) {
+			_, ok := Sniff([]byte("hello world"))
+			if !assert.False(t, ok) {
+				return
+			}
+		})
+	})
+}
+
+func TestDetector_AddMappings(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if a media type has no extensions", func(t *testing.T) {
+			d := NewDetector()
+			err := d.AddMappings(strings.NewReader("# comment\n\nvideo/mp4\n"))
+
+			var merr InvalidMappingError
+			if !assert.ErrorAs(t, err, &merr) {
+				return
+			}
+			if !assert.Equal(t, 3, merr.Line) {
+				return
+			}
+			if !assert.ErrorIs(t, merr, ErrMissingExtensions) {
+				return
+			}
+		})
+
+		t.Run("if a media type is invalid", func(t *testing.T) {
+			d := NewDetector()
+			err := d.AddMappings(strings.NewReader("video/ mp4\n"))
+
+			var merr InvalidMappingError
+			if !assert.ErrorAs(t, err, &merr) {
+				return
+			}
+			if !assert.NotEmpty(t, merr.Error()) {
+				return
+			}
+		})
+	})
+}
+
+func TestDetector_Detect(t *testing.T) {
+	t.Run("will prefer user mapped extensions", func(t *testing.T) {
+		t.Run("if the magic bytes are also recognized", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "mime.types")
+			err := os.WriteFile(filename, []byte("application/vnd.comicbook+zip cbz\n"), 0o600)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			d := NewDetector()
+			err = d.LoadMappingFile(filename)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			mediaType := d.Detect("comic.CBZ", []byte("PK\x03\x04"))
+			if !assert.Equal(t, "application/vnd.comicbook+zip", mediaType) {
+				return
+			}
+		})
+	})
+
+	t.Run("will prefer magic bytes", func(t *testing.T) {
+		t.Run("if the extension is misleading", func(t *testing.T) {
+			d := NewDetector()
+
+			mediaType := d.Detect("image.jpg", []byte("\x89PNG\r\n\x1a\n"))
+			if !assert.Equal(t, "image/png", mediaType) {
+				return
+			}
+		})
+	})
+
+	t.Run("will fallback to the builtin extensions", func(t *testing.T) {
+		t.Run("if the magic bytes are not recognized", func(t *testing.T) {
+			d := NewDetector()
+
+			mediaType := d.Detect("notes.txt", []byte("hello world"))
+			if !assert.Equal(t, "text/plain", mediaType) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return unknown", func(t *testing.T) {
+		t.Run("if nothing matches", func(t *testing.T) {
+			d := NewDetector()
+
+			mediaType := d.Detect("-", []byte("hello world"))
+			if !assert.Equal(t, Unknown, mediaType) {
+				return
+			}
+		})
+	})
+}
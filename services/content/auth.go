@@ -0,0 +1,110 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrMissingBearerToken = errors.New("missing bearer token")
+
+// authenticator only passes requests on to next if they're
+// authenticated with a token which grants the required scope.
+type authenticator struct {
+	log          *slog.Logger
+	tokens       token.Store
+	bootstrap    *bootstrapToken
+	scope        tokenpb.Scope
+	next         http.Handler
+	protoMarshal func(proto.Message) ([]byte, error)
+}
+
+func (a *authenticator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tok, err := a.authenticate(r.Context(), r)
+	if errors.Is(err, ErrMissingBearerToken) || errors.Is(err, token.ErrInvalidSecret) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="griot"`)
+		writeStatus(a.log, w, a.protoMarshal, humuspb.Code_UNAUTHENTICATED, err.Error())
+		return
+	}
+	if err != nil {
+		a.log.ErrorContext(r.Context(), "failed to verify token", slog.String("error", err.Error()))
+		writeStatus(a.log, w, a.protoMarshal, humuspb.Code_INTERNAL, "failed to verify token")
+		return
+	}
+	if !token.Grants(tok.GetScope(), a.scope) {
+		writeStatus(a.log, w, a.protoMarshal, humuspb.Code_PERMISSION_DENIED, "token requires "+a.scope.String()+" scope")
+		return
+	}
+	a.next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenCtxKey{}, tok)))
+}
+
+type tokenCtxKey struct{}
+
+// callerFromContext returns the user the request was authenticated as. Content
+// is owned by the empty user when authentication isn't enabled.
+func callerFromContext(ctx context.Context) string {
+	tok, _ := ctx.Value(tokenCtxKey{}).(*tokenpb.Token)
+	return tok.GetUser()
+}
+
+func (a *authenticator) authenticate(ctx context.Context, r *http.Request) (*tokenpb.Token, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "authenticator.authenticate")
+	defer span.End()
+
+	scheme, secret, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || secret == "" {
+		return nil, ErrMissingBearerToken
+	}
+
+	secret = strings.TrimSpace(secret)
+	if a.bootstrap.matches(secret) {
+		return a.bootstrap.token, nil
+	}
+
+	tok, err := token.Verify(spanCtx, a.tokens, secret)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return tok, nil
+}
+
+// bootstrapToken is an ADMIN token whose secret is given to the Server rather
+// than minted into the token.Store, so a new deployment can mint its first tokens.
+type bootstrapToken struct {
+	secretHash [sha256.Size]byte
+	token      *tokenpb.Token
+}
+
+func (b *bootstrapToken) matches(secret string) bool {
+	if b == nil {
+		return false
+	}
+	hash := sha256.Sum256([]byte(secret))
+	return subtle.ConstantTimeCompare(hash[:], b.secretHash[:]) == 1
+}
@@ -0,0 +1,286 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/token"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func mintToken(t *testing.T, store token.Store, user string, scope tokenpb.Scope) string {
+	t.Helper()
+
+	_, secret, err := token.Mint(context.Background(), store, "test", user, scope)
+	if !assert.Nil(t, err) {
+		return ""
+	}
+	return secret
+}
+
+type tokenStoreStub struct {
+	get func(context.Context, string) (*tokenpb.Record, error)
+}
+
+func (s tokenStoreStub) Put(ctx context.Context, record *tokenpb.Record) error {
+	return errors.New("not implemented")
+}
+
+func (s tokenStoreStub) Get(ctx context.Context, id string) (*tokenpb.Record, error) {
+	return s.get(ctx, id)
+}
+
+func (s tokenStoreStub) List(ctx context.Context) iter.Seq2[*tokenpb.Record, error] {
+	return func(yield func(*tokenpb.Record, error) bool) {
+		yield(nil, errors.New("not implemented"))
+	}
+}
+
+func (s tokenStoreStub) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func TestAuthenticator(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the request has no bearer token", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokenmemory.New())))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/v1/content")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusUnauthorized, resp.StatusCode) {
+				return
+			}
+			if !assert.Equal(t, `Bearer realm="griot"`, resp.Header.Get("WWW-Authenticate")) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_UNAUTHENTICATED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the bearer token is malformed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokenmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials("not-a-token"))
+
+			_, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNAUTHENTICATED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the token has been revoked", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			listResp, err := c.ListTokens(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, listResp.Tokens, 1) {
+				return
+			}
+
+			err = c.RevokeToken(context.Background(), &RevokeTokenRequest{
+				Id: listResp.Tokens[0].GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNAUTHENTICATED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the token does not grant the required scope", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_READ)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			err := c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_PERMISSION_DENIED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the bearer token does not match the bootstrap admin token", func(t *testing.T) {
+			tokens := tokenmemory.New()
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens), BootstrapAdmin("admin", "hunter2")))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials("hunter3"))
+
+			_, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "first",
+				Scope: tokenpb.Scope_ADMIN,
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNAUTHENTICATED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to get the token record", func(t *testing.T) {
+			tokens := tokenStoreStub{
+				get: func(ctx context.Context, id string) (*tokenpb.Record, error) {
+					return nil, errors.New("failed to get")
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials("griot_0123456789abcdef_secret"))
+
+			_, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will pass the request on", func(t *testing.T) {
+		t.Run("if the token grants the required scope", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_READ)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, names) {
+				return
+			}
+		})
+
+		t.Run("if the token grants a broader scope", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, names) {
+				return
+			}
+		})
+
+		t.Run("if the token is the bootstrap admin token", func(t *testing.T) {
+			tokens := tokenmemory.New()
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens), BootstrapAdmin("admin", "hunter2")))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials("hunter2"))
+
+			resp, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "first",
+				Scope: tokenpb.Scope_ADMIN,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "admin", resp.Token.GetUser()) {
+				return
+			}
+
+			_, err = token.Verify(context.Background(), tokens, resp.Secret)
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+
+		t.Run("if authentication is not enabled", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, names) {
+				return
+			}
+		})
+	})
+}
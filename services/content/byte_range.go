@@ -0,0 +1,137 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxRanges limits how many ranges a single request can ask for since
+// every range is read from Content Storage separately.
+const maxRanges = 100
+
+var (
+	errMalformedRange = errors.New("malformed range")
+	errNoOverlap      = errors.New("range does not overlap content")
+	errTooManyRanges  = errors.New("too many ranges")
+)
+
+// byteRange is a satisfiable range of content bytes.
+type byteRange struct {
+	start  int64
+	length int64
+}
+
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// parseRange parses a Range header, as defined in RFC 9110 Section 14.2,
+// against content of the given size. Ranges which do not overlap the content
+// are dropped and errNoOverlap is only returned if none of the ranges overlap.
+// The remaining ranges are sorted and any which overlap or are adjacent are
+// merged, as RFC 9110 Section 15.3.7.2 allows, so no byte is read twice.
+func parseRange(s string, size int64) ([]byteRange, error) {
+	const unit = "bytes="
+	if !strings.HasPrefix(s, unit) {
+		return nil, errMalformedRange
+	}
+
+	var ranges []byteRange
+	noOverlap := false
+	specs := 0
+	for _, spec := range strings.Split(s[len(unit):], ",") {
+		spec = textproto.TrimString(spec)
+		if spec == "" {
+			continue
+		}
+		specs++
+		if specs > maxRanges {
+			return nil, errTooManyRanges
+		}
+
+		first, last, found := strings.Cut(spec, "-")
+		if !found {
+			return nil, errMalformedRange
+		}
+		first, last = textproto.TrimString(first), textproto.TrimString(last)
+
+		if first == "" {
+			// suffix range, e.g. "-500" is the final 500 bytes
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errMalformedRange
+			}
+			if n == 0 || size == 0 {
+				noOverlap = true
+				continue
+			}
+			n = min(n, size)
+			ranges = append(ranges, byteRange{start: size - n, length: n})
+			continue
+		}
+
+		start, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return nil, errMalformedRange
+		}
+		if start >= size {
+			noOverlap = true
+			continue
+		}
+
+		end := size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return nil, errMalformedRange
+			}
+			end = min(end, size-1)
+		}
+		ranges = append(ranges, byteRange{start: start, length: end - start + 1})
+	}
+	if len(ranges) > 0 {
+		return mergeRanges(ranges), nil
+	}
+	if noOverlap {
+		return nil, errNoOverlap
+	}
+	return nil, errMalformedRange
+}
+
+// mergeRanges sorts the ranges and merges any which overlap or are adjacent.
+func mergeRanges(ranges []byteRange) []byteRange {
+	slices.SortFunc(ranges, func(a, b byteRange) int {
+		return cmp.Compare(a.start, b.start)
+	})
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		end := last.start + last.length
+		if r.start > end {
+			merged = append(merged, r)
+			continue
+		}
+		last.length = max(end, r.start+r.length) - last.start
+	}
+	return merged
+}
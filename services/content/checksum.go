@@ -0,0 +1,165 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/zeebo/blake3"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+type UnsupportedHashFuncError struct {
+	HashFunc contentpb.HashFunc
+}
+
+func (e UnsupportedHashFuncError) Error() string {
+	return fmt.Sprintf("unsupported hash function: %s", e.HashFunc)
+}
+
+type UnknownHashFuncError struct {
+	Value string
+}
+
+func (e UnknownHashFuncError) Error() string {
+	return fmt.Sprintf("unknown hash func value: %s", e.Value)
+}
+
+// hashFuncs is the registry of every supported hash function. Everything
+// which computes or verifies checksums, e.g. the CLI and the Content Service,
+// constructs its hashes from here so they always agree on what's supported.
+var hashFuncs = map[contentpb.HashFunc]func() hash.Hash{
+	contentpb.HashFunc_SHA256:      sha256.New,
+	contentpb.HashFunc_SHA512:      sha512.New,
+	contentpb.HashFunc_SHA3_256:    sha3.New256,
+	contentpb.HashFunc_BLAKE2B_256: newBlake2b256,
+	contentpb.HashFunc_BLAKE3: func() hash.Hash {
+		return blake3.New()
+	},
+}
+
+func newBlake2b256() hash.Hash {
+	// blake2b only fails for keys longer than 64 bytes
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
+// SupportedHashFuncs returns every supported hash function in enum order.
+func SupportedHashFuncs() []contentpb.HashFunc {
+	return slices.Sorted(maps.Keys(hashFuncs))
+}
+
+// ParseHashFunc returns the supported hash function with the given name.
+func ParseHashFunc(name string) (contentpb.HashFunc, error) {
+	v, exists := contentpb.HashFunc_value[name]
+	if !exists {
+		return 0, UnknownHashFuncError{
+			Value: name,
+		}
+	}
+
+	hf := contentpb.HashFunc(v)
+	if _, supported := hashFuncs[hf]; !supported {
+		return 0, UnsupportedHashFuncError{HashFunc: hf}
+	}
+	return hf, nil
+}
+
+// NewHash returns a hash.Hash for computing checksums with the given hash function.
+func NewHash(hf contentpb.HashFunc) (hash.Hash, error) {
+	newHash, supported := hashFuncs[hf]
+	if !supported {
+		return nil, UnsupportedHashFuncError{HashFunc: hf}
+	}
+	return newHash(), nil
+}
+
+type ChecksumMismatchError struct {
+	HashFunc contentpb.HashFunc
+	Expected []byte
+	Actual   []byte
+}
+
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"%s checksum mismatch: expected %s but computed %s",
+		e.HashFunc,
+		base64.StdEncoding.EncodeToString(e.Expected),
+		base64.StdEncoding.EncodeToString(e.Actual),
+	)
+}
+
+// verifyingReader hashes everything read through it and, once the
+// underlying reader is exhausted, returns a ChecksumMismatchError
+// instead of io.EOF if the computed hash does not match the expected
+// checksum. This allows consumers, like Content Storage, to abort
+// before committing content which failed verification.
+type verifyingReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	checksum *contentpb.Checksum
+	n        int64
+}
+
+// NewVerifyingReader returns an io.Reader which reads from r and, once r is exhausted,
+// returns a ChecksumMismatchError instead of io.EOF if the content read does not
+// match the given checksum.
+func NewVerifyingReader(r io.Reader, checksum *contentpb.Checksum) (io.Reader, error) {
+	return newVerifyingReader(r, checksum)
+}
+
+func newVerifyingReader(r io.Reader, checksum *contentpb.Checksum) (*verifyingReader, error) {
+	h, err := NewHash(checksum.GetHashFunc())
+	if err != nil {
+		return nil, err
+	}
+
+	vr := &verifyingReader{
+		r:        r,
+		hash:     h,
+		checksum: checksum,
+	}
+	return vr, nil
+}
+
+func (r *verifyingReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.hash.Write(b[:n])
+	r.n += int64(n)
+	if err != io.EOF {
+		return n, err
+	}
+
+	sum := r.hash.Sum(nil)
+	if !bytes.Equal(sum, r.checksum.GetHash()) {
+		return n, ChecksumMismatchError{
+			HashFunc: r.checksum.GetHashFunc(),
+			Expected: r.checksum.GetHash(),
+			Actual:   sum,
+		}
+	}
+	return n, io.EOF
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHash(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the hash func is not supported", func(t *testing.T) {
+			_, err := NewHash(contentpb.HashFunc(-1))
+
+			var uerr UnsupportedHashFuncError
+			if !assert.ErrorAs(t, err, &uerr) {
+				return
+			}
+			if !assert.NotEmpty(t, uerr.Error()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will compute the expected hash", func(t *testing.T) {
+		testCases := map[contentpb.HashFunc]string{
+			contentpb.HashFunc_SHA256:      "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			contentpb.HashFunc_SHA512:      "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+			contentpb.HashFunc_SHA3_256:    "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+			contentpb.HashFunc_BLAKE2B_256: "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8",
+			contentpb.HashFunc_BLAKE3:      "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262",
+		}
+
+		for _, hf := range SupportedHashFuncs() {
+			t.Run("if the hash func is "+hf.String(), func(t *testing.T) {
+				h, err := NewHash(hf)
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, testCases[hf], hex.EncodeToString(h.Sum(nil))) {
+					return
+				}
+			})
+		}
+	})
+}
+
+func TestParseHashFunc(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the name is not a known hash func", func(t *testing.T) {
+			_, err := ParseHashFunc("MD5")
+
+			var uerr UnknownHashFuncError
+			if !assert.ErrorAs(t, err, &uerr) {
+				return
+			}
+			if !assert.Equal(t, "MD5", uerr.Value) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the hash func", func(t *testing.T) {
+		t.Run("if the name is a supported hash func", func(t *testing.T) {
+			hf, err := ParseHashFunc("BLAKE2B_256")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, contentpb.HashFunc_BLAKE2B_256, hf) {
+				return
+			}
+		})
+	})
+}
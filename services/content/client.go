@@ -13,6 +13,14 @@
 // limitations under the License.
 
 // Package content provides Content Service client and server implementations.
+//
+// The Server is a plain http.Handler whose routes are registered on an http.ServeMux,
+// rather than being built with humus rest, since most of its APIs don't just exchange
+// a single protobuf message. Uploads are streamed from multipart bodies and verified as
+// they're read, downloads stream content with Range and HEAD support and resumable uploads
+// append raw bytes, all of which need direct control over the request and response bodies.
+// Every API still responds with a humuspb.Status on failure, and the Server can be served
+// by any HTTP server.
 package content
 
 import (
@@ -22,11 +30,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/z5labs/griot/services/content/collectionpb"
 	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/indexpb"
+	"github.com/z5labs/griot/services/content/librarypb"
+	"github.com/z5labs/griot/services/content/tokenpb"
 
 	"github.com/z5labs/humus/humuspb"
 	"github.com/z5labs/humus/rest"
@@ -41,26 +60,177 @@ type HttpClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// HttpClientFunc is a func which implements HttpClient. It's
+// mostly useful for implementing Middleware.
+type HttpClientFunc func(*http.Request) (*http.Response, error)
+
+func (f HttpClientFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
 type Client struct {
-	host           string
+	baseUrl        string
 	protoMarshal   func(proto.Message) ([]byte, error)
 	http           HttpClient
 	protoUnmarshal func([]byte, proto.Message) error
+	retry          RetryPolicy
+	header         http.Header
+	timeout        time.Duration
+}
+
+type clientOptions struct {
+	basePath    string
+	header      http.Header
+	timeout     time.Duration
+	middlewares []func(HttpClient) HttpClient
+	retry       RetryPolicy
+}
+
+type ClientOption func(*clientOptions)
+
+// BasePath is prefixed to the path of every API, e.g. if the Content
+// Service is served behind a reverse proxy under /griot, the Upload
+// Content v1 API is sent to /griot/v1/content.
+func BasePath(path string) ClientOption {
+	return func(co *clientOptions) {
+		co.basePath = strings.TrimSuffix(path, "/")
+	}
+}
+
+// Header adds a header which is sent with every request.
+func Header(key, value string) ClientOption {
+	return func(co *clientOptions) {
+		co.header.Add(key, value)
+	}
+}
+
+// UserAgent sets the User-Agent header sent with every request.
+func UserAgent(ua string) ClientOption {
+	return func(co *clientOptions) {
+		co.header.Set("User-Agent", ua)
+	}
 }
 
-func NewClient(hc HttpClient, host string) *Client {
+// Credentials authenticates every request with the given API token secret.
+func Credentials(secret string) ClientOption {
+	return func(co *clientOptions) {
+		co.header.Set("Authorization", "Bearer "+secret)
+	}
+}
+
+// Timeout limits how long every call to the Client may take, including
+// reading the response body, e.g. the content returned by DownloadContent.
+// Each retry of a call gets its own timeout.
+func Timeout(d time.Duration) ClientOption {
+	return func(co *clientOptions) {
+		co.timeout = d
+	}
+}
+
+// Middleware wraps the HttpClient so requests and responses can be
+// inspected or modified. Middlewares are applied in the order given,
+// so the first one sees requests first and responses last.
+func Middleware(middleware func(HttpClient) HttpClient) ClientOption {
+	return func(co *clientOptions) {
+		co.middlewares = append(co.middlewares, middleware)
+	}
+}
+
+// Retry sets the policy for retrying requests which failed with a transient error.
+func Retry(policy RetryPolicy) ClientOption {
+	return func(co *clientOptions) {
+		co.retry = policy
+	}
+}
+
+func NewClient(hc HttpClient, host string, opts ...ClientOption) *Client {
+	co := &clientOptions{
+		header: make(http.Header),
+		retry:  DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(co)
+	}
+
+	for i := len(co.middlewares) - 1; i >= 0; i-- {
+		hc = co.middlewares[i](hc)
+	}
+
 	c := &Client{
-		host:           host,
+		baseUrl:        host + co.basePath,
 		protoMarshal:   proto.Marshal,
 		http:           hc,
 		protoUnmarshal: proto.Unmarshal,
+		retry:          co.retry,
+		header:         co.header,
+		timeout:        co.timeout,
 	}
 	return c
 }
 
+// do sends the request with the default headers and per call timeout applied.
+// Headers which were already set on the request take precedence over the defaults.
+func (c *Client) do(r *http.Request) (*http.Response, error) {
+	for key, values := range c.header {
+		if _, ok := r.Header[key]; ok {
+			continue
+		}
+		r.Header[key] = slices.Clone(values)
+	}
+	if c.timeout <= 0 {
+		return c.http.Do(r)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), c.timeout)
+	resp, err := c.http.Do(r.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose keeps the timeout of a call going until its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (rc cancelOnClose) Close() error {
+	defer rc.cancel()
+	return rc.ReadCloser.Close()
+}
+
 type UploadContentRequest struct {
+	// Metadata must contain a checksum. If the checksum has no hash, the
+	// content is hashed with its hash func while it's being uploaded and
+	// the hash is sent after the content, so content from non-seekable
+	// sources, e.g. stdin, can be uploaded without reading it twice.
 	Metadata *contentpb.Metadata
 	Content  io.Reader
+
+	// OpenContent is used instead of Content if it's set. It's called for
+	// every attempt at uploading the content, so an upload which failed with
+	// a transient error can be retried, and the returned io.ReadCloser is
+	// closed once the attempt is done. Uploads of Content are never retried
+	// since it can't be read again.
+	OpenContent func() (io.ReadCloser, error)
+
+	// Size is the number of bytes in Content, if known.
+	// It's only used for reporting progress.
+	Size int64
+
+	// OnProgress is optionally called every time more of the content has been sent.
+	OnProgress func(Progress)
+}
+
+// Progress describes how much of the content has been uploaded so far.
+type Progress struct {
+	BytesSent int64
+
+	// TotalBytes is the size of the content or 0 if it's unknown.
+	TotalBytes int64
 }
 
 type UploadContentResponse struct {
@@ -69,6 +239,10 @@ type UploadContentResponse struct {
 
 type UnsupportedResponseContentTypeError struct {
 	ContentType string
+
+	// StatusCode is the HTTP status code of the response, which is useful
+	// for telling apart errors from proxies in front of the Content Service.
+	StatusCode int
 }
 
 func (e UnsupportedResponseContentTypeError) Error() string {
@@ -79,26 +253,68 @@ func (c *Client) UploadContent(ctx context.Context, req *UploadContentRequest) (
 	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.UploadContent")
 	defer span.End()
 
+	if req.OpenContent == nil {
+		resp, err := c.uploadContent(spanCtx, req, req.Content)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	// Content IDs are derived from the content checksum, so uploading
+	// the same content again is safe even if the first attempt had
+	// actually succeeded but its response was lost.
+	var resp *UploadContentResponse
+	err := c.retry.do(spanCtx, func(ctx context.Context) error {
+		content, err := req.OpenContent()
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+
+		resp, err = c.uploadContent(ctx, req, content)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) uploadContent(ctx context.Context, req *UploadContentRequest, content io.Reader) (*UploadContentResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.uploadContent")
+	defer span.End()
+
 	body, bodyWriter := io.Pipe()
+	pw := multipart.NewWriter(bodyWriter)
 
 	respCh := make(chan *http.Response, 1)
 	eg, egctx := errgroup.WithContext(spanCtx)
 	eg.Go(func() error {
 		defer bodyWriter.Close()
 
-		return c.writeUploadRequest(egctx, bodyWriter, req)
+		err := c.writeUploadRequest(egctx, pw, req, content)
+		if errors.Is(err, io.ErrClosedPipe) {
+			// The request body was closed by the HTTP client which means
+			// either the request failed or the server has already responded,
+			// both of which are handled by reading the response.
+			return nil
+		}
+		return err
 	})
 	eg.Go(func() error {
 		defer close(respCh)
 		defer body.Close()
 
-		r, err := http.NewRequestWithContext(egctx, http.MethodPost, c.host+"/content/upload", body)
+		r, err := http.NewRequestWithContext(egctx, http.MethodPost, c.baseUrl+"/v1/content", body)
 		if err != nil {
 			return err
 		}
-		r.Header.Set("Content-Type", "multipart/form-data")
+		r.Header.Set("Content-Type", pw.FormDataContentType())
 
-		resp, err := c.http.Do(r)
+		resp, err := c.do(r)
 		if err != nil {
 			return err
 		}
@@ -128,6 +344,7 @@ func (c *Client) UploadContent(ctx context.Context, req *UploadContentRequest) (
 	if contentType != rest.ProtobufContentType {
 		err = UnsupportedResponseContentTypeError{
 			ContentType: contentType,
+			StatusCode:  resp.StatusCode,
 		}
 		span.RecordError(err)
 		return nil, err
@@ -164,18 +381,68 @@ func (c *Client) UploadContent(ctx context.Context, req *UploadContentRequest) (
 	return &uploadResp, nil
 }
 
-func (c *Client) writeUploadRequest(ctx context.Context, w io.Writer, req *UploadContentRequest) error {
+func (c *Client) writeUploadRequest(ctx context.Context, pw *multipart.Writer, req *UploadContentRequest, content io.Reader) error {
 	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.writeUploadRequest")
 	defer span.End()
 
-	pw := multipart.NewWriter(w)
 	err := c.writeMetadata(spanCtx, pw, req.Metadata)
 	if err != nil {
 		return err
 	}
 
-	err = c.writeContent(spanCtx, pw, req.Metadata.Checksum.Hash, req.Content)
+	if req.OnProgress != nil {
+		content = &progressFuncReader{
+			r:          content,
+			progress:   Progress{TotalBytes: req.Size},
+			onProgress: req.OnProgress,
+		}
+	}
+
+	checksum := req.Metadata.GetChecksum()
+	if checksum == nil || len(checksum.GetHash()) > 0 {
+		err = c.writeContent(spanCtx, pw, checksum.GetHash(), content)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = c.writeTrailingChecksumContent(spanCtx, pw, checksum.GetHashFunc(), content)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = pw.Close()
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// writeTrailingChecksumContent writes the content while hashing it and then
+// writes the resulting checksum in a form field after the content.
+func (c *Client) writeTrailingChecksumContent(ctx context.Context, creater partCreater, hashFunc contentpb.HashFunc, r io.Reader) error {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.writeTrailingChecksumContent")
+	defer span.End()
+
+	hasher, err := NewHash(hashFunc)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = c.writeContent(spanCtx, creater, nil, io.TeeReader(r, hasher))
+	if err != nil {
+		return err
+	}
+
+	checksum := &contentpb.Checksum{
+		HashFunc: hashFunc.Enum(),
+		Hash:     hasher.Sum(nil),
+	}
+	err = c.writeProtoPart(creater, "checksum", checksum)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 	return nil
@@ -189,31 +456,35 @@ func (c *Client) writeMetadata(ctx context.Context, creater partCreater, meta *c
 	_, span := otel.Tracer("content").Start(ctx, "Client.writeMetadata")
 	defer span.End()
 
-	b, err := c.protoMarshal(meta)
+	err := c.writeProtoPart(creater, "metadata", meta)
 	if err != nil {
 		span.RecordError(err)
 		return err
 	}
+	return nil
+}
+
+func (c *Client) writeProtoPart(creater partCreater, name string, m proto.Message) error {
+	b, err := c.protoMarshal(m)
+	if err != nil {
+		return err
+	}
 
 	header := make(textproto.MIMEHeader)
-	header.Set("Content-Disposition", `form-data; name="metadata"`)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q`, name))
 	header.Set("Content-Type", rest.ProtobufContentType)
 
 	part, err := creater.CreatePart(header)
 	if err != nil {
-		span.RecordError(err)
 		return err
 	}
 
 	n, err := io.Copy(part, bytes.NewReader(b))
 	if err != nil {
-		span.RecordError(err)
 		return err
 	}
 	if n != int64(len(b)) {
-		err = errors.New("did not write all metadata bytes")
-		span.RecordError(err)
-		return err
+		return errors.New("did not write all " + name + " bytes")
 	}
 	return nil
 }
@@ -238,6 +509,22 @@ func (r *progressReader) Read(b []byte) (int, error) {
 	return n, err
 }
 
+// progressFuncReader reports the total number of bytes read so far to onProgress.
+type progressFuncReader struct {
+	r          io.Reader
+	progress   Progress
+	onProgress func(Progress)
+}
+
+func (r *progressFuncReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.progress.BytesSent += int64(n)
+		r.onProgress(r.progress)
+	}
+	return n, err
+}
+
 func (c *Client) writeContent(ctx context.Context, creater partCreater, hash []byte, r io.Reader) error {
 	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.writeContent")
 	defer span.End()
@@ -253,10 +540,13 @@ func (c *Client) writeContent(ctx context.Context, creater partCreater, hash []b
 		bytesRead: bytesRead,
 	}
 
-	filename := base64.StdEncoding.EncodeToString(hash)
+	disposition := `form-data; name="content"`
+	if len(hash) > 0 {
+		disposition += fmt.Sprintf("; filename=%q", base64.StdEncoding.EncodeToString(hash))
+	}
 
 	header := make(textproto.MIMEHeader)
-	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="content"; filename=%q`, filename))
+	header.Set("Content-Disposition", disposition)
 	header.Set("Content-Type", "application/octet-stream")
 
 	part, err := creater.CreatePart(header)
@@ -272,3 +562,1390 @@ func (c *Client) writeContent(ctx context.Context, creater partCreater, hash []b
 	}
 	return nil
 }
+
+// readProto reads a protobuf encoded message from the response body.
+func (c *Client) readProto(resp *http.Response, m proto.Message) error {
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != rest.ProtobufContentType {
+		return UnsupportedResponseContentTypeError{
+			ContentType: contentType,
+			StatusCode:  resp.StatusCode,
+		}
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return c.protoUnmarshal(b, m)
+}
+
+// readStatus reads the humuspb.Status from an unsuccessful response.
+func (c *Client) readStatus(resp *http.Response) error {
+	var status humuspb.Status
+	err := c.readProto(resp, &status)
+	if err != nil {
+		return err
+	}
+	return &status
+}
+
+type DownloadContentRequest struct {
+	Id string
+
+	// Offset and Length limit the download to a range of the content,
+	// which is useful for resuming interrupted downloads. A zero Length
+	// means the range extends to the end of the content.
+	Offset int64
+	Length int64
+}
+
+type DownloadContentResponse struct {
+	Name      string
+	MediaType *contentpb.MediaType
+
+	// Checksums only contains the indexed checksums whose hash func
+	// is supported by the client. Any others are skipped.
+	Checksums []*contentpb.Checksum
+
+	// Size is always the size of the entire content, while Offset is
+	// where in the content the returned Content begins.
+	Size   int64
+	Offset int64
+
+	// Content must be closed by the caller.
+	Content io.ReadCloser
+}
+
+type InvalidResponseHeaderError struct {
+	Name  string
+	Cause error
+}
+
+func (e InvalidResponseHeaderError) Error() string {
+	return fmt.Sprintf("invalid response header: %s: %s", e.Name, e.Cause)
+}
+
+func (e InvalidResponseHeaderError) Unwrap() error {
+	return e.Cause
+}
+
+func (c *Client) DownloadContent(ctx context.Context, req *DownloadContentRequest) (*DownloadContentResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.DownloadContent")
+	defer span.End()
+
+	if req.Offset < 0 || req.Length < 0 {
+		span.RecordError(ErrInvalidRange)
+		return nil, ErrInvalidRange
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/content/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if req.Offset > 0 || req.Length > 0 {
+		r.Header.Set("Range", formatRange(req.Offset, req.Length))
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	downloadResp, err := readDownloadHeaders(resp.Header)
+	if err != nil {
+		resp.Body.Close()
+		span.RecordError(err)
+		return nil, err
+	}
+	downloadResp.Size = resp.ContentLength
+	if resp.StatusCode == http.StatusPartialContent {
+		downloadResp.Offset, downloadResp.Size, err = parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			err = InvalidResponseHeaderError{
+				Name:  "Content-Range",
+				Cause: err,
+			}
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+	downloadResp.Content = resp.Body
+	return downloadResp, nil
+}
+
+var ErrInvalidRange = errors.New("offset and length must not be negative")
+
+func formatRange(offset, length int64) string {
+	if length == 0 {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+var ErrMalformedContentRange = errors.New("content range must be formatted as bytes {first}-{last}/{size}")
+
+// parseContentRange returns the offset and complete content size from a
+// Content-Range header in the form, "bytes {first}-{last}/{size}".
+func parseContentRange(v string) (offset int64, size int64, err error) {
+	v, found := strings.CutPrefix(v, "bytes ")
+	if !found {
+		return 0, 0, ErrMalformedContentRange
+	}
+
+	first, rest, found := strings.Cut(v, "-")
+	if !found {
+		return 0, 0, ErrMalformedContentRange
+	}
+	_, total, found := strings.Cut(rest, "/")
+	if !found {
+		return 0, 0, ErrMalformedContentRange
+	}
+
+	offset, err = strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, ErrMalformedContentRange
+	}
+	size, err = strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, 0, ErrMalformedContentRange
+	}
+	return offset, size, nil
+}
+
+func readDownloadHeaders(header http.Header) (*DownloadContentResponse, error) {
+	mediaType, err := contentpb.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return nil, InvalidResponseHeaderError{
+			Name:  "Content-Type",
+			Cause: err,
+		}
+	}
+
+	var name string
+	if disposition := header.Get("Content-Disposition"); disposition != "" {
+		_, params, err := mime.ParseMediaType(disposition)
+		if err != nil {
+			return nil, InvalidResponseHeaderError{
+				Name:  "Content-Disposition",
+				Cause: err,
+			}
+		}
+		name = params["filename"]
+	}
+
+	values := header.Values(ChecksumHeader)
+	checksums := make([]*contentpb.Checksum, 0, len(values))
+	for _, v := range values {
+		checksum, err := parseChecksum(v)
+		var unknownErr UnknownHashFuncError
+		var unsupportedErr UnsupportedHashFuncError
+		if errors.As(err, &unknownErr) || errors.As(err, &unsupportedErr) {
+			// the server may support more hash funcs than the client
+			continue
+		}
+		if err != nil {
+			return nil, InvalidResponseHeaderError{
+				Name:  ChecksumHeader,
+				Cause: err,
+			}
+		}
+		checksums = append(checksums, checksum)
+	}
+
+	resp := &DownloadContentResponse{
+		Name:      name,
+		MediaType: mediaType,
+		Checksums: checksums,
+	}
+	return resp, nil
+}
+
+var ErrMalformedChecksum = errors.New("checksum must be formatted as {hash_func}={base64_hash}")
+
+func parseChecksum(v string) (*contentpb.Checksum, error) {
+	name, b64Hash, found := strings.Cut(v, "=")
+	if !found {
+		return nil, ErrMalformedChecksum
+	}
+
+	hashFunc, err := ParseHashFunc(name)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(b64Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := &contentpb.Checksum{
+		HashFunc: hashFunc.Enum(),
+		Hash:     hash,
+	}
+	return checksum, nil
+}
+
+type GetContentMetadataRequest struct {
+	Id string
+}
+
+type GetContentMetadataResponse struct {
+	Record *indexpb.Record
+}
+
+func (c *Client) GetContentMetadata(ctx context.Context, req *GetContentMetadataRequest) (*GetContentMetadataResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.GetContentMetadata")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/content/"+url.PathEscape(req.Id)+"/metadata", nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var record indexpb.Record
+	err = c.readProto(resp, &record)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	metadataResp := &GetContentMetadataResponse{
+		Record: &record,
+	}
+	return metadataResp, nil
+}
+
+type FindByChecksumRequest struct {
+	Checksum *contentpb.Checksum
+}
+
+type FindByChecksumResponse struct {
+	Record *indexpb.Record
+}
+
+// FindByChecksum returns the index record of the content with the given checksum.
+func (c *Client) FindByChecksum(ctx context.Context, req *FindByChecksumRequest) (*FindByChecksumResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.FindByChecksum")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/checksums/"+url.PathEscape(formatChecksum(req.Checksum)), nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var record indexpb.Record
+	err = c.readProto(resp, &record)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	findResp := &FindByChecksumResponse{
+		Record: &record,
+	}
+	return findResp, nil
+}
+
+type ContentExistsRequest struct {
+	Checksum *contentpb.Checksum
+}
+
+type ContentExistsResponse struct {
+	Exists bool
+
+	// Id is only set if the content exists.
+	Id string
+}
+
+// ContentExists checks if content with the given checksum has already been
+// uploaded, which allows uploads of existing content to be skipped entirely.
+func (c *Client) ContentExists(ctx context.Context, req *ContentExistsRequest) (*ContentExistsResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ContentExists")
+	defer span.End()
+
+	findResp, err := c.FindByChecksum(spanCtx, &FindByChecksumRequest{
+		Checksum: req.Checksum,
+	})
+
+	var status *humuspb.Status
+	if errors.As(err, &status) && status.GetCode() == humuspb.Code_NOT_FOUND {
+		return &ContentExistsResponse{}, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	existsResp := &ContentExistsResponse{
+		Exists: true,
+		Id:     findResp.Record.GetContentId().GetValue(),
+	}
+	return existsResp, nil
+}
+
+type ListContentRequest struct {
+	// MediaType is either just a type, e.g. "video", or
+	// a type and subtype, e.g. "video/mp4".
+	MediaType  string
+	NamePrefix string
+
+	// PageSize is the number of records requested per page.
+	// If zero, the server default page size is used.
+	PageSize int
+}
+
+// ListContent returns every index record matching the request. Pages
+// are only requested from the server as the iteration reaches them.
+func (c *Client) ListContent(ctx context.Context, req *ListContentRequest) iter.Seq2[*indexpb.Record, error] {
+	return func(yield func(*indexpb.Record, error) bool) {
+		spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ListContent")
+		defer span.End()
+
+		var pageToken string
+		for {
+			page, err := c.listContentPage(spanCtx, req, pageToken)
+			if err != nil {
+				span.RecordError(err)
+				yield(nil, err)
+				return
+			}
+
+			for _, record := range page.GetRecords() {
+				if !yield(record, nil) {
+					return
+				}
+			}
+
+			pageToken = page.GetNextPageToken()
+			if pageToken == "" {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) listContentPage(ctx context.Context, req *ListContentRequest, pageToken string) (*indexpb.ListContentV1Response, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.listContentPage")
+	defer span.End()
+
+	query := make(url.Values)
+	if req.MediaType != "" {
+		query.Set("media_type", req.MediaType)
+	}
+	if req.NamePrefix != "" {
+		query.Set("name_prefix", req.NamePrefix)
+	}
+	if req.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if pageToken != "" {
+		query.Set("page_token", pageToken)
+	}
+
+	u := c.baseUrl + "/v1/content"
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, u, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var page indexpb.ListContentV1Response
+	err = c.readProto(resp, &page)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return &page, nil
+}
+
+type DeleteContentRequest struct {
+	Id string
+}
+
+func (c *Client) DeleteContent(ctx context.Context, req *DeleteContentRequest) error {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.DeleteContent")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodDelete, c.baseUrl+"/v1/content/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// UploadSession is a resumable upload which content
+// can be appended to across multiple requests.
+type UploadSession struct {
+	Id string
+
+	// Offset is how many bytes of content have been committed to the session.
+	Offset int64
+}
+
+type CreateUploadSessionRequest struct {
+	Metadata *contentpb.Metadata
+}
+
+func (c *Client) CreateUploadSession(ctx context.Context, req *CreateUploadSessionRequest) (*UploadSession, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.CreateUploadSession")
+	defer span.End()
+
+	b, err := c.protoMarshal(req.Metadata)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/uploads", bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	sess, err := c.doUploadSession(r, http.StatusCreated)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return sess, nil
+}
+
+type GetUploadSessionRequest struct {
+	Id string
+}
+
+func (c *Client) GetUploadSession(ctx context.Context, req *GetUploadSessionRequest) (*UploadSession, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.GetUploadSession")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/uploads/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	sess, err := c.doUploadSession(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return sess, nil
+}
+
+type AppendUploadSessionRequest struct {
+	Id string
+
+	// Offset must be the currently committed offset of the session.
+	Offset  int64
+	Content io.Reader
+
+	// Length is the number of bytes in Content, if known.
+	Length int64
+}
+
+// AppendUploadSession appends content to the session. Content is committed
+// as it's received by the server so if the request fails the session
+// should be fetched again to find where to resume from.
+func (c *Client) AppendUploadSession(ctx context.Context, req *AppendUploadSessionRequest) (*UploadSession, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.AppendUploadSession")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPatch, c.baseUrl+"/v1/uploads/"+url.PathEscape(req.Id), req.Content)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/octet-stream")
+	r.Header.Set(UploadOffsetHeader, strconv.FormatInt(req.Offset, 10))
+	if req.Length > 0 {
+		r.ContentLength = req.Length
+	}
+
+	sess, err := c.doUploadSession(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return sess, nil
+}
+
+func (c *Client) doUploadSession(r *http.Request, statusCode int) (*UploadSession, error) {
+	resp, err := c.do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != statusCode {
+		return nil, c.readStatus(resp)
+	}
+
+	var sess contentpb.UploadSession
+	err = c.readProto(resp, &sess)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadSession := &UploadSession{
+		Id:     sess.GetId(),
+		Offset: sess.GetOffset(),
+	}
+	return uploadSession, nil
+}
+
+type CompleteUploadSessionRequest struct {
+	Id string
+}
+
+// CompleteUploadSession verifies all the content committed to the
+// session against its checksum and moves it into Content Storage.
+func (c *Client) CompleteUploadSession(ctx context.Context, req *CompleteUploadSessionRequest) (*UploadContentResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.CompleteUploadSession")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/uploads/"+url.PathEscape(req.Id)+"/complete", nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var uploadV1Resp contentpb.UploadContentV1Response
+	err = c.readProto(resp, &uploadV1Resp)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	uploadResp := &UploadContentResponse{
+		Id: uploadV1Resp.GetId().GetValue(),
+	}
+	return uploadResp, nil
+}
+
+type AbortUploadSessionRequest struct {
+	Id string
+}
+
+func (c *Client) AbortUploadSession(ctx context.Context, req *AbortUploadSessionRequest) error {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.AbortUploadSession")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodDelete, c.baseUrl+"/v1/uploads/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// DefaultUploadChunkSize is the amount of content appended to
+// an upload session per request if no chunk size is given.
+const DefaultUploadChunkSize = 16 << 20
+
+var ErrSessionOffsetBeyondContent = errors.New("upload session offset is beyond the end of the content")
+
+type ResumableUploadContentRequest struct {
+	Metadata *contentpb.Metadata
+
+	// SessionId is the upload session to resume. If it's empty or the
+	// session no longer exists then a new upload session is created.
+	SessionId string
+
+	Content io.ReadSeeker
+
+	// ChunkSize is the max number of bytes appended per request.
+	ChunkSize int64
+
+	// OnSession is called with the id of the upload session before any
+	// content is appended so it can be persisted to resume from later.
+	OnSession func(id string) error
+
+	// OnProgress is optionally called with the offset the session is resumed
+	// from and then every time more of the content has been appended.
+	OnProgress func(Progress)
+}
+
+// ResumableUploadContent uploads content through an upload session,
+// appending it in chunks and starting from wherever the session
+// had previously been committed up to.
+func (c *Client) ResumableUploadContent(ctx context.Context, req *ResumableUploadContentRequest) (*UploadContentResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ResumableUploadContent")
+	defer span.End()
+
+	sess, err := c.resumeUploadSession(spanCtx, req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if req.OnSession != nil {
+		err = req.OnSession(sess.Id)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	size, err := req.Content.Seek(0, io.SeekEnd)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if sess.Offset > size {
+		span.RecordError(ErrSessionOffsetBeyondContent)
+		return nil, ErrSessionOffsetBeyondContent
+	}
+
+	chunkSize := req.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultUploadChunkSize
+	}
+	if req.OnProgress != nil {
+		req.OnProgress(Progress{BytesSent: sess.Offset, TotalBytes: size})
+	}
+
+	for sess.Offset < size {
+		err = c.retry.do(spanCtx, func(ctx context.Context) error {
+			next, err := c.appendChunk(ctx, req, sess, size, chunkSize)
+			if err == nil {
+				sess = next
+				return nil
+			}
+
+			// content is committed as it's received so the
+			// next attempt should resume from wherever it got to
+			current, getErr := c.GetUploadSession(ctx, &GetUploadSessionRequest{
+				Id: sess.Id,
+			})
+			if getErr == nil {
+				sess = current
+			}
+			return err
+		})
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	return c.CompleteUploadSession(spanCtx, &CompleteUploadSessionRequest{
+		Id: sess.Id,
+	})
+}
+
+// appendChunk appends the next chunk of content, starting at the session offset.
+func (c *Client) appendChunk(ctx context.Context, req *ResumableUploadContentRequest, sess *UploadSession, size, chunkSize int64) (*UploadSession, error) {
+	_, err := req.Content.Seek(sess.Offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	length := min(chunkSize, size-sess.Offset)
+	chunk := io.LimitReader(req.Content, length)
+	if req.OnProgress != nil {
+		chunk = &progressFuncReader{
+			r:          chunk,
+			progress:   Progress{BytesSent: sess.Offset, TotalBytes: size},
+			onProgress: req.OnProgress,
+		}
+	}
+
+	return c.AppendUploadSession(ctx, &AppendUploadSessionRequest{
+		Id:      sess.Id,
+		Offset:  sess.Offset,
+		Content: chunk,
+		Length:  length,
+	})
+}
+
+func (c *Client) resumeUploadSession(ctx context.Context, req *ResumableUploadContentRequest) (*UploadSession, error) {
+	if req.SessionId != "" {
+		sess, err := c.GetUploadSession(ctx, &GetUploadSessionRequest{
+			Id: req.SessionId,
+		})
+
+		var status *humuspb.Status
+		if !errors.As(err, &status) || status.GetCode() != humuspb.Code_NOT_FOUND {
+			return sess, err
+		}
+	}
+
+	return c.CreateUploadSession(ctx, &CreateUploadSessionRequest{
+		Metadata: req.Metadata,
+	})
+}
+
+type CreateTokenRequest struct {
+	Name  string
+	Scope tokenpb.Scope
+
+	// User the token authenticates requests as. It defaults
+	// to the user of the token creating it.
+	User string
+}
+
+type CreateTokenResponse struct {
+	Token *tokenpb.Token
+
+	// Secret authenticates requests made with the token. It's
+	// only ever returned when the token is created.
+	Secret string
+}
+
+func (c *Client) CreateToken(ctx context.Context, req *CreateTokenRequest) (*CreateTokenResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.CreateToken")
+	defer span.End()
+
+	b, err := c.protoMarshal(&tokenpb.CreateTokenV1Request{
+		Name:  &req.Name,
+		Scope: req.Scope.Enum(),
+		User:  &req.User,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/tokens", bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var created tokenpb.CreateTokenV1Response
+	err = c.readProto(resp, &created)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	createResp := &CreateTokenResponse{
+		Token:  created.GetToken(),
+		Secret: created.GetSecret(),
+	}
+	return createResp, nil
+}
+
+type ListTokensResponse struct {
+	Tokens []*tokenpb.Token
+}
+
+func (c *Client) ListTokens(ctx context.Context) (*ListTokensResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ListTokens")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/tokens", nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var list tokenpb.ListTokensV1Response
+	err = c.readProto(resp, &list)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	listResp := &ListTokensResponse{
+		Tokens: list.GetTokens(),
+	}
+	return listResp, nil
+}
+
+type RevokeTokenRequest struct {
+	Id string
+}
+
+func (c *Client) RevokeToken(ctx context.Context, req *RevokeTokenRequest) error {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.RevokeToken")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodDelete, c.baseUrl+"/v1/tokens/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+type CreateCollectionRequest struct {
+	Name string
+}
+
+func (c *Client) CreateCollection(ctx context.Context, req *CreateCollectionRequest) (*collectionpb.Collection, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.CreateCollection")
+	defer span.End()
+
+	b, err := c.protoMarshal(&collectionpb.CreateCollectionV1Request{
+		Name: &req.Name,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/collections", bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	col, err := c.doCollection(r, http.StatusCreated)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return col, nil
+}
+
+type ListCollectionsResponse struct {
+	Collections []*collectionpb.Collection
+}
+
+func (c *Client) ListCollections(ctx context.Context) (*ListCollectionsResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ListCollections")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/collections", nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var list collectionpb.ListCollectionsV1Response
+	err = c.readProto(resp, &list)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	listResp := &ListCollectionsResponse{
+		Collections: list.GetCollections(),
+	}
+	return listResp, nil
+}
+
+type GetCollectionRequest struct {
+	Id string
+}
+
+func (c *Client) GetCollection(ctx context.Context, req *GetCollectionRequest) (*collectionpb.Collection, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.GetCollection")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/collections/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	col, err := c.doCollection(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return col, nil
+}
+
+type RenameCollectionRequest struct {
+	Id   string
+	Name string
+}
+
+func (c *Client) RenameCollection(ctx context.Context, req *RenameCollectionRequest) (*collectionpb.Collection, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.RenameCollection")
+	defer span.End()
+
+	b, err := c.protoMarshal(&collectionpb.RenameCollectionV1Request{
+		Name: &req.Name,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPatch, c.baseUrl+"/v1/collections/"+url.PathEscape(req.Id), bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	col, err := c.doCollection(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return col, nil
+}
+
+type AddCollectionItemRequest struct {
+	CollectionId string
+	Type         collectionpb.ItemType
+
+	// ItemId is either a Content ID or a Collection ID, depending on the Type.
+	ItemId string
+
+	// Position is where the item is inserted, starting from 1. The item is
+	// appended if the Position is 0 or past the end of the collection.
+	Position uint32
+}
+
+func (c *Client) AddCollectionItem(ctx context.Context, req *AddCollectionItemRequest) (*collectionpb.Collection, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.AddCollectionItem")
+	defer span.End()
+
+	b, err := c.protoMarshal(&collectionpb.AddCollectionItemV1Request{
+		Type:     req.Type.Enum(),
+		Id:       &req.ItemId,
+		Position: &req.Position,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/collections/"+url.PathEscape(req.CollectionId)+"/items", bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	col, err := c.doCollection(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return col, nil
+}
+
+type RemoveCollectionItemRequest struct {
+	CollectionId string
+	ItemId       string
+}
+
+func (c *Client) RemoveCollectionItem(ctx context.Context, req *RemoveCollectionItemRequest) error {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.RemoveCollectionItem")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodDelete, c.collectionItemUrl(req.CollectionId, req.ItemId), nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+type ReorderCollectionItemRequest struct {
+	CollectionId string
+	ItemId       string
+
+	// Position is where the item is moved to, starting from 1. The item is moved
+	// to the end if the Position is 0 or past the end of the collection.
+	Position uint32
+}
+
+func (c *Client) ReorderCollectionItem(ctx context.Context, req *ReorderCollectionItemRequest) (*collectionpb.Collection, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ReorderCollectionItem")
+	defer span.End()
+
+	b, err := c.protoMarshal(&collectionpb.ReorderCollectionItemV1Request{
+		Position: &req.Position,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPatch, c.collectionItemUrl(req.CollectionId, req.ItemId), bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	col, err := c.doCollection(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return col, nil
+}
+
+func (c *Client) collectionItemUrl(collectionId, itemId string) string {
+	return c.baseUrl + "/v1/collections/" + url.PathEscape(collectionId) + "/items/" + url.PathEscape(itemId)
+}
+
+func (c *Client) doCollection(r *http.Request, statusCode int) (*collectionpb.Collection, error) {
+	resp, err := c.do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != statusCode {
+		return nil, c.readStatus(resp)
+	}
+
+	var col collectionpb.Collection
+	err = c.readProto(resp, &col)
+	if err != nil {
+		return nil, err
+	}
+	return &col, nil
+}
+
+type CreateLibraryRequest struct {
+	Name string
+}
+
+func (c *Client) CreateLibrary(ctx context.Context, req *CreateLibraryRequest) (*librarypb.Library, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.CreateLibrary")
+	defer span.End()
+
+	b, err := c.protoMarshal(&librarypb.CreateLibraryV1Request{
+		Name: &req.Name,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/libraries", bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	l, err := c.doLibrary(r, http.StatusCreated)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return l, nil
+}
+
+type ListLibrariesRequest struct {
+	// Name only lists the Library with this name, if there is one.
+	Name string
+}
+
+type ListLibrariesResponse struct {
+	Libraries []*librarypb.Library
+}
+
+func (c *Client) ListLibraries(ctx context.Context, req *ListLibrariesRequest) (*ListLibrariesResponse, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.ListLibraries")
+	defer span.End()
+
+	u := c.baseUrl + "/v1/libraries"
+	if req.Name != "" {
+		u += "?" + url.Values{"name": {req.Name}}.Encode()
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, u, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var list librarypb.ListLibrariesV1Response
+	err = c.readProto(resp, &list)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	listResp := &ListLibrariesResponse{
+		Libraries: list.GetLibraries(),
+	}
+	return listResp, nil
+}
+
+type GetLibraryRequest struct {
+	Id string
+}
+
+func (c *Client) GetLibrary(ctx context.Context, req *GetLibraryRequest) (*librarypb.Library, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.GetLibrary")
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodGet, c.baseUrl+"/v1/libraries/"+url.PathEscape(req.Id), nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	l, err := c.doLibrary(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return l, nil
+}
+
+type AddLibraryItemRequest struct {
+	LibraryId string
+	Type      collectionpb.ItemType
+
+	// ItemId is either a Content ID or a Collection ID, depending on the Type.
+	ItemId string
+}
+
+func (c *Client) AddLibraryItem(ctx context.Context, req *AddLibraryItemRequest) (*librarypb.Library, error) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.AddLibraryItem")
+	defer span.End()
+
+	b, err := c.protoMarshal(&librarypb.AddLibraryItemV1Request{
+		Type: req.Type.Enum(),
+		Id:   &req.ItemId,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodPost, c.baseUrl+"/v1/libraries/"+url.PathEscape(req.LibraryId)+"/items", bytes.NewReader(b))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	r.Header.Set("Content-Type", rest.ProtobufContentType)
+
+	l, err := c.doLibrary(r, http.StatusOK)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return l, nil
+}
+
+type RemoveLibraryItemRequest struct {
+	LibraryId string
+	ItemId    string
+}
+
+func (c *Client) RemoveLibraryItem(ctx context.Context, req *RemoveLibraryItemRequest) error {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "Client.RemoveLibraryItem")
+	defer span.End()
+
+	u := c.baseUrl + "/v1/libraries/" + url.PathEscape(req.LibraryId) + "/items/" + url.PathEscape(req.ItemId)
+	r, err := http.NewRequestWithContext(spanCtx, http.MethodDelete, u, nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err = c.readStatus(resp)
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Client) doLibrary(r *http.Request, statusCode int) (*librarypb.Library, error) {
+	resp, err := c.do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != statusCode {
+		return nil, c.readStatus(resp)
+	}
+
+	var l librarypb.Library
+	err = c.readProto(resp, &l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
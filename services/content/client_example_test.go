@@ -23,9 +23,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/z5labs/griot/internal/ptr"
 	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
 
 	"github.com/z5labs/humus/rest"
 	"google.golang.org/protobuf/proto"
@@ -89,3 +91,31 @@ func ExampleClient_UploadContent() {
 	fmt.Println(resp.Id)
 	//Output: example-id
 }
+
+func ExampleNewClient() {
+	srv := httptest.NewServer(http.StripPrefix("/griot", NewServer(nil, memory.New())))
+	defer srv.Close()
+
+	logRequests := func(next HttpClient) HttpClient {
+		return HttpClientFunc(func(r *http.Request) (*http.Response, error) {
+			fmt.Println(r.Method, r.URL.Path, r.Header.Get("User-Agent"))
+			return next.Do(r)
+		})
+	}
+
+	c := NewClient(
+		http.DefaultClient,
+		srv.URL,
+		BasePath("/griot"),
+		UserAgent("example/1.0"),
+		Timeout(10*time.Second),
+		Middleware(logRequests),
+	)
+
+	_, err := c.GetContentMetadata(context.Background(), &GetContentMetadataRequest{
+		Id: "example-id",
+	})
+	fmt.Println(err != nil)
+	//Output: GET /griot/v1/content/example-id/metadata example/1.0
+	// true
+}
@@ -17,6 +17,8 @@ package content
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -24,8 +26,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/z5labs/humus/humuspb"
@@ -343,4 +347,198 @@ func TestClient_UploadContent(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("will report the upload progress", func(t *testing.T) {
+		t.Run("if an OnProgress func is given", func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+				_, err := io.Copy(io.Discard, r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				b, err := proto.Marshal(&contentpb.UploadContentV1Response{})
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				w.Header().Set("Content-Type", rest.ProtobufContentType)
+				w.WriteHeader(http.StatusOK)
+				io.Copy(w, bytes.NewReader(b))
+			}))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			var progress []Progress
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: &contentpb.Checksum{},
+				},
+				Content: strings.NewReader("hello world"),
+				Size:    int64(len("hello world")),
+				OnProgress: func(p Progress) {
+					progress = append(progress, p)
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.NotEmpty(t, progress) {
+				return
+			}
+
+			expected := Progress{BytesSent: 11, TotalBytes: 11}
+			if !assert.Equal(t, expected, progress[len(progress)-1]) {
+				return
+			}
+		})
+	})
+}
+
+func TestClient_DownloadContent(t *testing.T) {
+	t.Run("will skip a checksum", func(t *testing.T) {
+		t.Run("if its hash func is unknown to the client", func(t *testing.T) {
+			hash := sha256.Sum256([]byte("hello"))
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Content-Length", "5")
+				w.Header().Add(ChecksumHeader, "SHA256="+base64.StdEncoding.EncodeToString(hash[:]))
+				w.Header().Add(ChecksumHeader, "FUTURE_HASH=aGVsbG8=")
+				w.WriteHeader(http.StatusOK)
+				io.WriteString(w, "hello")
+			}))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id: "id",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Content.Close()
+
+			if !assert.Len(t, resp.Checksums, 1) {
+				return
+			}
+			if !assert.Equal(t, contentpb.HashFunc_SHA256, resp.Checksums[0].GetHashFunc()) {
+				return
+			}
+			if !assert.Equal(t, hash[:], resp.Checksums[0].GetHash()) {
+				return
+			}
+		})
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("will prefix every API path", func(t *testing.T) {
+		t.Run("if a base path is given", func(t *testing.T) {
+			srv := httptest.NewServer(http.StripPrefix("/griot", NewServer(nil, memory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, BasePath("/griot/"))
+
+			_, err := c.GetContentMetadata(context.Background(), &GetContentMetadataRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			// a NOT_FOUND status means the request reached the GetContentMetadata handler
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will send the default headers", func(t *testing.T) {
+		t.Run("unless the request already set them", func(t *testing.T) {
+			var header http.Header
+			hc := httpClientFunc(func(r *http.Request) (*http.Response, error) {
+				header = r.Header
+				return nil, errors.New("failed")
+			})
+
+			c := NewClient(
+				hc,
+				"",
+				Header("X-Api-Key", "key"),
+				Header("Content-Type", "text/plain"),
+				UserAgent("griot-test"),
+			)
+
+			_, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{},
+			})
+			if !assert.NotNil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "key", header.Get("X-Api-Key")) {
+				return
+			}
+			if !assert.Equal(t, "griot-test", header.Get("User-Agent")) {
+				return
+			}
+			if !assert.Equal(t, rest.ProtobufContentType, header.Get("Content-Type")) {
+				return
+			}
+		})
+	})
+
+	t.Run("will apply the middlewares", func(t *testing.T) {
+		t.Run("in the order they are given", func(t *testing.T) {
+			var calls []string
+			middleware := func(name string) func(HttpClient) HttpClient {
+				return func(next HttpClient) HttpClient {
+					return HttpClientFunc(func(r *http.Request) (*http.Response, error) {
+						calls = append(calls, name)
+						return next.Do(r)
+					})
+				}
+			}
+
+			hc := httpClientFunc(func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, "http")
+				return nil, errors.New("failed")
+			})
+
+			c := NewClient(hc, "", Middleware(middleware("first")), Middleware(middleware("second")))
+
+			err := c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: "id",
+			})
+			if !assert.NotNil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"first", "second", "http"}, calls) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if a call takes longer than the timeout", func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			}))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Timeout(10*time.Millisecond))
+
+			_, err := c.GetContentMetadata(context.Background(), &GetContentMetadataRequest{
+				Id: "id",
+			})
+			if !assert.ErrorIs(t, err, context.DeadlineExceeded) {
+				return
+			}
+		})
+	})
 }
@@ -0,0 +1,175 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package boltdb implements a persistent collection.Store embedded
+// in a single file using bbolt, a pure Go key/value store.
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"time"
+
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	bolt "go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var collectionsBucket = []byte("collections")
+
+// Store is a collection.Store persisted to a bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens, or creates, the collection database file at the given path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(collectionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func putCollection(tx *bolt.Tx, owner, id string, c *collectionpb.Collection) error {
+	b, err := proto.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(collectionsBucket).Put([]byte(collection.Key(owner, id)), b)
+}
+
+func getCollection(tx *bolt.Tx, owner, id string) (*collectionpb.Collection, error) {
+	b := tx.Bucket(collectionsBucket).Get([]byte(collection.Key(owner, id)))
+	if b == nil {
+		return nil, collection.ErrNotFound
+	}
+
+	var c collectionpb.Collection
+	err := proto.Unmarshal(b, &c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *Store) Put(ctx context.Context, c *collectionpb.Collection) error {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Put")
+	defer span.End()
+
+	if c.GetId() == "" {
+		return collection.ErrMissingCollectionId
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return putCollection(tx, c.GetOwner(), c.GetId(), c)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, owner, id string) (*collectionpb.Collection, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Get")
+	defer span.End()
+
+	var c *collectionpb.Collection
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		c, err = getCollection(tx, owner, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// List reads every collection of the owner in a single read transaction before
+// yielding any of them so consumers are free to modify the store while iterating.
+func (s *Store) List(ctx context.Context, owner string) iter.Seq2[*collectionpb.Collection, error] {
+	return func(yield func(*collectionpb.Collection, error) bool) {
+		prefix := []byte(collection.Key(owner, ""))
+
+		var collections []*collectionpb.Collection
+		err := s.db.View(func(tx *bolt.Tx) error {
+			cur := tx.Bucket(collectionsBucket).Cursor()
+			for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+				var c collectionpb.Collection
+				err := proto.Unmarshal(v, &c)
+				if err != nil {
+					return err
+				}
+				collections = append(collections, &c)
+			}
+			return nil
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, c := range collections {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(c, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Update(ctx context.Context, owner, id string, update func(*collectionpb.Collection) error) (*collectionpb.Collection, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Update")
+	defer span.End()
+
+	var c *collectionpb.Collection
+	err := s.db.Update(func(tx *bolt.Tx) (err error) {
+		c, err = getCollection(tx, owner, id)
+		if err != nil {
+			return err
+		}
+		err = update(c)
+		if err != nil {
+			return err
+		}
+		return putCollection(tx, owner, id, c)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collection/collectiontest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	collectiontest.Run(t, func(t *testing.T) collection.Store {
+		s, err := Open(filepath.Join(t.TempDir(), "collections.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			s.Close()
+		})
+		return s
+	})
+
+	t.Run("will persist collections", func(t *testing.T) {
+		t.Run("if the store is reopened", func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "collections.db")
+
+			s, err := Open(path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			c, err := collection.Create(ctx, s, "bob", "Naruto")
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = s.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			s, err = Open(path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer s.Close()
+
+			got, err := s.Get(ctx, "bob", c.GetId())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "Naruto", got.GetName()) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collection defines the Collections users curate their content into.
+//
+// A Collection is an ordered list of items, each of which is either a piece of
+// content or another Collection. Items are ordered by a rank instead of their
+// position, so inserting or moving an item never changes any other item.
+package collection
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/collectionpb"
+)
+
+var (
+	ErrNotFound            = errors.New("collection not found")
+	ErrMissingCollectionId = errors.New("collection is missing collection id")
+	ErrItemNotFound        = errors.New("collection item not found")
+	ErrItemExists          = errors.New("collection already contains the item")
+)
+
+// Store keeps every Collection keyed by its owner and Collection ID.
+type Store interface {
+	// Put creates or replaces the Collection for its owner and Collection ID.
+	Put(ctx context.Context, c *collectionpb.Collection) error
+
+	Get(ctx context.Context, owner, id string) (*collectionpb.Collection, error)
+
+	// List returns all Collections of the owner ordered by Collection ID.
+	List(ctx context.Context, owner string) iter.Seq2[*collectionpb.Collection, error]
+
+	// Update atomically applies update to the owner's Collection and stores the result.
+	// Nothing is stored if update returns an error, which is then returned by Update.
+	Update(ctx context.Context, owner, id string, update func(*collectionpb.Collection) error) (*collectionpb.Collection, error)
+}
+
+// Key returns the key a Store keeps the owner's Collection under. The keys of
+// all Collections of an owner share the prefix returned by Key(owner, "").
+func Key(owner, id string) string {
+	return owner + "\x00" + id
+}
+
+// Create creates a new, empty Collection with the given name for the owner.
+func Create(ctx context.Context, store Store, owner, name string) (*collectionpb.Collection, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &collectionpb.Collection{
+		Id:    ptr.Ref(hex.EncodeToString(id)),
+		Name:  ptr.Ref(name),
+		Owner: ptr.Ref(owner),
+	}
+	err = store.Put(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// AddItem inserts the item at the given position, starting from 1, and sets its rank.
+// The item is appended if the position is 0 or past the end of the collection.
+func AddItem(c *collectionpb.Collection, item *collectionpb.Item, position int) error {
+	if slices.ContainsFunc(c.Items, hasId(item.GetId())) {
+		return ErrItemExists
+	}
+	insertItem(c, item, position)
+	return nil
+}
+
+// RemoveItem removes the item with the given ID.
+func RemoveItem(c *collectionpb.Collection, id string) error {
+	i := slices.IndexFunc(c.Items, hasId(id))
+	if i < 0 {
+		return ErrItemNotFound
+	}
+	c.Items = slices.Delete(c.Items, i, i+1)
+	return nil
+}
+
+// MoveItem moves the item with the given ID to the given position, starting from 1.
+// The item is moved to the end if the position is 0 or past the end of the collection.
+func MoveItem(c *collectionpb.Collection, id string, position int) error {
+	i := slices.IndexFunc(c.Items, hasId(id))
+	if i < 0 {
+		return ErrItemNotFound
+	}
+	item := c.Items[i]
+	c.Items = slices.Delete(c.Items, i, i+1)
+	insertItem(c, item, position)
+	return nil
+}
+
+func hasId(id string) func(*collectionpb.Item) bool {
+	return func(item *collectionpb.Item) bool {
+		return item.GetId() == id
+	}
+}
+
+func insertItem(c *collectionpb.Collection, item *collectionpb.Item, position int) {
+	i := position - 1
+	if position <= 0 || i > len(c.Items) {
+		i = len(c.Items)
+	}
+
+	var before, after string
+	if i > 0 {
+		before = c.Items[i-1].GetRank()
+	}
+	if i < len(c.Items) {
+		after = c.Items[i].GetRank()
+	}
+	item.Rank = ptr.Ref(rankBetween(before, after))
+	c.Items = slices.Insert(c.Items, i, item)
+}
+
+// rankDigits are ordered by their byte values so ranks can be compared as strings.
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// rankBetween returns a rank ordered after a and before b, either of which may be
+// empty to leave that side unbounded. Ranks never end with the smallest digit, so
+// there's always room for another rank before any of them.
+func rankBetween(a, b string) string {
+	if b != "" {
+		// a is padded with the smallest digit to compare it against b
+		n := 0
+		for n < len(b) && rankDigit(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankBetween(a[min(n, len(a)):], b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+
+	if b == "" && a != "" && digitA+1 < len(rankDigits) {
+		// appending is the most common insert, so stepping to the next
+		// digit instead of halving the gap keeps ranks short for longer
+		return rankDigits[digitA+1 : digitA+2]
+	}
+	if digitB-digitA > 1 {
+		mid := (digitA + digitB + 1) / 2
+		return rankDigits[mid : mid+1]
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	if a == "" {
+		return rankDigits[:1] + rankBetween("", "")
+	}
+	return a[:1] + rankBetween(a[1:], "")
+}
+
+func rankDigit(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
@@ -0,0 +1,248 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newItem(id string) *collectionpb.Item {
+	return &collectionpb.Item{
+		Type: collectionpb.ItemType_CONTENT.Enum(),
+		Id:   ptr.Ref(id),
+	}
+}
+
+func itemIds(c *collectionpb.Collection) []string {
+	ids := make([]string, 0, len(c.Items))
+	for _, item := range c.Items {
+		ids = append(ids, item.GetId())
+	}
+	return ids
+}
+
+func itemRanks(c *collectionpb.Collection) []string {
+	ranks := make([]string, 0, len(c.Items))
+	for _, item := range c.Items {
+		ranks = append(ranks, item.GetRank())
+	}
+	return ranks
+}
+
+func TestAddItem(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the collection already contains the item", func(t *testing.T) {
+			c := &collectionpb.Collection{}
+			err := AddItem(c, newItem("a"), 0)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = AddItem(c, newItem("a"), 0)
+			if !assert.ErrorIs(t, err, ErrItemExists) {
+				return
+			}
+		})
+	})
+
+	t.Run("will insert the item", func(t *testing.T) {
+		t.Run("at the given position", func(t *testing.T) {
+			c := &collectionpb.Collection{}
+			for _, id := range []string{"a", "c"} {
+				err := AddItem(c, newItem(id), 0)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			err := AddItem(c, newItem("b"), 2)
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = AddItem(c, newItem("first"), 1)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"first", "a", "b", "c"}, itemIds(c)) {
+				return
+			}
+			if !assert.True(t, slices.IsSorted(itemRanks(c))) {
+				return
+			}
+		})
+
+		t.Run("at the end if the position is past the end", func(t *testing.T) {
+			c := &collectionpb.Collection{}
+			for _, id := range []string{"a", "b"} {
+				err := AddItem(c, newItem(id), 10)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+			if !assert.Equal(t, []string{"a", "b"}, itemIds(c)) {
+				return
+			}
+		})
+
+		t.Run("without changing the rank of any other item", func(t *testing.T) {
+			c := &collectionpb.Collection{}
+			for _, id := range []string{"a", "b"} {
+				err := AddItem(c, newItem(id), 0)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+			ranks := itemRanks(c)
+
+			err := AddItem(c, newItem("between"), 2)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, ranks[0], c.Items[0].GetRank()) {
+				return
+			}
+			if !assert.Equal(t, ranks[1], c.Items[2].GetRank()) {
+				return
+			}
+		})
+	})
+}
+
+func TestRemoveItem(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the collection does not contain the item", func(t *testing.T) {
+			err := RemoveItem(&collectionpb.Collection{}, "a")
+			if !assert.ErrorIs(t, err, ErrItemNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the item", func(t *testing.T) {
+		t.Run("if the collection contains it", func(t *testing.T) {
+			c := &collectionpb.Collection{}
+			for _, id := range []string{"a", "b", "c"} {
+				err := AddItem(c, newItem(id), 0)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			err := RemoveItem(c, "b")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"a", "c"}, itemIds(c)) {
+				return
+			}
+		})
+	})
+}
+
+func TestMoveItem(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the collection does not contain the item", func(t *testing.T) {
+			err := MoveItem(&collectionpb.Collection{}, "a", 1)
+			if !assert.ErrorIs(t, err, ErrItemNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will move the item", func(t *testing.T) {
+		c := &collectionpb.Collection{}
+		for _, id := range []string{"a", "b", "c"} {
+			err := AddItem(c, newItem(id), 0)
+			if !assert.Nil(t, err) {
+				return
+			}
+		}
+
+		t.Run("to the given position", func(t *testing.T) {
+			err := MoveItem(c, "c", 1)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"c", "a", "b"}, itemIds(c)) {
+				return
+			}
+			if !assert.True(t, slices.IsSorted(itemRanks(c))) {
+				return
+			}
+		})
+
+		t.Run("to the end if the position is 0", func(t *testing.T) {
+			err := MoveItem(c, "c", 0)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"a", "b", "c"}, itemIds(c)) {
+				return
+			}
+		})
+	})
+}
+
+func TestRankBetween(t *testing.T) {
+	t.Run("will return a rank ordered between the given ranks", func(t *testing.T) {
+		t.Run("if items are repeatedly inserted anywhere", func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, 2))
+
+			var ranks []string
+			for range 1000 {
+				i := r.IntN(len(ranks) + 1)
+
+				var before, after string
+				if i > 0 {
+					before = ranks[i-1]
+				}
+				if i < len(ranks) {
+					after = ranks[i]
+				}
+				rank := rankBetween(before, after)
+				if !assert.Less(t, before, rank) {
+					return
+				}
+				if after != "" && !assert.Less(t, rank, after) {
+					return
+				}
+				if !assert.False(t, strings.HasSuffix(rank, rankDigits[:1])) {
+					return
+				}
+				ranks = slices.Insert(ranks, i, rank)
+			}
+		})
+	})
+
+	t.Run("will keep ranks short", func(t *testing.T) {
+		t.Run("if items are repeatedly appended", func(t *testing.T) {
+			var rank string
+			for range 1000 {
+				rank = rankBetween(rank, "")
+			}
+			if !assert.LessOrEqual(t, len(rank), 40) {
+				return
+			}
+		})
+	})
+}
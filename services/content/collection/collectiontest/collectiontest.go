@@ -0,0 +1,206 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collectiontest provides a conformance test suite for collection.Store implementations.
+package collectiontest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func names(t *testing.T, collections func(yield func(*collectionpb.Collection, error) bool)) []string {
+	t.Helper()
+
+	var ns []string
+	for c, err := range collections {
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		ns = append(ns, c.GetName())
+	}
+	return ns
+}
+
+func newItem(id string) *collectionpb.Item {
+	return &collectionpb.Item{
+		Type: collectionpb.ItemType_CONTENT.Enum(),
+		Id:   ptr.Ref(id),
+	}
+}
+
+// Run runs the conformance test suite against the collection.Store returned by newStore.
+// A new collection.Store is requested for every test.
+func Run(t *testing.T, newStore func(*testing.T) collection.Store) {
+	ctx := context.Background()
+
+	t.Run("Put", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the collection is missing a collection id", func(t *testing.T) {
+				s := newStore(t)
+
+				err := s.Put(ctx, &collectionpb.Collection{})
+				if !assert.ErrorIs(t, err, collection.ErrMissingCollectionId) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the collection does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := s.Get(ctx, "bob", "0123456789abcdef")
+				if !assert.ErrorIs(t, err, collection.ErrNotFound) {
+					return
+				}
+			})
+
+			t.Run("if the collection belongs to another owner", func(t *testing.T) {
+				s := newStore(t)
+
+				c, err := collection.Create(ctx, s, "alice", "Naruto")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = s.Get(ctx, "bob", c.GetId())
+				if !assert.ErrorIs(t, err, collection.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the collection", func(t *testing.T) {
+			t.Run("if it was created", func(t *testing.T) {
+				s := newStore(t)
+
+				c, err := collection.Create(ctx, s, "bob", "Naruto")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				got, err := s.Get(ctx, "bob", c.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(c, got)) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Run("will only return the collections of the owner", func(t *testing.T) {
+			s := newStore(t)
+
+			for _, c := range []struct{ owner, name string }{
+				{owner: "bob", name: "Naruto"},
+				{owner: "bobby", name: "Bleach"},
+				{owner: "alice", name: "One Piece"},
+				{owner: "bob", name: "Season 1"},
+			} {
+				_, err := collection.Create(ctx, s, c.owner, c.name)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			if !assert.ElementsMatch(t, []string{"Naruto", "Season 1"}, names(t, s.List(ctx, "bob"))) {
+				return
+			}
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the collection does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := s.Update(ctx, "bob", "0123456789abcdef", func(c *collectionpb.Collection) error {
+					return nil
+				})
+				if !assert.ErrorIs(t, err, collection.ErrNotFound) {
+					return
+				}
+			})
+
+			t.Run("if the update fails", func(t *testing.T) {
+				s := newStore(t)
+
+				c, err := collection.Create(ctx, s, "bob", "Naruto")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				updateErr := errors.New("failed to update")
+				_, err = s.Update(ctx, "bob", c.GetId(), func(c *collectionpb.Collection) error {
+					c.Name = ptr.Ref("Bleach")
+					return updateErr
+				})
+				if !assert.ErrorIs(t, err, updateErr) {
+					return
+				}
+
+				got, err := s.Get(ctx, "bob", c.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, "Naruto", got.GetName()) {
+					return
+				}
+			})
+		})
+
+		t.Run("will store the updated collection", func(t *testing.T) {
+			t.Run("if the update succeeds", func(t *testing.T) {
+				s := newStore(t)
+
+				c, err := collection.Create(ctx, s, "bob", "Naruto")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				updated, err := s.Update(ctx, "bob", c.GetId(), func(c *collectionpb.Collection) error {
+					return collection.AddItem(c, newItem("a"), 0)
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Len(t, updated.GetItems(), 1) {
+					return
+				}
+
+				got, err := s.Get(ctx, "bob", c.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(updated, got)) {
+					return
+				}
+			})
+		})
+	})
+}
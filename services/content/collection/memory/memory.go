@@ -0,0 +1,113 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements an in-memory collection.Store.
+package memory
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collectionpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Store is a collection.Store which keeps all collections in memory.
+// It's primarily intended for testing and ephemeral deployments.
+type Store struct {
+	mu          sync.RWMutex
+	collections map[string]*collectionpb.Collection
+}
+
+func New() *Store {
+	return &Store{
+		collections: make(map[string]*collectionpb.Collection),
+	}
+}
+
+func (s *Store) Put(ctx context.Context, c *collectionpb.Collection) error {
+	if c.GetId() == "" {
+		return collection.ErrMissingCollectionId
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collections[collection.Key(c.GetOwner(), c.GetId())] = proto.Clone(c).(*collectionpb.Collection)
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, owner, id string) (*collectionpb.Collection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, exists := s.collections[collection.Key(owner, id)]
+	if !exists {
+		return nil, collection.ErrNotFound
+	}
+	return proto.Clone(c).(*collectionpb.Collection), nil
+}
+
+// List snapshots the collections before yielding any of them
+// so consumers are free to modify the store while iterating.
+func (s *Store) List(ctx context.Context, owner string) iter.Seq2[*collectionpb.Collection, error] {
+	return func(yield func(*collectionpb.Collection, error) bool) {
+		prefix := collection.Key(owner, "")
+
+		s.mu.RLock()
+		var collections []*collectionpb.Collection
+		for _, key := range slices.Sorted(maps.Keys(s.collections)) {
+			if strings.HasPrefix(key, prefix) {
+				collections = append(collections, proto.Clone(s.collections[key]).(*collectionpb.Collection))
+			}
+		}
+		s.mu.RUnlock()
+
+		for _, c := range collections {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(c, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Update(ctx context.Context, owner, id string, update func(*collectionpb.Collection) error) (*collectionpb.Collection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := collection.Key(owner, id)
+	c, exists := s.collections[key]
+	if !exists {
+		return nil, collection.ErrNotFound
+	}
+
+	c = proto.Clone(c).(*collectionpb.Collection)
+	err := update(c)
+	if err != nil {
+		return nil, err
+	}
+	s.collections[key] = c
+	return proto.Clone(c).(*collectionpb.Collection), nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collection/collectiontest"
+)
+
+func TestStore(t *testing.T) {
+	collectiontest.Run(t, func(t *testing.T) collection.Store {
+		return New()
+	})
+}
@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: create_collection_v1_request.proto
+
+package collectionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateCollectionV1Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (x *CreateCollectionV1Request) Reset() {
+	*x = CreateCollectionV1Request{}
+	mi := &file_create_collection_v1_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCollectionV1Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCollectionV1Request) ProtoMessage() {}
+
+func (x *CreateCollectionV1Request) ProtoReflect() protoreflect.Message {
+	mi := &file_create_collection_v1_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCollectionV1Request.ProtoReflect.Descriptor instead.
+func (*CreateCollectionV1Request) Descriptor() ([]byte, []int) {
+	return file_create_collection_v1_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateCollectionV1Request) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+var File_create_collection_v1_request_proto protoreflect.FileDescriptor
+
+var file_create_collection_v1_request_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x2e, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x2f,
+	0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x56, 0x31, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x42,
+	0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x35,
+	0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x3b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x70, 0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70,
+	0xe8, 0x07,
+}
+
+var (
+	file_create_collection_v1_request_proto_rawDescOnce sync.Once
+	file_create_collection_v1_request_proto_rawDescData = file_create_collection_v1_request_proto_rawDesc
+)
+
+func file_create_collection_v1_request_proto_rawDescGZIP() []byte {
+	file_create_collection_v1_request_proto_rawDescOnce.Do(func() {
+		file_create_collection_v1_request_proto_rawDescData = protoimpl.X.CompressGZIP(file_create_collection_v1_request_proto_rawDescData)
+	})
+	return file_create_collection_v1_request_proto_rawDescData
+}
+
+var file_create_collection_v1_request_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_create_collection_v1_request_proto_goTypes = []any{
+	(*CreateCollectionV1Request)(nil), // 0: griot.content.collection.CreateCollectionV1Request
+}
+var file_create_collection_v1_request_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_create_collection_v1_request_proto_init() }
+func file_create_collection_v1_request_proto_init() {
+	if File_create_collection_v1_request_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_create_collection_v1_request_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_create_collection_v1_request_proto_goTypes,
+		DependencyIndexes: file_create_collection_v1_request_proto_depIdxs,
+		MessageInfos:      file_create_collection_v1_request_proto_msgTypes,
+	}.Build()
+	File_create_collection_v1_request_proto = out.File
+	file_create_collection_v1_request_proto_rawDesc = nil
+	file_create_collection_v1_request_proto_goTypes = nil
+	file_create_collection_v1_request_proto_depIdxs = nil
+}
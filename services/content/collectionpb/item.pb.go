@@ -0,0 +1,158 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: item.proto
+
+package collectionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type *ItemType `protobuf:"varint,1,opt,name=type,enum=griot.content.collection.ItemType" json:"type,omitempty"`
+	// id is either a Content ID or a Collection ID, depending on the type.
+	Id *string `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	// rank orders the items of a collection. Ranks are compared as strings and
+	// one can always be found between any two others, so an item can be inserted
+	// anywhere without changing the rank of any other item.
+	Rank *string `protobuf:"bytes,3,opt,name=rank" json:"rank,omitempty"`
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_item_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Item) GetType() ItemType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ItemType_CONTENT
+}
+
+func (x *Item) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *Item) GetRank() string {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return ""
+}
+
+var File_item_proto protoreflect.FileDescriptor
+
+var file_item_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x67, 0x72,
+	0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0f, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x62, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12,
+	0x36, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e,
+	0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x42, 0x44, 0x5a, 0x42, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x35, 0x6c, 0x61, 0x62, 0x73,
+	0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x70, 0x62, 0x3b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x70,
+	0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
+}
+
+var (
+	file_item_proto_rawDescOnce sync.Once
+	file_item_proto_rawDescData = file_item_proto_rawDesc
+)
+
+func file_item_proto_rawDescGZIP() []byte {
+	file_item_proto_rawDescOnce.Do(func() {
+		file_item_proto_rawDescData = protoimpl.X.CompressGZIP(file_item_proto_rawDescData)
+	})
+	return file_item_proto_rawDescData
+}
+
+var file_item_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_item_proto_goTypes = []any{
+	(*Item)(nil),  // 0: griot.content.collection.Item
+	(ItemType)(0), // 1: griot.content.collection.ItemType
+}
+var file_item_proto_depIdxs = []int32{
+	1, // 0: griot.content.collection.Item.type:type_name -> griot.content.collection.ItemType
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_item_proto_init() }
+func file_item_proto_init() {
+	if File_item_proto != nil {
+		return
+	}
+	file_item_type_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_item_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_item_proto_goTypes,
+		DependencyIndexes: file_item_proto_depIdxs,
+		MessageInfos:      file_item_proto_msgTypes,
+	}.Build()
+	File_item_proto = out.File
+	file_item_proto_rawDesc = nil
+	file_item_proto_goTypes = nil
+	file_item_proto_depIdxs = nil
+}
@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: item_type.proto
+
+package collectionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ItemType is what an Item of a Collection refers to.
+type ItemType int32
+
+const (
+	ItemType_CONTENT    ItemType = 0
+	ItemType_COLLECTION ItemType = 1
+)
+
+// Enum value maps for ItemType.
+var (
+	ItemType_name = map[int32]string{
+		0: "CONTENT",
+		1: "COLLECTION",
+	}
+	ItemType_value = map[string]int32{
+		"CONTENT":    0,
+		"COLLECTION": 1,
+	}
+)
+
+func (x ItemType) Enum() *ItemType {
+	p := new(ItemType)
+	*p = x
+	return p
+}
+
+func (x ItemType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ItemType) Descriptor() protoreflect.EnumDescriptor {
+	return file_item_type_proto_enumTypes[0].Descriptor()
+}
+
+func (ItemType) Type() protoreflect.EnumType {
+	return &file_item_type_proto_enumTypes[0]
+}
+
+func (x ItemType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ItemType.Descriptor instead.
+func (ItemType) EnumDescriptor() ([]byte, []int) {
+	return file_item_type_proto_rawDescGZIP(), []int{0}
+}
+
+var File_item_type_proto protoreflect.FileDescriptor
+
+var file_item_type_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x18, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x2e, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2a, 0x27, 0x0a, 0x08, 0x49,
+	0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4e, 0x54, 0x45,
+	0x4e, 0x54, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x49,
+	0x4f, 0x4e, 0x10, 0x01, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x7a, 0x35, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x2f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x3b, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
+}
+
+var (
+	file_item_type_proto_rawDescOnce sync.Once
+	file_item_type_proto_rawDescData = file_item_type_proto_rawDesc
+)
+
+func file_item_type_proto_rawDescGZIP() []byte {
+	file_item_type_proto_rawDescOnce.Do(func() {
+		file_item_type_proto_rawDescData = protoimpl.X.CompressGZIP(file_item_type_proto_rawDescData)
+	})
+	return file_item_type_proto_rawDescData
+}
+
+var file_item_type_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_item_type_proto_goTypes = []any{
+	(ItemType)(0), // 0: griot.content.collection.ItemType
+}
+var file_item_type_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_item_type_proto_init() }
+func file_item_type_proto_init() {
+	if File_item_type_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_item_type_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_item_type_proto_goTypes,
+		DependencyIndexes: file_item_type_proto_depIdxs,
+		EnumInfos:         file_item_type_proto_enumTypes,
+	}.Build()
+	File_item_type_proto = out.File
+	file_item_type_proto_rawDesc = nil
+	file_item_type_proto_goTypes = nil
+	file_item_type_proto_depIdxs = nil
+}
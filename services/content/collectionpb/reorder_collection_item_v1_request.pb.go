@@ -0,0 +1,136 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: reorder_collection_item_v1_request.proto
+
+package collectionpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReorderCollectionItemV1Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// position is where the item is moved to, starting from 1. The item
+	// is moved to the end if the position is 0 or past the end of the collection.
+	Position *uint32 `protobuf:"varint,1,opt,name=position" json:"position,omitempty"`
+}
+
+func (x *ReorderCollectionItemV1Request) Reset() {
+	*x = ReorderCollectionItemV1Request{}
+	mi := &file_reorder_collection_item_v1_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderCollectionItemV1Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderCollectionItemV1Request) ProtoMessage() {}
+
+func (x *ReorderCollectionItemV1Request) ProtoReflect() protoreflect.Message {
+	mi := &file_reorder_collection_item_v1_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderCollectionItemV1Request.ProtoReflect.Descriptor instead.
+func (*ReorderCollectionItemV1Request) Descriptor() ([]byte, []int) {
+	return file_reorder_collection_item_v1_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReorderCollectionItemV1Request) GetPosition() uint32 {
+	if x != nil && x.Position != nil {
+		return *x.Position
+	}
+	return 0
+}
+
+var File_reorder_collection_item_v1_request_proto protoreflect.FileDescriptor
+
+var file_reorder_collection_item_v1_request_proto_rawDesc = []byte{
+	0x0a, 0x28, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x67, 0x72, 0x69, 0x6f,
+	0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3c, 0x0a, 0x1e, 0x52, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x43,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x56, 0x31, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x7a, 0x35, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x3b, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x70, 0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x70, 0xe8, 0x07,
+}
+
+var (
+	file_reorder_collection_item_v1_request_proto_rawDescOnce sync.Once
+	file_reorder_collection_item_v1_request_proto_rawDescData = file_reorder_collection_item_v1_request_proto_rawDesc
+)
+
+func file_reorder_collection_item_v1_request_proto_rawDescGZIP() []byte {
+	file_reorder_collection_item_v1_request_proto_rawDescOnce.Do(func() {
+		file_reorder_collection_item_v1_request_proto_rawDescData = protoimpl.X.CompressGZIP(file_reorder_collection_item_v1_request_proto_rawDescData)
+	})
+	return file_reorder_collection_item_v1_request_proto_rawDescData
+}
+
+var file_reorder_collection_item_v1_request_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_reorder_collection_item_v1_request_proto_goTypes = []any{
+	(*ReorderCollectionItemV1Request)(nil), // 0: griot.content.collection.ReorderCollectionItemV1Request
+}
+var file_reorder_collection_item_v1_request_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_reorder_collection_item_v1_request_proto_init() }
+func file_reorder_collection_item_v1_request_proto_init() {
+	if File_reorder_collection_item_v1_request_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_reorder_collection_item_v1_request_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_reorder_collection_item_v1_request_proto_goTypes,
+		DependencyIndexes: file_reorder_collection_item_v1_request_proto_depIdxs,
+		MessageInfos:      file_reorder_collection_item_v1_request_proto_msgTypes,
+	}.Build()
+	File_reorder_collection_item_v1_request_proto = out.File
+	file_reorder_collection_item_v1_request_proto_rawDesc = nil
+	file_reorder_collection_item_v1_request_proto_goTypes = nil
+	file_reorder_collection_item_v1_request_proto_depIdxs = nil
+}
@@ -0,0 +1,311 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrMissingCollectionName = errors.New("collection name is required")
+	ErrMissingItemId         = errors.New("collection item id is required")
+	ErrUnknownItemType       = errors.New("unknown collection item type")
+	ErrCollectionCycle       = errors.New("a collection can't contain itself, even through other collections")
+)
+
+// collectionsV1Handler implements the APIs for curating content into Collections.
+// Every Collection belongs to the user who created it and can only contain that
+// user's content and Collections.
+type collectionsV1Handler struct {
+	log            *slog.Logger
+	collections    collection.Store
+	index          index.Index
+	owners         *keyedLocks
+	protoMarshal   func(proto.Message) ([]byte, error)
+	protoUnmarshal func([]byte, proto.Message) error
+}
+
+func (h *collectionsV1Handler) create(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.create")
+	defer span.End()
+
+	var req collectionpb.CreateCollectionV1Request
+	err := h.readRequest(r, &req)
+	if err == nil && req.GetName() == "" {
+		err = ErrMissingCollectionName
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read create collection request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	c, err := collection.Create(spanCtx, h.collections, callerFromContext(spanCtx), req.GetName())
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to create collection", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to create collection")
+		return
+	}
+
+	w.Header().Set("Location", "/v1/collections/"+url.PathEscape(c.GetId()))
+	writeProto(h.log, w, http.StatusCreated, h.protoMarshal, c)
+}
+
+func (h *collectionsV1Handler) list(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.list")
+	defer span.End()
+
+	resp := &collectionpb.ListCollectionsV1Response{}
+	for c, err := range h.collections.List(spanCtx, callerFromContext(spanCtx)) {
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list collections", slog.String("error", err.Error()))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to list collections")
+			return
+		}
+		resp.Collections = append(resp.Collections, c)
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, resp)
+}
+
+func (h *collectionsV1Handler) get(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.get")
+	defer span.End()
+
+	c, err := h.collections.Get(spanCtx, callerFromContext(spanCtx), r.PathValue("id"))
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to get collection", err)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, c)
+}
+
+func (h *collectionsV1Handler) rename(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.rename")
+	defer span.End()
+
+	var req collectionpb.RenameCollectionV1Request
+	err := h.readRequest(r, &req)
+	if err == nil && req.GetName() == "" {
+		err = ErrMissingCollectionName
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read rename collection request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	c, err := h.collections.Update(spanCtx, callerFromContext(spanCtx), r.PathValue("id"), func(c *collectionpb.Collection) error {
+		c.Name = req.Name
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to rename collection", err)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, c)
+}
+
+func (h *collectionsV1Handler) addItem(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.addItem")
+	defer span.End()
+
+	var req collectionpb.AddCollectionItemV1Request
+	err := h.readRequest(r, &req)
+	if err == nil {
+		err = validateAddItemRequest(&req)
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read add collection item request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	owner := callerFromContext(spanCtx)
+	id := r.PathValue("id")
+
+	// adding items is serialized per owner since validating the item reads other
+	// collections, e.g. concurrently adding A to B and B to A must not both succeed
+	unlock := h.owners.exclusive(owner)
+	defer unlock()
+
+	err = h.validateItem(spanCtx, owner, id, req.GetType(), req.GetId())
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to validate collection item", err)
+		return
+	}
+
+	item := &collectionpb.Item{
+		Type: req.GetType().Enum(),
+		Id:   req.Id,
+	}
+	c, err := h.collections.Update(spanCtx, owner, id, func(c *collectionpb.Collection) error {
+		return collection.AddItem(c, item, int(req.GetPosition()))
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to add collection item", err)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, c)
+}
+
+func validateAddItemRequest(req *collectionpb.AddCollectionItemV1Request) error {
+	if req.GetId() == "" {
+		return ErrMissingItemId
+	}
+	if _, known := collectionpb.ItemType_name[int32(req.GetType())]; !known {
+		return ErrUnknownItemType
+	}
+	return nil
+}
+
+// validateItem checks the item refers to content or a collection of the owner
+// and, if it's a collection, that adding it won't nest a collection in itself.
+func (h *collectionsV1Handler) validateItem(ctx context.Context, owner, id string, typ collectionpb.ItemType, itemId string) error {
+	if typ == collectionpb.ItemType_CONTENT {
+		_, err := h.index.Get(ctx, owner, &contentpb.ContentId{Value: &itemId})
+		return err
+	}
+
+	// every collection nested in the item is visited, so it's
+	// found if the collection would end up containing itself
+	pending := []string{itemId}
+	seen := make(map[string]bool)
+	for len(pending) > 0 {
+		nestedId := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if nestedId == id {
+			return ErrCollectionCycle
+		}
+		if seen[nestedId] {
+			continue
+		}
+		seen[nestedId] = true
+
+		nested, err := h.collections.Get(ctx, owner, nestedId)
+		if errors.Is(err, collection.ErrNotFound) && nestedId != itemId {
+			// nested collections aren't required to still exist
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, item := range nested.GetItems() {
+			if item.GetType() == collectionpb.ItemType_COLLECTION {
+				pending = append(pending, item.GetId())
+			}
+		}
+	}
+	return nil
+}
+
+func (h *collectionsV1Handler) removeItem(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.removeItem")
+	defer span.End()
+
+	itemId := r.PathValue("item_id")
+	_, err := h.collections.Update(spanCtx, callerFromContext(spanCtx), r.PathValue("id"), func(c *collectionpb.Collection) error {
+		return collection.RemoveItem(c, itemId)
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to remove collection item", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *collectionsV1Handler) reorderItem(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "collectionsV1Handler.reorderItem")
+	defer span.End()
+
+	var req collectionpb.ReorderCollectionItemV1Request
+	err := h.readRequest(r, &req)
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read reorder collection item request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	itemId := r.PathValue("item_id")
+	c, err := h.collections.Update(spanCtx, callerFromContext(spanCtx), r.PathValue("id"), func(c *collectionpb.Collection) error {
+		return collection.MoveItem(c, itemId, int(req.GetPosition()))
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to reorder collection item", err)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, c)
+}
+
+func (h *collectionsV1Handler) readRequest(r *http.Request, m proto.Message) error {
+	if r.Header.Get("Content-Type") != rest.ProtobufContentType {
+		return ErrUnsupportedContentType
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxMetadataSize+1))
+	if err != nil {
+		return err
+	}
+	if len(b) > maxMetadataSize {
+		return ErrRequestTooLarge
+	}
+	return h.protoUnmarshal(b, m)
+}
+
+// writeError responds with the humuspb.Status for an error returned while
+// looking up or updating a collection, or an item being added to one.
+func (h *collectionsV1Handler) writeError(ctx context.Context, w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, collection.ErrNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, err.Error())
+	case errors.Is(err, index.ErrNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "content not found")
+	case errors.Is(err, collection.ErrItemNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, err.Error())
+	case errors.Is(err, collection.ErrItemExists):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_ALREADY_EXISTS, err.Error())
+	case errors.Is(err, ErrCollectionCycle):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+	default:
+		h.log.ErrorContext(ctx, msg, slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, msg)
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/services/content/collection"
+	collectionmemory "github.com/z5labs/griot/services/content/collection/memory"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/index/indextest"
+	"github.com/z5labs/griot/services/content/index/memory"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func itemIds(c *collectionpb.Collection) []string {
+	var ids []string
+	for _, item := range c.GetItems() {
+		ids = append(ids, item.GetId())
+	}
+	return ids
+}
+
+func assertStatusCode(t *testing.T, code humuspb.Code, err error) bool {
+	t.Helper()
+
+	var status *humuspb.Status
+	if !assert.ErrorAs(t, err, &status) {
+		return false
+	}
+	return assert.Equal(t, code, status.GetCode())
+}
+
+type slowUpdateStore struct {
+	collection.Store
+
+	delay time.Duration
+}
+
+func (s slowUpdateStore) Update(ctx context.Context, owner, id string, update func(*collectionpb.Collection) error) (*collectionpb.Collection, error) {
+	time.Sleep(s.delay)
+	return s.Store.Update(ctx, owner, id, update)
+}
+
+func TestCollectionsV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if collections are not enabled", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.ListCollections(context.Background())
+			if !assertStatusCode(t, humuspb.Code_UNIMPLEMENTED, err) {
+				return
+			}
+		})
+
+		t.Run("if the collection name is missing", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{})
+			if !assertStatusCode(t, humuspb.Code_INVALID_ARGUMENT, err) {
+				return
+			}
+		})
+
+		t.Run("if the collection does not exist", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.GetCollection(context.Background(), &GetCollectionRequest{
+				Id: "0123456789abcdef",
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+
+		t.Run("if the collection belongs to another user", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			bob := NewClient(http.DefaultClient, srv.URL, Credentials(mintToken(t, tokens, "bob", tokenpb.Scope_UPLOAD)))
+			alice := NewClient(http.DefaultClient, srv.URL, Credentials(mintToken(t, tokens, "alice", tokenpb.Scope_UPLOAD)))
+
+			col, err := bob.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Naruto",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = alice.GetCollection(context.Background(), &GetCollectionRequest{
+				Id: col.GetId(),
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+
+			listResp, err := alice.ListCollections(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, listResp.Collections) {
+				return
+			}
+		})
+
+		t.Run("if the content item is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Season 1",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+				CollectionId: col.GetId(),
+				Type:         collectionpb.ItemType_CONTENT,
+				ItemId:       indextest.NewRecord("hello", "text", "plain").GetContentId().GetValue(),
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+
+		t.Run("if the item type is unknown", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Season 1",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+				CollectionId: col.GetId(),
+				Type:         collectionpb.ItemType(42),
+				ItemId:       "0123456789abcdef",
+			})
+			if !assertStatusCode(t, humuspb.Code_INVALID_ARGUMENT, err) {
+				return
+			}
+		})
+
+		t.Run("if the collection already contains the item", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := memory.New()
+			err := idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx, Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Season 1",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			req := &AddCollectionItemRequest{
+				CollectionId: col.GetId(),
+				Type:         collectionpb.ItemType_CONTENT,
+				ItemId:       record.GetContentId().GetValue(),
+			}
+			_, err = c.AddCollectionItem(context.Background(), req)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AddCollectionItem(context.Background(), req)
+			if !assertStatusCode(t, humuspb.Code_ALREADY_EXISTS, err) {
+				return
+			}
+		})
+
+		t.Run("if the collection would contain itself", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			var cols []*collectionpb.Collection
+			for _, name := range []string{"Naruto", "Season 1", "Arc 1"} {
+				col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+					Name: name,
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+				cols = append(cols, col)
+			}
+			for i := range 2 {
+				_, err := c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+					CollectionId: cols[i].GetId(),
+					Type:         collectionpb.ItemType_COLLECTION,
+					ItemId:       cols[i+1].GetId(),
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			for _, col := range cols {
+				_, err := c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+					CollectionId: cols[2].GetId(),
+					Type:         collectionpb.ItemType_COLLECTION,
+					ItemId:       col.GetId(),
+				})
+				if !assertStatusCode(t, humuspb.Code_INVALID_ARGUMENT, err) {
+					return
+				}
+			}
+		})
+
+		t.Run("if collections are concurrently added to each other", func(t *testing.T) {
+			// delaying updates ensures both adds would be validated
+			// before either is stored if they weren't serialized
+			collections := slowUpdateStore{
+				Store: collectionmemory.New(),
+				delay: 50 * time.Millisecond,
+			}
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collections)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			var cols []*collectionpb.Collection
+			for _, name := range []string{"Naruto", "Season 1"} {
+				col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+					Name: name,
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+				cols = append(cols, col)
+			}
+
+			errs := make([]error, len(cols))
+			var wg sync.WaitGroup
+			for i := range cols {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					_, errs[i] = c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+						CollectionId: cols[i].GetId(),
+						Type:         collectionpb.ItemType_COLLECTION,
+						ItemId:       cols[1-i].GetId(),
+					})
+				}()
+			}
+			wg.Wait()
+
+			added := 0
+			for _, err := range errs {
+				if err == nil {
+					added++
+					continue
+				}
+				if !assertStatusCode(t, humuspb.Code_INVALID_ARGUMENT, err) {
+					return
+				}
+			}
+			if !assert.Equal(t, 1, added) {
+				return
+			}
+		})
+
+		t.Run("if the removed item is not in the collection", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Season 1",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = c.RemoveCollectionItem(context.Background(), &RemoveCollectionItemRequest{
+				CollectionId: col.GetId(),
+				ItemId:       "0123456789abcdef",
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will curate the collection", func(t *testing.T) {
+		idx := memory.New()
+		var ids []string
+		for _, name := range []string{"Naruto S01E01", "Naruto S01E02", "Naruto S01E03"} {
+			record := indextest.NewRecord(name, "video", "av1")
+			err := idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+			ids = append(ids, record.GetContentId().GetValue())
+		}
+
+		srv := httptest.NewServer(NewServer(nil, idx, Collections(collectionmemory.New())))
+		defer srv.Close()
+
+		c := NewClient(http.DefaultClient, srv.URL)
+
+		col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+			Name: "Season 1",
+		})
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		t.Run("if items are added", func(t *testing.T) {
+			for _, item := range []struct {
+				id       string
+				position uint32
+			}{
+				{id: ids[0]},
+				{id: ids[2]},
+				{id: ids[1], position: 2},
+			} {
+				_, err := c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+					CollectionId: col.GetId(),
+					Type:         collectionpb.ItemType_CONTENT,
+					ItemId:       item.id,
+					Position:     item.position,
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			got, err := c.GetCollection(context.Background(), &GetCollectionRequest{
+				Id: col.GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, ids, itemIds(got)) {
+				return
+			}
+		})
+
+		t.Run("if an item is reordered", func(t *testing.T) {
+			got, err := c.ReorderCollectionItem(context.Background(), &ReorderCollectionItemRequest{
+				CollectionId: col.GetId(),
+				ItemId:       ids[2],
+				Position:     1,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{ids[2], ids[0], ids[1]}, itemIds(got)) {
+				return
+			}
+		})
+
+		t.Run("if an item is removed", func(t *testing.T) {
+			err := c.RemoveCollectionItem(context.Background(), &RemoveCollectionItemRequest{
+				CollectionId: col.GetId(),
+				ItemId:       ids[0],
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			got, err := c.GetCollection(context.Background(), &GetCollectionRequest{
+				Id: col.GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{ids[2], ids[1]}, itemIds(got)) {
+				return
+			}
+		})
+
+		t.Run("if it is renamed", func(t *testing.T) {
+			got, err := c.RenameCollection(context.Background(), &RenameCollectionRequest{
+				Id:   col.GetId(),
+				Name: "Naruto Season 1",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "Naruto Season 1", got.GetName()) {
+				return
+			}
+			if !assert.Equal(t, []string{ids[2], ids[1]}, itemIds(got)) {
+				return
+			}
+		})
+
+		t.Run("if another collection is added", func(t *testing.T) {
+			parent, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Naruto",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			got, err := c.AddCollectionItem(context.Background(), &AddCollectionItemRequest{
+				CollectionId: parent.GetId(),
+				Type:         collectionpb.ItemType_COLLECTION,
+				ItemId:       col.GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{col.GetId()}, itemIds(got)) {
+				return
+			}
+
+			listResp, err := c.ListCollections(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, listResp.Collections, 2) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+)
+
+// NewContentId computes the Content ID for content with the given checksum.
+//
+// The Content ID is the base64 encoded SHA-256 hash of the string,
+// "{hash_func}/{base64_hash}", where base64_hash is the base64 encoding
+// of the checksum hash. Since it only depends on the checksum, a Content ID
+// can be predicted for content without uploading it.
+func NewContentId(checksum *contentpb.Checksum) *contentpb.ContentId {
+	b64Hash := base64.StdEncoding.EncodeToString(checksum.GetHash())
+	sum := sha256.Sum256([]byte(checksum.GetHashFunc().String() + "/" + b64Hash))
+	id := base64.StdEncoding.EncodeToString(sum[:])
+	return &contentpb.ContentId{
+		Value: &id,
+	}
+}
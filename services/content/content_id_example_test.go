@@ -0,0 +1,34 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+)
+
+func ExampleNewContentId() {
+	hash := sha256.Sum256([]byte("hello world"))
+
+	id := NewContentId(&contentpb.Checksum{
+		HashFunc: contentpb.HashFunc_SHA256.Enum(),
+		Hash:     hash[:],
+	})
+
+	fmt.Println(id.GetValue())
+	//Output: fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=
+}
@@ -23,16 +23,28 @@ const (
 type HashFunc int32
 
 const (
-	HashFunc_SHA256 HashFunc = 0
+	HashFunc_SHA256      HashFunc = 0
+	HashFunc_SHA512      HashFunc = 1
+	HashFunc_SHA3_256    HashFunc = 2
+	HashFunc_BLAKE2B_256 HashFunc = 3
+	HashFunc_BLAKE3      HashFunc = 4
 )
 
 // Enum value maps for HashFunc.
 var (
 	HashFunc_name = map[int32]string{
 		0: "SHA256",
+		1: "SHA512",
+		2: "SHA3_256",
+		3: "BLAKE2B_256",
+		4: "BLAKE3",
 	}
 	HashFunc_value = map[string]int32{
-		"SHA256": 0,
+		"SHA256":      0,
+		"SHA512":      1,
+		"SHA3_256":    2,
+		"BLAKE2B_256": 3,
+		"BLAKE3":      4,
 	}
 )
 
@@ -68,13 +80,16 @@ var File_hash_func_proto protoreflect.FileDescriptor
 var file_hash_func_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x12, 0x0d, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
-	0x2a, 0x16, 0x0a, 0x08, 0x48, 0x61, 0x73, 0x68, 0x46, 0x75, 0x6e, 0x63, 0x12, 0x0a, 0x0a, 0x06,
-	0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x00, 0x42, 0x3e, 0x5a, 0x3c, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x35, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72,
-	0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x3b, 0x63,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x70, 0xe8, 0x07,
+	0x2a, 0x4d, 0x0a, 0x08, 0x48, 0x61, 0x73, 0x68, 0x46, 0x75, 0x6e, 0x63, 0x12, 0x0a, 0x0a, 0x06,
+	0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x35,
+	0x31, 0x32, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x41, 0x33, 0x5f, 0x32, 0x35, 0x36,
+	0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x4c, 0x41, 0x4b, 0x45, 0x32, 0x42, 0x5f, 0x32, 0x35,
+	0x36, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x42, 0x4c, 0x41, 0x4b, 0x45, 0x33, 0x10, 0x04, 0x42,
+	0x3e, 0x5a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x35,
+	0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x70, 0x62, 0x3b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x62,
+	0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
 }
 
 var (
@@ -0,0 +1,114 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contentpb
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+var (
+	ErrMissingSubtype        = errors.New("missing subtype")
+	ErrInvalidRestrictedName = errors.New("type and subtype must be RFC 6838 restricted names")
+)
+
+// InvalidMediaTypeError is returned by ParseMediaType when the value
+// is not a valid media type.
+type InvalidMediaTypeError struct {
+	Value string
+	Cause error
+}
+
+func (e InvalidMediaTypeError) Error() string {
+	return fmt.Sprintf("invalid media type: %q: %s", e.Value, e.Cause)
+}
+
+func (e InvalidMediaTypeError) Unwrap() error {
+	return e.Cause
+}
+
+// ParseMediaType parses a RFC 6838 media type with optional parameters,
+// e.g. "application/ld+json; charset=utf-8". The type, subtype and suffix
+// are lowercased since they're case-insensitive.
+func ParseMediaType(v string) (*MediaType, error) {
+	full, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return nil, InvalidMediaTypeError{Value: v, Cause: err}
+	}
+
+	typ, subtype, found := strings.Cut(full, "/")
+	if !found || subtype == "" {
+		return nil, InvalidMediaTypeError{Value: v, Cause: ErrMissingSubtype}
+	}
+	if !isRestrictedName(typ) || !isRestrictedName(subtype) {
+		return nil, InvalidMediaTypeError{Value: v, Cause: ErrInvalidRestrictedName}
+	}
+
+	mediaType := &MediaType{
+		Type: &typ,
+	}
+
+	// RFC 6839 structured syntax suffixes follow the last '+' of the subtype.
+	if i := strings.LastIndexByte(subtype, '+'); i > 0 && i < len(subtype)-1 {
+		suffix := subtype[i+1:]
+		subtype = subtype[:i]
+		mediaType.Suffix = &suffix
+	}
+	mediaType.Subtype = &subtype
+
+	if len(params) > 0 {
+		mediaType.Parameters = params
+	}
+	return mediaType, nil
+}
+
+// FormatMediaType is the inverse of ParseMediaType. An empty string is
+// returned if the media type has no type.
+func FormatMediaType(mediaType *MediaType) string {
+	typ := mediaType.GetType()
+	if typ == "" {
+		return ""
+	}
+	if subtype := mediaType.GetSubtype(); subtype != "" {
+		typ += "/" + subtype
+	}
+	if suffix := mediaType.GetSuffix(); suffix != "" {
+		typ += "+" + suffix
+	}
+
+	formatted := mime.FormatMediaType(typ, mediaType.GetParameters())
+	if formatted == "" {
+		return typ
+	}
+	return formatted
+}
+
+// isRestrictedName reports whether s is a restricted-name as defined in RFC 6838 section 4.2.
+func isRestrictedName(s string) bool {
+	if len(s) == 0 || len(s) > 127 {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case i > 0 && strings.ContainsRune("!#$&-^_.+", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contentpb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMediaType(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the media type is malformed", func(t *testing.T) {
+			_, err := ParseMediaType("text/plain; charset=")
+
+			var merr InvalidMediaTypeError
+			if !assert.ErrorAs(t, err, &merr) {
+				return
+			}
+			if !assert.NotEmpty(t, merr.Error()) {
+				return
+			}
+		})
+
+		t.Run("if the subtype is missing", func(t *testing.T) {
+			_, err := ParseMediaType("text")
+			if !assert.ErrorIs(t, err, ErrMissingSubtype) {
+				return
+			}
+		})
+
+		t.Run("if the type is not a restricted name", func(t *testing.T) {
+			_, err := ParseMediaType("-text/plain")
+			if !assert.ErrorIs(t, err, ErrInvalidRestrictedName) {
+				return
+			}
+		})
+	})
+
+	t.Run("will split the type, subtype and suffix", func(t *testing.T) {
+		t.Run("if the subtype has a structured syntax suffix", func(t *testing.T) {
+			mediaType, err := ParseMediaType("Application/LD+JSON; charset=utf-8")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "application", mediaType.GetType()) {
+				return
+			}
+			if !assert.Equal(t, "ld", mediaType.GetSubtype()) {
+				return
+			}
+			if !assert.Equal(t, "json", mediaType.GetSuffix()) {
+				return
+			}
+			if !assert.Equal(t, map[string]string{"charset": "utf-8"}, mediaType.GetParameters()) {
+				return
+			}
+		})
+
+		t.Run("if the subtype has no suffix", func(t *testing.T) {
+			mediaType, err := ParseMediaType("video/av1")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "video", mediaType.GetType()) {
+				return
+			}
+			if !assert.Equal(t, "av1", mediaType.GetSubtype()) {
+				return
+			}
+			if !assert.Nil(t, mediaType.Suffix) {
+				return
+			}
+		})
+	})
+}
+
+func TestFormatMediaType(t *testing.T) {
+	t.Run("will return an empty string", func(t *testing.T) {
+		t.Run("if the media type has no type", func(t *testing.T) {
+			if !assert.Empty(t, FormatMediaType(&MediaType{})) {
+				return
+			}
+		})
+	})
+
+	t.Run("will round trip", func(t *testing.T) {
+		mediaTypes := []string{
+			"video/av1",
+			"application/ld+json",
+			"application/vnd.api+json; charset=utf-8",
+			"multipart/form-data; boundary=abc",
+		}
+
+		for _, v := range mediaTypes {
+			t.Run("if the media type is "+v, func(t *testing.T) {
+				mediaType, err := ParseMediaType(v)
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, v, FormatMediaType(mediaType)) {
+					return
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+// deleteContentV1Handler removes the caller's content from the Content Index and,
+// once no other user has it indexed, from Content Storage.
+//
+// The index record is deleted first so the index never points at content which
+// is missing from storage. If deleting from storage then fails, the record is
+// restored so the content isn't left in storage without being indexed. The content
+// is exclusively locked throughout so it can't be uploaded and indexed again by
+// another user after it's been checked for other users but before it's deleted.
+type deleteContentV1Handler struct {
+	log          *slog.Logger
+	store        storage.Storage
+	index        index.Index
+	locks        *keyedLocks
+	protoMarshal func(proto.Message) ([]byte, error)
+}
+
+func (h *deleteContentV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "deleteContentV1Handler.ServeHTTP")
+	defer span.End()
+
+	id := r.PathValue("id")
+	contentId := &contentpb.ContentId{
+		Value: &id,
+	}
+
+	owner := callerFromContext(spanCtx)
+	record, err := h.index.Get(spanCtx, owner, contentId)
+	if errors.Is(err, index.ErrNotFound) {
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "content not found")
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to get index record", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to delete content")
+		return
+	}
+
+	unlock := h.locks.exclusive(id)
+	defer unlock()
+
+	err = h.index.Delete(spanCtx, owner, contentId)
+	if errors.Is(err, index.ErrNotFound) {
+		// a concurrent delete got here first
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "content not found")
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to delete index record", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to delete content")
+		return
+	}
+
+	shared, err := h.indexedByAnyone(spanCtx, contentId)
+	if err != nil {
+		// keeping content nobody has indexed is safer than deleting content someone else has
+		span.RecordError(err)
+		h.log.ErrorContext(
+			spanCtx,
+			"failed to check if content is indexed by other users so it was kept in storage",
+			slog.String("content_id", id),
+			slog.String("error", err.Error()),
+		)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if shared {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = h.store.Delete(spanCtx, contentId)
+	if err == nil || errors.Is(err, storage.ErrNotFound) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	span.RecordError(err)
+	h.log.ErrorContext(spanCtx, "failed to delete content from storage", slog.String("error", err.Error()))
+
+	restoreErr := h.index.Put(spanCtx, record)
+	if restoreErr != nil {
+		span.RecordError(restoreErr)
+		h.log.ErrorContext(
+			spanCtx,
+			"failed to restore index record after failing to delete content from storage",
+			slog.String("content_id", id),
+			slog.String("error", restoreErr.Error()),
+		)
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "content was unindexed but failed to be deleted from storage")
+		return
+	}
+	writeStatus(h.log, w, h.protoMarshal, humuspb.Code_UNAVAILABLE, "failed to delete content from storage, no changes were made")
+}
+
+func (h *deleteContentV1Handler) indexedByAnyone(ctx context.Context, id *contentpb.ContentId) (bool, error) {
+	for _, err := range h.index.QueryByContentId(ctx, id) {
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
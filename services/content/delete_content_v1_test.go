@@ -0,0 +1,397 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/index/indextest"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/indexpb"
+	"github.com/z5labs/griot/services/content/storage"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestDeleteContentV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			err := c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to delete the index record", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := indexStub{
+				get: func(ctx context.Context, owner string, ci *contentpb.ContentId) (*indexpb.Record, error) {
+					return record, nil
+				},
+				del: func(ctx context.Context, owner string, ci *contentpb.ContentId) error {
+					return errors.New("failed to delete")
+				},
+			}
+
+			deleted := false
+			store := storageStub{
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			err := c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: record.GetContentId().GetValue(),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+			if !assert.False(t, deleted) {
+				return
+			}
+		})
+
+		t.Run("if it fails to delete the content from storage", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := memory.New()
+			err := idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			store := storageStub{
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					return errors.New("failed to delete")
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			err = c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: record.GetContentId().GetValue(),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNAVAILABLE, status.GetCode()) {
+				return
+			}
+
+			_, err = idx.Get(context.Background(), "", record.GetContentId())
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+
+		t.Run("if it fails to restore the index record", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := indexStub{
+				get: func(ctx context.Context, owner string, ci *contentpb.ContentId) (*indexpb.Record, error) {
+					return record, nil
+				},
+				del: func(ctx context.Context, owner string, ci *contentpb.ContentId) error {
+					return nil
+				},
+				queryByContentId: func(ctx context.Context, ci *contentpb.ContentId) iter.Seq2[*indexpb.Record, error] {
+					return func(yield func(*indexpb.Record, error) bool) {}
+				},
+				put: func(ctx context.Context, r *indexpb.Record) error {
+					return errors.New("failed to put")
+				},
+			}
+
+			store := storageStub{
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					return errors.New("failed to delete")
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			err := c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: record.GetContentId().GetValue(),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will delete the content", func(t *testing.T) {
+		t.Run("if it is both indexed and stored", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := memory.New()
+			err := idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			var deletedId string
+			store := storageStub{
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					deletedId = ci.GetValue()
+					return nil
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			err = c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: record.GetContentId().GetValue(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, record.GetContentId().GetValue(), deletedId) {
+				return
+			}
+
+			_, err = idx.Get(context.Background(), "", record.GetContentId())
+			if !assert.ErrorIs(t, err, index.ErrNotFound) {
+				return
+			}
+		})
+
+		t.Run("if it is indexed but already missing from storage", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := memory.New()
+			err := idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			store := storageStub{
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					return storage.ErrNotFound
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			err = c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: record.GetContentId().GetValue(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = idx.Get(context.Background(), "", record.GetContentId())
+			if !assert.ErrorIs(t, err, index.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will keep the content in storage", func(t *testing.T) {
+		t.Run("if another user has it indexed", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			idx := memory.New()
+			for _, owner := range []string{"bob", "alice"} {
+				err := idx.Put(context.Background(), indextest.Owned(record, owner))
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			deleted := false
+			store := storageStub{
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_UPLOAD)
+
+			srv := httptest.NewServer(NewServer(store, idx, Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			err := c.DeleteContent(context.Background(), &DeleteContentRequest{
+				Id: record.GetContentId().GetValue(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.False(t, deleted) {
+				return
+			}
+
+			_, err = idx.Get(context.Background(), "bob", record.GetContentId())
+			if !assert.ErrorIs(t, err, index.ErrNotFound) {
+				return
+			}
+			_, err = idx.Get(context.Background(), "alice", record.GetContentId())
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will keep the content stored", func(t *testing.T) {
+		t.Run("if another user uploads it while it's being deleted", func(t *testing.T) {
+			record := indextest.NewRecord("hello", "text", "plain")
+			record.ContentId = NewContentId(record.GetCheckSums()[0])
+			idx := memory.New()
+			err := idx.Put(context.Background(), indextest.Owned(record, "bob"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			var mu sync.Mutex
+			stored := map[string][]byte{
+				record.GetContentId().GetValue(): []byte("hello"),
+			}
+			deleting := make(chan struct{})
+			finishDelete := make(chan struct{})
+			store := storageStub{
+				put: func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+					b, err := io.ReadAll(r)
+					if err != nil {
+						return err
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					stored[ci.GetValue()] = b
+					return nil
+				},
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					close(deleting)
+					<-finishDelete
+					mu.Lock()
+					defer mu.Unlock()
+					delete(stored, ci.GetValue())
+					return nil
+				},
+			}
+
+			tokens := tokenmemory.New()
+			bobSecret := mintToken(t, tokens, "bob", tokenpb.Scope_UPLOAD)
+			aliceSecret := mintToken(t, tokens, "alice", tokenpb.Scope_UPLOAD)
+
+			srv := httptest.NewServer(NewServer(store, idx, Authentication(tokens)))
+			defer srv.Close()
+
+			deleteErr := make(chan error, 1)
+			go func() {
+				c := NewClient(http.DefaultClient, srv.URL, Credentials(bobSecret))
+				deleteErr <- c.DeleteContent(context.Background(), &DeleteContentRequest{
+					Id: record.GetContentId().GetValue(),
+				})
+			}()
+			<-deleting
+
+			uploadErr := make(chan error, 1)
+			go func() {
+				c := NewClient(http.DefaultClient, srv.URL, Credentials(aliceSecret))
+				_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+					Metadata: &contentpb.Metadata{
+						Name:      ptr.Ref("hello"),
+						MediaType: record.GetContentType(),
+						Checksum:  record.GetCheckSums()[0],
+					},
+					Content: strings.NewReader("hello"),
+				})
+				uploadErr <- err
+			}()
+
+			select {
+			case err := <-uploadErr:
+				close(finishDelete)
+				<-deleteErr
+				assert.Fail(t, "expected upload to wait for the delete to finish", "upload error: %v", err)
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+			close(finishDelete)
+
+			if !assert.Nil(t, <-deleteErr) {
+				return
+			}
+			if !assert.Nil(t, <-uploadErr) {
+				return
+			}
+
+			_, err = idx.Get(context.Background(), "alice", record.GetContentId())
+			if !assert.Nil(t, err) {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !assert.Equal(t, []byte("hello"), stored[record.GetContentId().GetValue()]) {
+				return
+			}
+		})
+	})
+}
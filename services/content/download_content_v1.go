@@ -0,0 +1,253 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/indexpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+// ChecksumHeader is the response header containing an indexed checksum of the downloaded
+// content in the form, "{hash_func}={base64_hash}". It's repeated for every indexed checksum.
+const ChecksumHeader = "Griot-Checksum"
+
+type downloadContentV1Handler struct {
+	log          *slog.Logger
+	store        storage.Storage
+	index        index.Index
+	protoMarshal func(proto.Message) ([]byte, error)
+}
+
+func (h *downloadContentV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "downloadContentV1Handler.ServeHTTP")
+	defer span.End()
+
+	id := r.PathValue("id")
+	contentId := &contentpb.ContentId{
+		Value: &id,
+	}
+
+	record, err := h.index.Get(spanCtx, callerFromContext(spanCtx), contentId)
+	if errors.Is(err, index.ErrNotFound) {
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "content not found")
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to get index record", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to get content")
+		return
+	}
+
+	size := int64(sizeInBytes(record.GetContentSize()))
+
+	var ranges []byteRange
+	if v := r.Header.Get("Range"); v != "" {
+		ranges, err = parseRange(v, size)
+		if errors.Is(err, errNoOverlap) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_OUT_OF_RANGE, "range not satisfiable")
+			return
+		}
+
+		// RFC 9110 allows malformed Range headers, as well as ones requesting
+		// too many ranges, to be ignored in which case the entire content is
+		// returned instead.
+		if err != nil {
+			ranges = nil
+		}
+	}
+
+	header := w.Header()
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("ETag", strconv.Quote(id))
+	if name := record.GetContentName(); name != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	}
+	for _, checksum := range record.GetCheckSums() {
+		header.Add(ChecksumHeader, formatChecksum(checksum))
+	}
+
+	// the content is still opened for HEAD requests so they fail like
+	// GET requests would, but never read since net/http discards HEAD response bodies
+	head := r.Method == http.MethodHead
+	mediaType := formatMediaType(record.GetContentType())
+	switch len(ranges) {
+	case 0:
+		h.writeContent(spanCtx, w, head, contentId, mediaType, size)
+	case 1:
+		h.writeRange(spanCtx, w, head, contentId, mediaType, size, ranges[0])
+	default:
+		h.writeRanges(spanCtx, w, head, contentId, mediaType, size, ranges)
+	}
+}
+
+func (h *downloadContentV1Handler) writeContent(ctx context.Context, w http.ResponseWriter, head bool, id *contentpb.ContentId, mediaType string, size int64) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "downloadContentV1Handler.writeContent")
+	defer span.End()
+
+	rc, err := h.store.Get(spanCtx, id)
+	if err != nil {
+		span.RecordError(err)
+		h.writeStorageError(spanCtx, w, id, err)
+		return
+	}
+	defer rc.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", mediaType)
+	header.Set("Content-Length", strconv.FormatInt(size, 10))
+	w.WriteHeader(http.StatusOK)
+	if head {
+		return
+	}
+
+	_, err = io.Copy(w, rc)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write content", slog.String("error", err.Error()))
+	}
+}
+
+func (h *downloadContentV1Handler) writeRange(ctx context.Context, w http.ResponseWriter, head bool, id *contentpb.ContentId, mediaType string, size int64, br byteRange) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "downloadContentV1Handler.writeRange")
+	defer span.End()
+
+	rc, err := h.store.GetRange(spanCtx, id, br.start, br.length)
+	if err != nil {
+		span.RecordError(err)
+		h.writeStorageError(spanCtx, w, id, err)
+		return
+	}
+	defer rc.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", mediaType)
+	header.Set("Content-Length", strconv.FormatInt(br.length, 10))
+	header.Set("Content-Range", br.contentRange(size))
+	w.WriteHeader(http.StatusPartialContent)
+	if head {
+		return
+	}
+
+	_, err = io.Copy(w, rc)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write content range", slog.String("error", err.Error()))
+	}
+}
+
+// writeRanges writes a multipart/byteranges response. Since the response
+// status has already been sent by the time any range after the first is
+// read from storage, failures past that point can only be logged.
+func (h *downloadContentV1Handler) writeRanges(ctx context.Context, w http.ResponseWriter, head bool, id *contentpb.ContentId, mediaType string, size int64, ranges []byteRange) {
+	spanCtx, span := otel.Tracer("content").Start(ctx, "downloadContentV1Handler.writeRanges")
+	defer span.End()
+
+	rc, err := h.store.GetRange(spanCtx, id, ranges[0].start, ranges[0].length)
+	if err != nil {
+		span.RecordError(err)
+		h.writeStorageError(spanCtx, w, id, err)
+		return
+	}
+
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	w.WriteHeader(http.StatusPartialContent)
+	if head {
+		rc.Close()
+		return
+	}
+
+	for i, br := range ranges {
+		if i > 0 {
+			rc, err = h.store.GetRange(spanCtx, id, br.start, br.length)
+			if err != nil {
+				span.RecordError(err)
+				h.log.ErrorContext(spanCtx, "failed to get content range from storage", slog.String("error", err.Error()))
+				return
+			}
+		}
+
+		err = writeRangePart(mw, rc, mediaType, size, br)
+		rc.Close()
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to write content range", slog.String("error", err.Error()))
+			return
+		}
+	}
+
+	err = mw.Close()
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to write content ranges", slog.String("error", err.Error()))
+	}
+}
+
+func writeRangePart(mw *multipart.Writer, r io.Reader, mediaType string, size int64, br byteRange) error {
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":  {mediaType},
+		"Content-Range": {br.contentRange(size)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(pw, r)
+	return err
+}
+
+func (h *downloadContentV1Handler) writeStorageError(ctx context.Context, w http.ResponseWriter, id *contentpb.ContentId, err error) {
+	// content which is indexed but missing from storage is never
+	// the callers fault, so even storage.ErrNotFound is internal
+	h.log.ErrorContext(
+		ctx,
+		"failed to get content from storage",
+		slog.String("content_id", id.GetValue()),
+		slog.Bool("not_found", errors.Is(err, storage.ErrNotFound)),
+		slog.String("error", err.Error()),
+	)
+	writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to get content")
+}
+
+func sizeInBytes(size *indexpb.ContentSize) uint64 {
+	if size.GetUnit() == indexpb.UnitOfInformation_BIT {
+		return size.GetValue() / 8
+	}
+	return size.GetValue()
+}
+
+func formatChecksum(checksum *contentpb.Checksum) string {
+	return checksum.GetHashFunc().String() + "=" + base64.StdEncoding.EncodeToString(checksum.GetHash())
+}
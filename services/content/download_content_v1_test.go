@@ -0,0 +1,562 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/indexpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestDownloadContentV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the indexed content is missing from storage", func(t *testing.T) {
+			store := storageGetFunc(func(ctx context.Context, ci *contentpb.ContentId) (io.ReadCloser, error) {
+				return nil, storage.ErrNotFound
+			})
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId: &contentpb.ContentId{Value: ptr.Ref("fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=")},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(storageStub{get: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err = c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to get the content from storage", func(t *testing.T) {
+			store := storageGetFunc(func(ctx context.Context, ci *contentpb.ContentId) (io.ReadCloser, error) {
+				return nil, errors.New("failed to get")
+			})
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId: &contentpb.ContentId{Value: ptr.Ref("fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=")},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(storageStub{get: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err = c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will stream the content", func(t *testing.T) {
+		t.Run("if the content id contains a slash", func(t *testing.T) {
+			id := "ab/cd+ef/g="
+
+			var gotId string
+			store := storageGetFunc(func(ctx context.Context, ci *contentpb.ContentId) (io.ReadCloser, error) {
+				gotId = ci.GetValue()
+				return io.NopCloser(strings.NewReader("hello world")), nil
+			})
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId:   &contentpb.ContentId{Value: &id},
+				ContentSize: &indexpb.ContentSize{Value: ptr.Ref(uint64(len("hello world"))), Unit: indexpb.UnitOfInformation_BYTE.Enum()},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(storageStub{get: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id: id,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Content.Close()
+
+			if !assert.Equal(t, id, gotId) {
+				return
+			}
+		})
+
+		t.Run("with the indexed metadata as headers", func(t *testing.T) {
+			hash := sha256.Sum256([]byte("hello world"))
+			checksum := &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+				Hash:     hash[:],
+			}
+			id := NewContentId(checksum)
+
+			store := storageGetFunc(func(ctx context.Context, ci *contentpb.ContentId) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("hello world")), nil
+			})
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId:   id,
+				ContentName: ptr.Ref("hello.txt"),
+				ContentType: &contentpb.MediaType{
+					Type:    ptr.Ref("text"),
+					Subtype: ptr.Ref("plain"),
+				},
+				ContentSize: &indexpb.ContentSize{Value: ptr.Ref(uint64(len("hello world"))), Unit: indexpb.UnitOfInformation_BYTE.Enum()},
+				CheckSums:   []*contentpb.Checksum{checksum},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(storageStub{get: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id: id.GetValue(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Content.Close()
+
+			if !assert.Equal(t, "hello.txt", resp.Name) {
+				return
+			}
+			if !assert.Equal(t, "text", resp.MediaType.GetType()) {
+				return
+			}
+			if !assert.Equal(t, "plain", resp.MediaType.GetSubtype()) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), resp.Size) {
+				return
+			}
+			if !assert.Len(t, resp.Checksums, 1) {
+				return
+			}
+			if !assert.Equal(t, contentpb.HashFunc_SHA256, resp.Checksums[0].GetHashFunc()) {
+				return
+			}
+			if !assert.Equal(t, hash[:], resp.Checksums[0].GetHash()) {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Content)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+		})
+	})
+
+	t.Run("will not read the content", func(t *testing.T) {
+		t.Run("if only the headers are requested", func(t *testing.T) {
+			hash := sha256.Sum256([]byte("hello world"))
+			checksum := &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+				Hash:     hash[:],
+			}
+			id := NewContentId(checksum)
+
+			var read bool
+			store := storageGetFunc(func(ctx context.Context, ci *contentpb.ContentId) (io.ReadCloser, error) {
+				r := readFunc(func(b []byte) (int, error) {
+					read = true
+					return 0, io.EOF
+				})
+				return io.NopCloser(r), nil
+			})
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId:   id,
+				ContentSize: &indexpb.ContentSize{Value: ptr.Ref(uint64(len("hello world"))), Unit: indexpb.UnitOfInformation_BYTE.Enum()},
+				CheckSums:   []*contentpb.Checksum{checksum},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(storageStub{get: store}, idx))
+			defer srv.Close()
+
+			resp, err := http.Head(srv.URL + "/v1/content/" + id.GetValue())
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), resp.ContentLength) {
+				return
+			}
+			if !assert.False(t, read) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return HTTP 416", func(t *testing.T) {
+		t.Run("if none of the requested ranges overlap the content", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/content/"+rangeTestId, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			req.Header.Set("Range", "bytes=20-30")
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode) {
+				return
+			}
+			if !assert.Equal(t, "bytes */11", resp.Header.Get("Content-Range")) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_OUT_OF_RANGE, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the entire content", func(t *testing.T) {
+		t.Run("if the range header is malformed", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/content/"+rangeTestId, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			req.Header.Set("Range", "bytes=5-1")
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+		})
+
+		t.Run("if too many ranges are requested", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/content/"+rangeTestId, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			specs := make([]string, maxRanges+1)
+			for i := range specs {
+				specs[i] = "0-0"
+			}
+			req.Header.Set("Range", "bytes="+strings.Join(specs, ","))
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return partial content", func(t *testing.T) {
+		t.Run("if an offset and length are requested", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id:     rangeTestId,
+				Offset: 2,
+				Length: 3,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Content.Close()
+
+			if !assert.Equal(t, int64(2), resp.Offset) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), resp.Size) {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Content)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "llo", string(b)) {
+				return
+			}
+		})
+
+		t.Run("if only an offset is requested", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.DownloadContent(context.Background(), &DownloadContentRequest{
+				Id:     rangeTestId,
+				Offset: 6,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Content.Close()
+
+			if !assert.Equal(t, int64(6), resp.Offset) {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Content)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "world", string(b)) {
+				return
+			}
+		})
+
+		t.Run("as a single range if the requested ranges overlap", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/content/"+rangeTestId, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			req.Header.Set("Range", "bytes=3-6, 0-4, 7-7")
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusPartialContent, resp.StatusCode) {
+				return
+			}
+			if !assert.Equal(t, "bytes 0-7/11", resp.Header.Get("Content-Range")) {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello wo", string(b)) {
+				return
+			}
+		})
+
+		t.Run("as multipart/byteranges if multiple ranges are requested", func(t *testing.T) {
+			srv := newRangeTestServer(t, "hello world")
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/content/"+rangeTestId, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			req.Header.Set("Range", "bytes=0-4, -5")
+
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusPartialContent, resp.StatusCode) {
+				return
+			}
+
+			mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "multipart/byteranges", mediaType) {
+				return
+			}
+
+			mr := multipart.NewReader(resp.Body, params["boundary"])
+
+			expected := []struct {
+				contentRange string
+				content      string
+			}{
+				{contentRange: "bytes 0-4/11", content: "hello"},
+				{contentRange: "bytes 6-10/11", content: "world"},
+			}
+			for _, e := range expected {
+				part, err := mr.NextPart()
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, "text/plain", part.Header.Get("Content-Type")) {
+					return
+				}
+				if !assert.Equal(t, e.contentRange, part.Header.Get("Content-Range")) {
+					return
+				}
+
+				b, err := io.ReadAll(part)
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, e.content, string(b)) {
+					return
+				}
+			}
+
+			_, err = mr.NextPart()
+			if !assert.Equal(t, io.EOF, err) {
+				return
+			}
+		})
+	})
+}
+
+// rangeTestId is a Content ID which doesn't need path escaping.
+const rangeTestId = "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok="
+
+func newRangeTestServer(t *testing.T, s string) *httptest.Server {
+	t.Helper()
+
+	store := storageStub{
+		getRange: func(ctx context.Context, ci *contentpb.ContentId, offset, length int64) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(s[offset : offset+length])), nil
+		},
+		get: func(ctx context.Context, ci *contentpb.ContentId) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(s)), nil
+		},
+	}
+
+	idx := memory.New()
+	err := idx.Put(context.Background(), &indexpb.Record{
+		ContentId: &contentpb.ContentId{Value: ptr.Ref(rangeTestId)},
+		ContentType: &contentpb.MediaType{
+			Type:    ptr.Ref("text"),
+			Subtype: ptr.Ref("plain"),
+		},
+		ContentSize: &indexpb.ContentSize{Value: ptr.Ref(uint64(len(s))), Unit: indexpb.UnitOfInformation_BYTE.Enum()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(NewServer(store, idx))
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestFindByChecksumV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the checksum is malformed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/v1/checksums/SHA256")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the content is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.FindByChecksum(context.Background(), &FindByChecksumRequest{
+				Checksum: sha256Checksum([]byte("hello world")),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to get the index record", func(t *testing.T) {
+			idx := indexStub{
+				getByChecksum: func(ctx context.Context, owner string, c *contentpb.Checksum) (*indexpb.Record, error) {
+					return nil, errors.New("failed to get")
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.ContentExists(context.Background(), &ContentExistsRequest{
+				Checksum: sha256Checksum([]byte("hello world")),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will report the content does not exist", func(t *testing.T) {
+		t.Run("if the content is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.ContentExists(context.Background(), &ContentExistsRequest{
+				Checksum: sha256Checksum([]byte("hello world")),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.False(t, resp.Exists) {
+				return
+			}
+			if !assert.Empty(t, resp.Id) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the index record", func(t *testing.T) {
+		t.Run("if content with the checksum is indexed", func(t *testing.T) {
+			checksum := sha256Checksum([]byte("hello world"))
+			id := NewContentId(checksum)
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId:   id,
+				ContentName: ptr.Ref("hello.txt"),
+				CheckSums:   []*contentpb.Checksum{checksum},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			findResp, err := c.FindByChecksum(context.Background(), &FindByChecksumRequest{
+				Checksum: checksum,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello.txt", findResp.Record.GetContentName()) {
+				return
+			}
+
+			existsResp, err := c.ContentExists(context.Background(), &ContentExistsRequest{
+				Checksum: checksum,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.True(t, existsResp.Exists) {
+				return
+			}
+			if !assert.Equal(t, id.GetValue(), existsResp.Id) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+// getContentMetadataV1Handler responds with the indexpb.Record for some content.
+// It also handles HEAD requests which only respond with the status code, so
+// clients can cheaply check if content exists.
+type getContentMetadataV1Handler struct {
+	log          *slog.Logger
+	index        index.Index
+	protoMarshal func(proto.Message) ([]byte, error)
+}
+
+func (h *getContentMetadataV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "getContentMetadataV1Handler.ServeHTTP")
+	defer span.End()
+
+	id := r.PathValue("id")
+	record, err := h.index.Get(spanCtx, callerFromContext(spanCtx), &contentpb.ContentId{
+		Value: &id,
+	})
+	if errors.Is(err, index.ErrNotFound) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "content not found")
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to get index record", slog.String("error", err.Error()))
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to get content metadata")
+		return
+	}
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, record)
+}
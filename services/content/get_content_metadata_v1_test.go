@@ -0,0 +1,176 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestGetContentMetadataV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.GetContentMetadata(context.Background(), &GetContentMetadataRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to get the index record", func(t *testing.T) {
+			idx := indexStub{
+				get: func(ctx context.Context, owner string, ci *contentpb.ContentId) (*indexpb.Record, error) {
+					return nil, errors.New("failed to get")
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.GetContentMetadata(context.Background(), &GetContentMetadataRequest{
+				Id: "fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the index record", func(t *testing.T) {
+		t.Run("if the content is indexed", func(t *testing.T) {
+			hash := sha256.Sum256([]byte("hello world"))
+			checksum := &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+				Hash:     hash[:],
+			}
+			id := NewContentId(checksum)
+
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId:   id,
+				ContentName: ptr.Ref("hello.txt"),
+				ContentType: &contentpb.MediaType{
+					Type:    ptr.Ref("text"),
+					Subtype: ptr.Ref("plain"),
+				},
+				ContentSize: &indexpb.ContentSize{Value: ptr.Ref(uint64(len("hello world"))), Unit: indexpb.UnitOfInformation_BYTE.Enum()},
+				CheckSums:   []*contentpb.Checksum{checksum},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.GetContentMetadata(context.Background(), &GetContentMetadataRequest{
+				Id: id.GetValue(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, id.GetValue(), resp.Record.GetContentId().GetValue()) {
+				return
+			}
+			if !assert.Equal(t, "hello.txt", resp.Record.GetContentName()) {
+				return
+			}
+			if !assert.Equal(t, "plain", resp.Record.GetContentType().GetSubtype()) {
+				return
+			}
+			if !assert.Equal(t, uint64(len("hello world")), resp.Record.GetContentSize().GetValue()) {
+				return
+			}
+			if !assert.Len(t, resp.Record.GetCheckSums(), 1) {
+				return
+			}
+			if !assert.Equal(t, hash[:], resp.Record.GetCheckSums()[0].GetHash()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will only respond with a status code", func(t *testing.T) {
+		t.Run("if the content is not indexed and the request is HEAD", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			resp, err := http.Head(srv.URL + "/v1/content/fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=/metadata")
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusNotFound, resp.StatusCode) {
+				return
+			}
+		})
+
+		t.Run("if the content is indexed and the request is HEAD", func(t *testing.T) {
+			idx := memory.New()
+			err := idx.Put(context.Background(), &indexpb.Record{
+				ContentId: &contentpb.ContentId{Value: ptr.Ref("fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=")},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx))
+			defer srv.Close()
+
+			resp, err := http.Head(srv.URL + "/v1/content/fk3LTu1d6QnOYwO21CplFbIYDxLSUF9sJVL93DTyHok=/metadata")
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,391 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package boltdb implements a persistent Content Index embedded
+// in a single file using bbolt, a pure Go key/value store.
+//
+// Records are stored in a bucket keyed by their owner and Content ID and
+// each supported query is backed by its own bucket of secondary keys.
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"time"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	bolt "go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	metaBucket       = []byte("meta")
+	recordsBucket    = []byte("records")
+	checksumsBucket  = []byte("checksums")
+	contentIdsBucket = []byte("content_ids")
+	mediaTypesBucket = []byte("media_types")
+	namesBucket      = []byte("names")
+
+	indexBuckets = [][]byte{recordsBucket, checksumsBucket, contentIdsBucket, mediaTypesBucket, namesBucket}
+
+	versionKey = []byte("version")
+)
+
+// layoutVersion identifies how records and secondary keys are laid out
+// in the database file. Files using an older layout are migrated by Open.
+const layoutVersion = 1
+
+// DefaultBatchSize is the number of records read per
+// transaction while iterating over query results.
+const DefaultBatchSize = 100
+
+type Option func(*Index)
+
+// BatchSize sets the number of records read per transaction while iterating over query results.
+func BatchSize(n int) Option {
+	return func(idx *Index) {
+		idx.batchSize = max(n, 1)
+	}
+}
+
+// Index is an index.Index persisted to a bbolt database file.
+type Index struct {
+	db        *bolt.DB
+	batchSize int
+}
+
+// Open opens, or creates, the index database file at the given path.
+func Open(path string, opts ...Option) (*Index, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(migrate)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	idx := &Index{
+		db:        db,
+		batchSize: DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(idx)
+	}
+	return idx, nil
+}
+
+func (idx *Index) Close() error {
+	return idx.db.Close()
+}
+
+// migrate creates the buckets of a new database file and rebuilds the buckets
+// of one using an older layout. Records indexed before content had owners
+// were keyed by Content ID alone and are migrated as owned by the empty owner.
+func migrate(tx *bolt.Tx) error {
+	meta, err := tx.CreateBucketIfNotExists(metaBucket)
+	if err != nil {
+		return err
+	}
+	version := meta.Get(versionKey)
+	if len(version) == 1 && version[0] == layoutVersion {
+		return nil
+	}
+
+	var records []*indexpb.Record
+	if b := tx.Bucket(recordsBucket); b != nil {
+		err = b.ForEach(func(k, v []byte) error {
+			var record indexpb.Record
+			err := proto.Unmarshal(v, &record)
+			if err != nil {
+				return err
+			}
+			records = append(records, &record)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range indexBuckets {
+		err = tx.DeleteBucket(name)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		_, err = tx.CreateBucket(name)
+		if err != nil {
+			return err
+		}
+	}
+	for _, record := range records {
+		err = putRecord(tx, record)
+		if err != nil {
+			return err
+		}
+	}
+	return meta.Put(versionKey, []byte{layoutVersion})
+}
+
+func getRecord(tx *bolt.Tx, key []byte) (*indexpb.Record, error) {
+	b := tx.Bucket(recordsBucket).Get(key)
+	if b == nil {
+		return nil, index.ErrNotFound
+	}
+
+	var record indexpb.Record
+	err := proto.Unmarshal(b, &record)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func putRecord(tx *bolt.Tx, record *indexpb.Record) error {
+	b, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(recordsBucket).Put(index.RecordKey(record.GetOwner(), record.GetContentId()), b)
+	if err != nil {
+		return err
+	}
+	return putKeys(tx, record)
+}
+
+func putKeys(tx *bolt.Tx, record *indexpb.Record) error {
+	owner := record.GetOwner()
+	key := index.RecordKey(owner, record.GetContentId())
+	for _, checksum := range record.GetCheckSums() {
+		err := tx.Bucket(checksumsBucket).Put(index.OwnerKey(owner, index.ChecksumKey(checksum)), key)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tx.Bucket(contentIdsBucket).Put(index.ContentIdKey(record), nil)
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(mediaTypesBucket).Put(index.OwnerKey(owner, index.MediaTypeKey(record)), nil)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(namesBucket).Put(index.OwnerKey(owner, index.NameKey(record)), nil)
+}
+
+func deleteKeys(tx *bolt.Tx, record *indexpb.Record) error {
+	owner := record.GetOwner()
+	for _, checksum := range record.GetCheckSums() {
+		err := tx.Bucket(checksumsBucket).Delete(index.OwnerKey(owner, index.ChecksumKey(checksum)))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tx.Bucket(contentIdsBucket).Delete(index.ContentIdKey(record))
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(mediaTypesBucket).Delete(index.OwnerKey(owner, index.MediaTypeKey(record)))
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(namesBucket).Delete(index.OwnerKey(owner, index.NameKey(record)))
+}
+
+func (idx *Index) Put(ctx context.Context, record *indexpb.Record) error {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Index.Put")
+	defer span.End()
+
+	err := index.ValidateRecord(record)
+	if err != nil {
+		return err
+	}
+
+	err = idx.db.Update(func(tx *bolt.Tx) error {
+		old, err := getRecord(tx, index.RecordKey(record.GetOwner(), record.GetContentId()))
+		if err != nil && err != index.ErrNotFound {
+			return err
+		}
+		if old != nil {
+			err = deleteKeys(tx, old)
+			if err != nil {
+				return err
+			}
+		}
+		return putRecord(tx, record)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (idx *Index) Get(ctx context.Context, owner string, id *contentpb.ContentId) (*indexpb.Record, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Index.Get")
+	defer span.End()
+
+	var record *indexpb.Record
+	err := idx.db.View(func(tx *bolt.Tx) (err error) {
+		record, err = getRecord(tx, index.RecordKey(owner, id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (idx *Index) GetByChecksum(ctx context.Context, owner string, checksum *contentpb.Checksum) (*indexpb.Record, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Index.GetByChecksum")
+	defer span.End()
+
+	var record *indexpb.Record
+	err := idx.db.View(func(tx *bolt.Tx) (err error) {
+		key := tx.Bucket(checksumsBucket).Get(index.OwnerKey(owner, index.ChecksumKey(checksum)))
+		if key == nil {
+			return index.ErrNotFound
+		}
+		record, err = getRecord(tx, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (idx *Index) QueryByMediaType(ctx context.Context, owner, typ, subtype string) iter.Seq2[*indexpb.Record, error] {
+	return idx.query(ctx, mediaTypesBucket, ownedRecordKey(owner), index.OwnerKey(owner, index.MediaTypePrefix(typ, subtype)), nil, nil)
+}
+
+func (idx *Index) QueryByName(ctx context.Context, owner, name string) iter.Seq2[*indexpb.Record, error] {
+	return idx.query(ctx, namesBucket, ownedRecordKey(owner), index.OwnerKey(owner, index.NamePrefix(name)), nil, nil)
+}
+
+func (idx *Index) QueryByContentId(ctx context.Context, id *contentpb.ContentId) iter.Seq2[*indexpb.Record, error] {
+	return idx.query(ctx, contentIdsBucket, index.RecordKeyFromContentIdKey, index.ContentIdPrefix(id), nil, nil)
+}
+
+func (idx *Index) List(ctx context.Context, owner string, filter index.Filter, after []byte) iter.Seq2[*indexpb.Record, error] {
+	bucket := recordsBucket
+	switch filter.Order() {
+	case index.OrderByMediaType:
+		bucket = mediaTypesBucket
+	case index.OrderByName:
+		bucket = namesBucket
+	}
+	if len(after) > 0 {
+		after = index.OwnerKey(owner, after)
+	}
+	return idx.query(ctx, bucket, ownedRecordKey(owner), index.OwnerKey(owner, filter.Prefix()), after, filter.Match)
+}
+
+// ownedRecordKey maps the owner's index keys, which end
+// with a Content ID, to the key of the record they belong to.
+func ownedRecordKey(owner string) func([]byte) []byte {
+	return func(key []byte) []byte {
+		return index.OwnerKey(owner, []byte(index.ContentIdFromKey(key)))
+	}
+}
+
+// query reads matching records in batches, each in its own read transaction,
+// so consumers are free to modify the index while iterating.
+func (idx *Index) query(ctx context.Context, bucket []byte, recordKey func([]byte) []byte, prefix []byte, after []byte, match func(*indexpb.Record) bool) iter.Seq2[*indexpb.Record, error] {
+	return func(yield func(*indexpb.Record, error) bool) {
+		if bytes.Compare(after, prefix) < 0 {
+			after = nil
+		}
+		for {
+			records := make([]*indexpb.Record, 0, idx.batchSize)
+			done := false
+			err := idx.db.View(func(tx *bolt.Tx) error {
+				c := tx.Bucket(bucket).Cursor()
+
+				k, _ := c.Seek(prefix)
+				if after != nil {
+					k, _ = c.Seek(after)
+					if bytes.Equal(k, after) {
+						k, _ = c.Next()
+					}
+				}
+				for ; k != nil && bytes.HasPrefix(k, prefix) && len(records) < idx.batchSize; k, _ = c.Next() {
+					after = bytes.Clone(k)
+
+					record, err := getRecord(tx, recordKey(k))
+					if err != nil {
+						return err
+					}
+					if match != nil && !match(record) {
+						continue
+					}
+					records = append(records, record)
+				}
+				done = k == nil || !bytes.HasPrefix(k, prefix)
+				return nil
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, record := range records {
+				err := ctx.Err()
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(record, nil) {
+					return
+				}
+			}
+			if done {
+				return
+			}
+		}
+	}
+}
+
+func (idx *Index) Delete(ctx context.Context, owner string, id *contentpb.ContentId) error {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Index.Delete")
+	defer span.End()
+
+	key := index.RecordKey(owner, id)
+	err := idx.db.Update(func(tx *bolt.Tx) error {
+		record, err := getRecord(tx, key)
+		if err != nil {
+			return err
+		}
+
+		err = deleteKeys(tx, record)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(recordsBucket).Delete(key)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
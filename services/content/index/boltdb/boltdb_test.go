@@ -0,0 +1,142 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/index/indextest"
+
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestIndex(t *testing.T) {
+	indextest.Run(t, func(t *testing.T) index.Index {
+		// a batch size of 1 ensures queries span multiple transactions
+		idx, err := Open(filepath.Join(t.TempDir(), "index.db"), BatchSize(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			idx.Close()
+		})
+		return idx
+	})
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("will persist records", func(t *testing.T) {
+		t.Run("if the index is closed and reopened", func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "index.db")
+			idx, err := Open(name)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			record := indextest.NewRecord("hello", "text", "plain")
+			err = idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = idx.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			idx, err = Open(name)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer idx.Close()
+
+			got, err := idx.GetByChecksum(context.Background(), "", record.GetCheckSums()[0])
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, record.GetContentId().GetValue(), got.GetContentId().GetValue()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will migrate records", func(t *testing.T) {
+		t.Run("if they were indexed before content had owners", func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "index.db")
+			record := indextest.NewRecord("hello", "text", "plain")
+
+			db, err := bolt.Open(name, 0o600, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = db.Update(func(tx *bolt.Tx) error {
+				b, err := proto.Marshal(record)
+				if err != nil {
+					return err
+				}
+				records, err := tx.CreateBucket(recordsBucket)
+				if err != nil {
+					return err
+				}
+				err = records.Put([]byte(record.GetContentId().GetValue()), b)
+				if err != nil {
+					return err
+				}
+				names, err := tx.CreateBucket(namesBucket)
+				if err != nil {
+					return err
+				}
+				return names.Put([]byte("hello\x00"+record.GetContentId().GetValue()), nil)
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = db.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			idx, err := Open(name)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer idx.Close()
+
+			got, err := idx.Get(context.Background(), "", record.GetContentId())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.True(t, proto.Equal(record, got)) {
+				return
+			}
+
+			var owners []string
+			for record, err := range idx.QueryByContentId(context.Background(), record.GetContentId()) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				owners = append(owners, record.GetOwner())
+			}
+			if !assert.Equal(t, []string{""}, owners) {
+				return
+			}
+		})
+	})
+}
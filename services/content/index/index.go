@@ -0,0 +1,228 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package index defines the Content Index used by the Content Service.
+package index
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"strings"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/indexpb"
+)
+
+var (
+	ErrNotFound         = errors.New("index record not found")
+	ErrMissingContentId = errors.New("index record is missing content id")
+	ErrInvalidOwner     = errors.New("index record owner must not contain a NUL character")
+)
+
+// Index stores a Record for every piece of content which has been
+// successfully stored, keyed by its owner and Content ID. The same content
+// may be indexed once for every owner and every query, except for
+// QueryByContentId, is scoped to a single owner. Every supported query
+// is backed by a secondary index so that listing content never requires
+// scanning Content Storage.
+type Index interface {
+	// Put creates or replaces the Record for its owner and Content ID.
+	Put(ctx context.Context, record *indexpb.Record) error
+
+	Get(ctx context.Context, owner string, id *contentpb.ContentId) (*indexpb.Record, error)
+
+	// GetByChecksum returns the Record of the owner which contains the given checksum.
+	GetByChecksum(ctx context.Context, owner string, checksum *contentpb.Checksum) (*indexpb.Record, error)
+
+	// QueryByMediaType returns all Records of the owner with the given media type and,
+	// if it's not empty, the given subtype ordered by subtype and Content ID.
+	QueryByMediaType(ctx context.Context, owner, typ, subtype string) iter.Seq2[*indexpb.Record, error]
+
+	// QueryByName returns all Records of the owner with the given name ordered by Content ID.
+	QueryByName(ctx context.Context, owner, name string) iter.Seq2[*indexpb.Record, error]
+
+	// QueryByContentId returns the Records of every owner of the content ordered
+	// by owner, e.g. for finding out if content is still indexed by anyone.
+	QueryByContentId(ctx context.Context, id *contentpb.ContentId) iter.Seq2[*indexpb.Record, error]
+
+	// List returns all Records of the owner matching the filter ordered by their ListKey.
+	// If after is not empty, only Records ordered after it are returned which
+	// allows a listing to be resumed from the ListKey of the last Record seen.
+	List(ctx context.Context, owner string, filter Filter, after []byte) iter.Seq2[*indexpb.Record, error]
+
+	Delete(ctx context.Context, owner string, id *contentpb.ContentId) error
+}
+
+// ValidateOwner checks the owner can be used in index keys.
+func ValidateOwner(owner string) error {
+	if strings.Contains(owner, keySep) {
+		return ErrInvalidOwner
+	}
+	return nil
+}
+
+// ValidateRecord checks the record has everything required to be indexed.
+func ValidateRecord(record *indexpb.Record) error {
+	if record.GetContentId().GetValue() == "" {
+		return ErrMissingContentId
+	}
+	return ValidateOwner(record.GetOwner())
+}
+
+// Filter narrows down the Records returned by List. The zero value matches every Record.
+type Filter struct {
+	// MediaType and MediaSubtype match Records by media type. MediaSubtype is
+	// only considered when MediaType is set.
+	MediaType    string
+	MediaSubtype string
+
+	// NamePrefix matches Records whose name begins with it.
+	NamePrefix string
+}
+
+// ListOrder identifies which key a List is ordered by.
+type ListOrder int
+
+const (
+	OrderByContentId ListOrder = iota
+	OrderByMediaType
+	OrderByName
+)
+
+// Order returns the most selective key for listing Records matching the filter.
+func (f Filter) Order() ListOrder {
+	switch {
+	case f.MediaType != "":
+		return OrderByMediaType
+	case f.NamePrefix != "":
+		return OrderByName
+	default:
+		return OrderByContentId
+	}
+}
+
+// Prefix returns the prefix shared by the ListKeys of all Records which may match the filter.
+func (f Filter) Prefix() []byte {
+	switch f.Order() {
+	case OrderByMediaType:
+		return MediaTypePrefix(f.MediaType, f.MediaSubtype)
+	case OrderByName:
+		return []byte(f.NamePrefix)
+	default:
+		return nil
+	}
+}
+
+// ListKey returns the key which orders the record within a List using the filter.
+func (f Filter) ListKey(record *indexpb.Record) []byte {
+	switch f.Order() {
+	case OrderByMediaType:
+		return MediaTypeKey(record)
+	case OrderByName:
+		return NameKey(record)
+	default:
+		return []byte(record.GetContentId().GetValue())
+	}
+}
+
+// Match reports whether the record satisfies every condition of the filter.
+func (f Filter) Match(record *indexpb.Record) bool {
+	if f.MediaType != "" {
+		mediaType := record.GetContentType()
+		if !strings.EqualFold(f.MediaType, mediaType.GetType()) {
+			return false
+		}
+		if f.MediaSubtype != "" && !strings.EqualFold(f.MediaSubtype, mediaType.GetSubtype()) {
+			return false
+		}
+	}
+	return strings.HasPrefix(record.GetContentName(), f.NamePrefix)
+}
+
+// keySep separates the components of index keys. It can't
+// appear in any valid owner, media type or Content ID.
+const keySep = "\x00"
+
+// OwnerKey prefixes the key with the owner, so the keys of every owner are kept
+// apart and all of them share the prefix returned by OwnerKey(owner, nil).
+func OwnerKey(owner string, key []byte) []byte {
+	return append([]byte(owner+keySep), key...)
+}
+
+// RecordKey returns the primary key of the owner's Record for the content.
+func RecordKey(owner string, id *contentpb.ContentId) []byte {
+	return OwnerKey(owner, []byte(id.GetValue()))
+}
+
+// ContentIdKey returns the secondary index key for querying a Record by Content ID.
+func ContentIdKey(record *indexpb.Record) []byte {
+	return []byte(record.GetContentId().GetValue() + keySep + record.GetOwner())
+}
+
+// ContentIdPrefix returns the prefix of all ContentIdKeys of the content.
+func ContentIdPrefix(id *contentpb.ContentId) []byte {
+	return []byte(id.GetValue() + keySep)
+}
+
+// RecordKeyFromContentIdKey returns the primary key of the Record a ContentIdKey belongs to.
+func RecordKeyFromContentIdKey(key []byte) []byte {
+	id, owner, _ := bytes.Cut(key, []byte(keySep))
+	return OwnerKey(string(owner), id)
+}
+
+// ChecksumKey returns the secondary index key for looking up a Record by checksum.
+func ChecksumKey(checksum *contentpb.Checksum) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(checksum.GetHashFunc().String())
+	buf.WriteString(keySep)
+	buf.Write(checksum.GetHash())
+	return buf.Bytes()
+}
+
+// MediaTypeKey returns the secondary index key for querying a Record by media type.
+func MediaTypeKey(record *indexpb.Record) []byte {
+	mediaType := record.GetContentType()
+	return []byte(strings.Join([]string{
+		strings.ToLower(mediaType.GetType()),
+		strings.ToLower(mediaType.GetSubtype()),
+		record.GetContentId().GetValue(),
+	}, keySep))
+}
+
+// MediaTypePrefix returns the prefix of all MediaTypeKeys matching the given type and optional subtype.
+func MediaTypePrefix(typ, subtype string) []byte {
+	prefix := strings.ToLower(typ) + keySep
+	if subtype != "" {
+		prefix += strings.ToLower(subtype) + keySep
+	}
+	return []byte(prefix)
+}
+
+// NameKey returns the secondary index key for querying a Record by name.
+func NameKey(record *indexpb.Record) []byte {
+	return []byte(record.GetContentName() + keySep + record.GetContentId().GetValue())
+}
+
+// ContentIdFromKey returns the Content ID value primary and secondary index keys,
+// other than ContentIdKeys, end with.
+func ContentIdFromKey(key []byte) string {
+	return string(key[bytes.LastIndex(key, []byte(keySep))+1:])
+}
+
+// NamePrefix returns the prefix of all NameKeys matching the given name.
+func NamePrefix(name string) []byte {
+	return []byte(name + keySep)
+}
@@ -0,0 +1,564 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indextest provides a conformance test suite for index.Index implementations.
+package indextest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewRecord returns a Record for content with the given name and media type
+// whose checksum and Content ID are derived from the name.
+func NewRecord(name, typ, subtype string) *indexpb.Record {
+	hash := sha256.Sum256([]byte(name))
+	id := sha256.Sum256(hash[:])
+	return &indexpb.Record{
+		ContentId: &contentpb.ContentId{
+			Value: ptr.Ref(base64.StdEncoding.EncodeToString(id[:])),
+		},
+		ContentType: &contentpb.MediaType{
+			Type:    ptr.Ref(typ),
+			Subtype: ptr.Ref(subtype),
+		},
+		ContentName: ptr.Ref(name),
+		ContentSize: &indexpb.ContentSize{
+			Value: ptr.Ref(uint64(len(name))),
+			Unit:  indexpb.UnitOfInformation_BYTE.Enum(),
+		},
+		CheckSums: []*contentpb.Checksum{
+			{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+				Hash:     hash[:],
+			},
+		},
+	}
+}
+
+// Owned returns a copy of the record owned by the given owner.
+func Owned(record *indexpb.Record, owner string) *indexpb.Record {
+	record = proto.Clone(record).(*indexpb.Record)
+	record.Owner = ptr.Ref(owner)
+	return record
+}
+
+func names(t *testing.T, records func(yield func(*indexpb.Record, error) bool)) []string {
+	t.Helper()
+
+	var ns []string
+	for record, err := range records {
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		ns = append(ns, record.GetContentName())
+	}
+	return ns
+}
+
+// Run runs the conformance test suite against the index.Index returned by newIndex.
+// A new index.Index is requested for every test.
+func Run(t *testing.T, newIndex func(*testing.T) index.Index) {
+	ctx := context.Background()
+
+	t.Run("Put", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the record is missing a content id", func(t *testing.T) {
+				idx := newIndex(t)
+
+				err := idx.Put(ctx, &indexpb.Record{})
+				if !assert.ErrorIs(t, err, index.ErrMissingContentId) {
+					return
+				}
+			})
+
+			t.Run("if the record owner contains a NUL character", func(t *testing.T) {
+				idx := newIndex(t)
+
+				err := idx.Put(ctx, Owned(NewRecord("hello", "text", "plain"), "bob\x00"))
+				if !assert.ErrorIs(t, err, index.ErrInvalidOwner) {
+					return
+				}
+			})
+		})
+
+		t.Run("will replace the existing record", func(t *testing.T) {
+			t.Run("if a record with the same content id already exists", func(t *testing.T) {
+				idx := newIndex(t)
+
+				record := NewRecord("Naruto S01E01", "video", "av1")
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				renamed := proto.Clone(record).(*indexpb.Record)
+				renamed.ContentName = ptr.Ref("Naruto S01E02")
+				renamed.ContentType.Subtype = ptr.Ref("mp4")
+				err = idx.Put(ctx, renamed)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				got, err := idx.Get(ctx, "", record.GetContentId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(renamed, got)) {
+					return
+				}
+				if !assert.Empty(t, names(t, idx.QueryByName(ctx, "", "Naruto S01E01"))) {
+					return
+				}
+				if !assert.Empty(t, names(t, idx.QueryByMediaType(ctx, "", "video", "av1"))) {
+					return
+				}
+				if !assert.Equal(t, []string{"Naruto S01E02"}, names(t, idx.QueryByMediaType(ctx, "", "video", "mp4"))) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the record does not exist", func(t *testing.T) {
+				idx := newIndex(t)
+
+				_, err := idx.Get(ctx, "", NewRecord("hello", "text", "plain").GetContentId())
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the record", func(t *testing.T) {
+			t.Run("if it was previously put", func(t *testing.T) {
+				idx := newIndex(t)
+
+				record := NewRecord("hello", "text", "plain")
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				got, err := idx.Get(ctx, "", record.GetContentId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(record, got)) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("GetByChecksum", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if no record contains the checksum", func(t *testing.T) {
+				idx := newIndex(t)
+
+				_, err := idx.GetByChecksum(ctx, "", NewRecord("hello", "text", "plain").GetCheckSums()[0])
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the record", func(t *testing.T) {
+			t.Run("if it contains the checksum", func(t *testing.T) {
+				idx := newIndex(t)
+
+				record := NewRecord("hello", "text", "plain")
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+				err = idx.Put(ctx, NewRecord("goodbye", "text", "plain"))
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				got, err := idx.GetByChecksum(ctx, "", record.GetCheckSums()[0])
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(record, got)) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("QueryByMediaType", func(t *testing.T) {
+		t.Run("will return matching records", func(t *testing.T) {
+			idx := newIndex(t)
+
+			for _, record := range []*indexpb.Record{
+				NewRecord("a", "video", "mp4"),
+				NewRecord("b", "video", "av1"),
+				NewRecord("c", "Video", "AV1"),
+				NewRecord("d", "audio", "flac"),
+				NewRecord("e", "videos", "av1"),
+			} {
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			t.Run("if only the type is provided", func(t *testing.T) {
+				ns := names(t, idx.QueryByMediaType(ctx, "", "video", ""))
+				if !assert.ElementsMatch(t, []string{"a", "b", "c"}, ns) {
+					return
+				}
+				if !assert.Equal(t, "a", ns[2]) {
+					return
+				}
+			})
+
+			t.Run("if the type and subtype are provided", func(t *testing.T) {
+				ns := names(t, idx.QueryByMediaType(ctx, "", "VIDEO", "av1"))
+				if !assert.ElementsMatch(t, []string{"b", "c"}, ns) {
+					return
+				}
+			})
+
+			t.Run("if the iteration is stopped early", func(t *testing.T) {
+				var ns []string
+				for record, err := range idx.QueryByMediaType(ctx, "", "video", "") {
+					if !assert.Nil(t, err) {
+						return
+					}
+					ns = append(ns, record.GetContentName())
+					break
+				}
+				if !assert.Len(t, ns, 1) {
+					return
+				}
+			})
+		})
+
+		t.Run("will allow modifying the index", func(t *testing.T) {
+			t.Run("if it is done while iterating", func(t *testing.T) {
+				idx := newIndex(t)
+
+				for _, name := range []string{"a", "b", "c"} {
+					err := idx.Put(ctx, NewRecord(name, "video", "mp4"))
+					if !assert.Nil(t, err) {
+						return
+					}
+				}
+
+				for record, err := range idx.QueryByMediaType(ctx, "", "video", "mp4") {
+					if !assert.Nil(t, err) {
+						return
+					}
+					err = idx.Delete(ctx, "", record.GetContentId())
+					if !assert.Nil(t, err) {
+						return
+					}
+				}
+				if !assert.Empty(t, names(t, idx.QueryByMediaType(ctx, "", "video", ""))) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("QueryByName", func(t *testing.T) {
+		t.Run("will only return records with the exact name", func(t *testing.T) {
+			idx := newIndex(t)
+
+			for _, record := range []*indexpb.Record{
+				NewRecord("Naruto", "video", "av1"),
+				NewRecord("Naruto S01E01", "video", "av1"),
+			} {
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			if !assert.Equal(t, []string{"Naruto"}, names(t, idx.QueryByName(ctx, "", "Naruto"))) {
+				return
+			}
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Run("will return matching records", func(t *testing.T) {
+			idx := newIndex(t)
+
+			for _, record := range []*indexpb.Record{
+				NewRecord("Naruto S01E01", "video", "av1"),
+				NewRecord("Naruto S01E02", "video", "mp4"),
+				NewRecord("Naruto OST", "audio", "flac"),
+				NewRecord("Bleach S01E01", "video", "av1"),
+			} {
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			t.Run("if the filter is empty", func(t *testing.T) {
+				ns := names(t, idx.List(ctx, "", index.Filter{}, nil))
+				if !assert.ElementsMatch(t, []string{"Naruto S01E01", "Naruto S01E02", "Naruto OST", "Bleach S01E01"}, ns) {
+					return
+				}
+			})
+
+			t.Run("if only a name prefix is provided", func(t *testing.T) {
+				ns := names(t, idx.List(ctx, "", index.Filter{NamePrefix: "Naruto S"}, nil))
+				if !assert.Equal(t, []string{"Naruto S01E01", "Naruto S01E02"}, ns) {
+					return
+				}
+			})
+
+			t.Run("if a media type and name prefix are provided", func(t *testing.T) {
+				filter := index.Filter{
+					MediaType:  "video",
+					NamePrefix: "Naruto",
+				}
+				ns := names(t, idx.List(ctx, "", filter, nil))
+				if !assert.ElementsMatch(t, []string{"Naruto S01E01", "Naruto S01E02"}, ns) {
+					return
+				}
+			})
+
+			t.Run("if a media type and subtype are provided", func(t *testing.T) {
+				filter := index.Filter{
+					MediaType:    "video",
+					MediaSubtype: "av1",
+				}
+				ns := names(t, idx.List(ctx, "", filter, nil))
+				if !assert.ElementsMatch(t, []string{"Naruto S01E01", "Bleach S01E01"}, ns) {
+					return
+				}
+			})
+		})
+
+		t.Run("will resume after the given list key", func(t *testing.T) {
+			idx := newIndex(t)
+
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				err := idx.Put(ctx, NewRecord(name, "video", "mp4"))
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			for _, filter := range []index.Filter{{}, {MediaType: "video"}, {NamePrefix: "a"}} {
+				var all []string
+				var after []byte
+				for {
+					var page []string
+					for record, err := range idx.List(ctx, "", filter, after) {
+						if !assert.Nil(t, err) {
+							return
+						}
+						page = append(page, record.GetContentName())
+						after = filter.ListKey(record)
+						if len(page) == 2 {
+							break
+						}
+					}
+					all = append(all, page...)
+					if len(page) < 2 {
+						break
+					}
+				}
+				if !assert.ElementsMatch(t, names(t, idx.List(ctx, "", filter, nil)), all) {
+					return
+				}
+			}
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the record does not exist", func(t *testing.T) {
+				idx := newIndex(t)
+
+				err := idx.Delete(ctx, "", NewRecord("hello", "text", "plain").GetContentId())
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will remove the record from every query", func(t *testing.T) {
+			t.Run("if the record exists", func(t *testing.T) {
+				idx := newIndex(t)
+
+				record := NewRecord("hello", "text", "plain")
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				err = idx.Delete(ctx, "", record.GetContentId())
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = idx.Get(ctx, "", record.GetContentId())
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+				_, err = idx.GetByChecksum(ctx, "", record.GetCheckSums()[0])
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+				if !assert.Empty(t, names(t, idx.QueryByMediaType(ctx, "", "text", ""))) {
+					return
+				}
+				if !assert.Empty(t, names(t, idx.QueryByName(ctx, "", "hello"))) {
+					return
+				}
+				if !assert.Empty(t, names(t, idx.QueryByContentId(ctx, record.GetContentId()))) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Run("will index the same content separately", func(t *testing.T) {
+			t.Run("for every owner", func(t *testing.T) {
+				idx := newIndex(t)
+
+				record := NewRecord("hello", "text", "plain")
+				for _, owner := range []string{"bob", "alice"} {
+					err := idx.Put(ctx, Owned(record, owner))
+					if !assert.Nil(t, err) {
+						return
+					}
+				}
+
+				err := idx.Delete(ctx, "bob", record.GetContentId())
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = idx.Get(ctx, "bob", record.GetContentId())
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+				got, err := idx.Get(ctx, "alice", record.GetContentId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, "alice", got.GetOwner()) {
+					return
+				}
+				got, err = idx.GetByChecksum(ctx, "alice", record.GetCheckSums()[0])
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, "alice", got.GetOwner()) {
+					return
+				}
+			})
+		})
+
+		t.Run("will only return records of the owner", func(t *testing.T) {
+			idx := newIndex(t)
+
+			records := []*indexpb.Record{
+				Owned(NewRecord("Naruto S01E01", "video", "av1"), "bob"),
+				Owned(NewRecord("Naruto S01E02", "video", "av1"), "alice"),
+				Owned(NewRecord("Naruto S01E03", "video", "av1"), "bobby"),
+			}
+			for _, record := range records {
+				err := idx.Put(ctx, record)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			t.Run("if the record is looked up by checksum", func(t *testing.T) {
+				_, err := idx.GetByChecksum(ctx, "bob", records[1].GetCheckSums()[0])
+				if !assert.ErrorIs(t, err, index.ErrNotFound) {
+					return
+				}
+			})
+
+			t.Run("if the records are queried by media type", func(t *testing.T) {
+				ns := names(t, idx.QueryByMediaType(ctx, "bob", "video", "av1"))
+				if !assert.Equal(t, []string{"Naruto S01E01"}, ns) {
+					return
+				}
+			})
+
+			t.Run("if the records are queried by name", func(t *testing.T) {
+				ns := names(t, idx.QueryByName(ctx, "bob", "Naruto S01E02"))
+				if !assert.Empty(t, ns) {
+					return
+				}
+			})
+
+			t.Run("if the records are listed", func(t *testing.T) {
+				for _, filter := range []index.Filter{{}, {NamePrefix: "Naruto"}, {MediaType: "video"}} {
+					ns := names(t, idx.List(ctx, "bob", filter, nil))
+					if !assert.Equal(t, []string{"Naruto S01E01"}, ns) {
+						return
+					}
+				}
+			})
+		})
+	})
+
+	t.Run("QueryByContentId", func(t *testing.T) {
+		t.Run("will return the record of every owner", func(t *testing.T) {
+			t.Run("ordered by owner", func(t *testing.T) {
+				idx := newIndex(t)
+
+				record := NewRecord("hello", "text", "plain")
+				for _, owner := range []string{"bob", "alice", ""} {
+					err := idx.Put(ctx, Owned(record, owner))
+					if !assert.Nil(t, err) {
+						return
+					}
+				}
+				err := idx.Put(ctx, Owned(NewRecord("goodbye", "text", "plain"), "bob"))
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				var owners []string
+				for record, err := range idx.QueryByContentId(ctx, record.GetContentId()) {
+					if !assert.Nil(t, err) {
+						return
+					}
+					owners = append(owners, record.GetOwner())
+				}
+				if !assert.Equal(t, []string{"", "alice", "bob"}, owners) {
+					return
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements an in-memory Content Index.
+package memory
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Index is an index.Index which keeps all records in memory.
+// It's primarily intended for testing and ephemeral deployments.
+type Index struct {
+	mu         sync.RWMutex
+	records    map[string]*indexpb.Record
+	checksums  map[string]string
+	recordKeys []string
+	contentIds []string
+	mediaTypes []string
+	names      []string
+}
+
+func New() *Index {
+	return &Index{
+		records:   make(map[string]*indexpb.Record),
+		checksums: make(map[string]string),
+	}
+}
+
+func (idx *Index) Put(ctx context.Context, record *indexpb.Record) error {
+	err := index.ValidateRecord(record)
+	if err != nil {
+		return err
+	}
+	key := string(index.RecordKey(record.GetOwner(), record.GetContentId()))
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	old, exists := idx.records[key]
+	if exists {
+		idx.removeKeys(old)
+	}
+
+	record = proto.Clone(record).(*indexpb.Record)
+	owner := record.GetOwner()
+	idx.records[key] = record
+	idx.recordKeys = insertSorted(idx.recordKeys, key)
+	for _, checksum := range record.GetCheckSums() {
+		idx.checksums[string(index.OwnerKey(owner, index.ChecksumKey(checksum)))] = key
+	}
+	idx.contentIds = insertSorted(idx.contentIds, string(index.ContentIdKey(record)))
+	idx.mediaTypes = insertSorted(idx.mediaTypes, string(index.OwnerKey(owner, index.MediaTypeKey(record))))
+	idx.names = insertSorted(idx.names, string(index.OwnerKey(owner, index.NameKey(record))))
+	return nil
+}
+
+func (idx *Index) removeKeys(record *indexpb.Record) {
+	owner := record.GetOwner()
+	for _, checksum := range record.GetCheckSums() {
+		delete(idx.checksums, string(index.OwnerKey(owner, index.ChecksumKey(checksum))))
+	}
+	idx.contentIds = deleteSorted(idx.contentIds, string(index.ContentIdKey(record)))
+	idx.mediaTypes = deleteSorted(idx.mediaTypes, string(index.OwnerKey(owner, index.MediaTypeKey(record))))
+	idx.names = deleteSorted(idx.names, string(index.OwnerKey(owner, index.NameKey(record))))
+}
+
+func insertSorted(keys []string, key string) []string {
+	i, found := slices.BinarySearch(keys, key)
+	if found {
+		return keys
+	}
+	return slices.Insert(keys, i, key)
+}
+
+func deleteSorted(keys []string, key string) []string {
+	i, found := slices.BinarySearch(keys, key)
+	if !found {
+		return keys
+	}
+	return slices.Delete(keys, i, i+1)
+}
+
+func (idx *Index) Get(ctx context.Context, owner string, id *contentpb.ContentId) (*indexpb.Record, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	record, exists := idx.records[string(index.RecordKey(owner, id))]
+	if !exists {
+		return nil, index.ErrNotFound
+	}
+	return proto.Clone(record).(*indexpb.Record), nil
+}
+
+func (idx *Index) GetByChecksum(ctx context.Context, owner string, checksum *contentpb.Checksum) (*indexpb.Record, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	key, exists := idx.checksums[string(index.OwnerKey(owner, index.ChecksumKey(checksum)))]
+	if !exists {
+		return nil, index.ErrNotFound
+	}
+	return proto.Clone(idx.records[key]).(*indexpb.Record), nil
+}
+
+func (idx *Index) QueryByMediaType(ctx context.Context, owner, typ, subtype string) iter.Seq2[*indexpb.Record, error] {
+	return idx.query(ctx, func() []string { return idx.mediaTypes }, ownedRecordKey(owner), index.OwnerKey(owner, index.MediaTypePrefix(typ, subtype)), nil, nil)
+}
+
+func (idx *Index) QueryByName(ctx context.Context, owner, name string) iter.Seq2[*indexpb.Record, error] {
+	return idx.query(ctx, func() []string { return idx.names }, ownedRecordKey(owner), index.OwnerKey(owner, index.NamePrefix(name)), nil, nil)
+}
+
+func (idx *Index) QueryByContentId(ctx context.Context, id *contentpb.ContentId) iter.Seq2[*indexpb.Record, error] {
+	return idx.query(ctx, func() []string { return idx.contentIds }, index.RecordKeyFromContentIdKey, index.ContentIdPrefix(id), nil, nil)
+}
+
+func (idx *Index) List(ctx context.Context, owner string, filter index.Filter, after []byte) iter.Seq2[*indexpb.Record, error] {
+	var keys func() []string
+	switch filter.Order() {
+	case index.OrderByMediaType:
+		keys = func() []string { return idx.mediaTypes }
+	case index.OrderByName:
+		keys = func() []string { return idx.names }
+	default:
+		keys = func() []string { return idx.recordKeys }
+	}
+	if len(after) > 0 {
+		after = index.OwnerKey(owner, after)
+	}
+	return idx.query(ctx, keys, ownedRecordKey(owner), index.OwnerKey(owner, filter.Prefix()), after, filter.Match)
+}
+
+// ownedRecordKey maps the owner's index keys, which end
+// with a Content ID, to the key of the record they belong to.
+func ownedRecordKey(owner string) func([]byte) []byte {
+	return func(key []byte) []byte {
+		return index.OwnerKey(owner, []byte(index.ContentIdFromKey(key)))
+	}
+}
+
+// query snapshots the matching records before yielding any of them
+// so consumers are free to modify the index while iterating.
+func (idx *Index) query(ctx context.Context, keys func() []string, recordKey func([]byte) []byte, prefix []byte, after []byte, match func(*indexpb.Record) bool) iter.Seq2[*indexpb.Record, error] {
+	return func(yield func(*indexpb.Record, error) bool) {
+		idx.mu.RLock()
+		ks := keys()
+		i, _ := slices.BinarySearch(ks, string(prefix))
+		if len(after) > 0 {
+			j, found := slices.BinarySearch(ks, string(after))
+			if found {
+				j++
+			}
+			i = max(i, j)
+		}
+		var records []*indexpb.Record
+		for ; i < len(ks) && strings.HasPrefix(ks[i], string(prefix)); i++ {
+			record := idx.records[string(recordKey([]byte(ks[i])))]
+			if match != nil && !match(record) {
+				continue
+			}
+			records = append(records, proto.Clone(record).(*indexpb.Record))
+		}
+		idx.mu.RUnlock()
+
+		for _, record := range records {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (idx *Index) Delete(ctx context.Context, owner string, id *contentpb.ContentId) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	key := string(index.RecordKey(owner, id))
+	record, exists := idx.records[key]
+	if !exists {
+		return index.ErrNotFound
+	}
+	idx.removeKeys(record)
+	idx.recordKeys = deleteSorted(idx.recordKeys, key)
+	delete(idx.records, key)
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"sync"
+)
+
+// keyedLocks hands out a read-write lock per key, e.g. per Content ID, and only
+// keeps the locks which are currently held. The locks only cover a single Server,
+// so they don't serialize requests served by other Servers sharing the same stores.
+type keyedLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.RWMutex
+	refs int
+}
+
+// share acquires a shared lock on the key and returns the func for releasing it.
+func (l *keyedLocks) share(key string) func() {
+	lock := l.acquire(key)
+	lock.RLock()
+	return func() {
+		lock.RUnlock()
+		l.release(key)
+	}
+}
+
+// exclusive acquires an exclusive lock on the key and returns the func for releasing it.
+func (l *keyedLocks) exclusive(key string) func() {
+	lock := l.acquire(key)
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.release(key)
+	}
+}
+
+func (l *keyedLocks) acquire(key string) *keyedLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locks == nil {
+		l.locks = make(map[string]*keyedLock)
+	}
+	lock, exists := l.locks[key]
+	if !exists {
+		lock = &keyedLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+func (l *keyedLocks) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock := l.locks[key]
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, key)
+	}
+}
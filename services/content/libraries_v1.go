@@ -0,0 +1,246 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/library"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrMissingLibraryName = errors.New("library name is required")
+
+// librariesV1Handler implements the APIs for organizing content and Collections
+// into Libraries. Every Library belongs to the user who created it, can only
+// contain that user's content and Collections and is uniquely named among
+// that user's Libraries.
+type librariesV1Handler struct {
+	log            *slog.Logger
+	libraries      library.Store
+	collections    collection.Store
+	index          index.Index
+	protoMarshal   func(proto.Message) ([]byte, error)
+	protoUnmarshal func([]byte, proto.Message) error
+}
+
+func (h *librariesV1Handler) create(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "librariesV1Handler.create")
+	defer span.End()
+
+	var req librarypb.CreateLibraryV1Request
+	err := h.readRequest(r, &req)
+	if err == nil && req.GetName() == "" {
+		err = ErrMissingLibraryName
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read create library request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	l, err := library.Create(spanCtx, h.libraries, callerFromContext(spanCtx), req.GetName())
+	if errors.Is(err, library.ErrNameExists) {
+		span.RecordError(err)
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_ALREADY_EXISTS, fmt.Sprintf("a library named %q already exists", req.GetName()))
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to create library", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to create library")
+		return
+	}
+
+	w.Header().Set("Location", "/v1/libraries/"+url.PathEscape(l.GetId()))
+	writeProto(h.log, w, http.StatusCreated, h.protoMarshal, l)
+}
+
+// list responds with every Library of the caller or, if a name is
+// given, only the Library with that name, if there is one.
+func (h *librariesV1Handler) list(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "librariesV1Handler.list")
+	defer span.End()
+
+	owner := callerFromContext(spanCtx)
+	resp := &librarypb.ListLibrariesV1Response{}
+	if r.URL.Query().Has("name") {
+		l, err := h.libraries.GetByName(spanCtx, owner, r.URL.Query().Get("name"))
+		if err != nil && !errors.Is(err, library.ErrNotFound) {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to get library by name", slog.String("error", err.Error()))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to list libraries")
+			return
+		}
+		if l != nil {
+			resp.Libraries = append(resp.Libraries, l)
+		}
+		writeProto(h.log, w, http.StatusOK, h.protoMarshal, resp)
+		return
+	}
+
+	for l, err := range h.libraries.List(spanCtx, owner) {
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list libraries", slog.String("error", err.Error()))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to list libraries")
+			return
+		}
+		resp.Libraries = append(resp.Libraries, l)
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, resp)
+}
+
+func (h *librariesV1Handler) get(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "librariesV1Handler.get")
+	defer span.End()
+
+	l, err := h.libraries.Get(spanCtx, callerFromContext(spanCtx), r.PathValue("id"))
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to get library", err)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, l)
+}
+
+func (h *librariesV1Handler) addItem(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "librariesV1Handler.addItem")
+	defer span.End()
+
+	var req librarypb.AddLibraryItemV1Request
+	err := h.readRequest(r, &req)
+	if err == nil {
+		err = validateAddLibraryItemRequest(&req)
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read add library item request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	owner := callerFromContext(spanCtx)
+	err = h.validateItem(spanCtx, owner, req.GetType(), req.GetId())
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to validate library item", err)
+		return
+	}
+
+	item := &librarypb.LibraryItem{
+		Type: req.GetType().Enum(),
+		Id:   req.Id,
+	}
+	l, err := h.libraries.Update(spanCtx, owner, r.PathValue("id"), func(l *librarypb.Library) error {
+		return library.AddItem(l, item)
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to add library item", err)
+		return
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, l)
+}
+
+func validateAddLibraryItemRequest(req *librarypb.AddLibraryItemV1Request) error {
+	if req.GetId() == "" {
+		return ErrMissingItemId
+	}
+	if _, known := collectionpb.ItemType_name[int32(req.GetType())]; !known {
+		return ErrUnknownItemType
+	}
+	return nil
+}
+
+// validateItem checks the item refers to content or a collection of the owner.
+func (h *librariesV1Handler) validateItem(ctx context.Context, owner string, typ collectionpb.ItemType, itemId string) error {
+	if typ == collectionpb.ItemType_CONTENT {
+		_, err := h.index.Get(ctx, owner, &contentpb.ContentId{Value: &itemId})
+		return err
+	}
+	if h.collections == nil {
+		return collection.ErrNotFound
+	}
+	_, err := h.collections.Get(ctx, owner, itemId)
+	return err
+}
+
+func (h *librariesV1Handler) removeItem(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "librariesV1Handler.removeItem")
+	defer span.End()
+
+	itemId := r.PathValue("item_id")
+	_, err := h.libraries.Update(spanCtx, callerFromContext(spanCtx), r.PathValue("id"), func(l *librarypb.Library) error {
+		return library.RemoveItem(l, itemId)
+	})
+	if err != nil {
+		span.RecordError(err)
+		h.writeError(spanCtx, w, "failed to remove library item", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *librariesV1Handler) readRequest(r *http.Request, m proto.Message) error {
+	if r.Header.Get("Content-Type") != rest.ProtobufContentType {
+		return ErrUnsupportedContentType
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxMetadataSize+1))
+	if err != nil {
+		return err
+	}
+	if len(b) > maxMetadataSize {
+		return ErrRequestTooLarge
+	}
+	return h.protoUnmarshal(b, m)
+}
+
+// writeError responds with the humuspb.Status for an error returned while
+// looking up or updating a library, or an item being added to one.
+func (h *librariesV1Handler) writeError(ctx context.Context, w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, library.ErrNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, err.Error())
+	case errors.Is(err, collection.ErrNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, err.Error())
+	case errors.Is(err, index.ErrNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "content not found")
+	case errors.Is(err, library.ErrItemNotFound):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, err.Error())
+	case errors.Is(err, library.ErrItemExists):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_ALREADY_EXISTS, err.Error())
+	default:
+		h.log.ErrorContext(ctx, msg, slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, msg)
+	}
+}
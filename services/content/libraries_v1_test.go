@@ -0,0 +1,353 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	collectionmemory "github.com/z5labs/griot/services/content/collection/memory"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/index/indextest"
+	"github.com/z5labs/griot/services/content/index/memory"
+	librarymemory "github.com/z5labs/griot/services/content/library/memory"
+	"github.com/z5labs/griot/services/content/librarypb"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func libraryItemIds(l *librarypb.Library) []string {
+	var ids []string
+	for _, item := range l.GetItems() {
+		ids = append(ids, item.GetId())
+	}
+	return ids
+}
+
+func TestLibrariesV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if libraries are not enabled", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.ListLibraries(context.Background(), &ListLibrariesRequest{})
+			if !assertStatusCode(t, humuspb.Code_UNIMPLEMENTED, err) {
+				return
+			}
+		})
+
+		t.Run("if the library name is missing", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{})
+			if !assertStatusCode(t, humuspb.Code_INVALID_ARGUMENT, err) {
+				return
+			}
+		})
+
+		t.Run("if a library with the same name already exists", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assertStatusCode(t, humuspb.Code_ALREADY_EXISTS, err) {
+				return
+			}
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, `a library named "Anime" already exists`, status.GetMessage()) {
+				return
+			}
+		})
+
+		t.Run("if the library does not exist", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.GetLibrary(context.Background(), &GetLibraryRequest{
+				Id: "0123456789abcdef",
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+
+		t.Run("if the library belongs to another user", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			bob := NewClient(http.DefaultClient, srv.URL, Credentials(mintToken(t, tokens, "bob", tokenpb.Scope_UPLOAD)))
+			alice := NewClient(http.DefaultClient, srv.URL, Credentials(mintToken(t, tokens, "alice", tokenpb.Scope_UPLOAD)))
+
+			l, err := bob.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = alice.GetLibrary(context.Background(), &GetLibraryRequest{
+				Id: l.GetId(),
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+
+			listResp, err := alice.ListLibraries(context.Background(), &ListLibrariesRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, listResp.Libraries) {
+				return
+			}
+		})
+
+		t.Run("if the content item is not indexed", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			l, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AddLibraryItem(context.Background(), &AddLibraryItemRequest{
+				LibraryId: l.GetId(),
+				Type:      collectionpb.ItemType_CONTENT,
+				ItemId:    indextest.NewRecord("hello", "text", "plain").GetContentId().GetValue(),
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+
+		t.Run("if the collection item does not exist", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New()), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			l, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AddLibraryItem(context.Background(), &AddLibraryItemRequest{
+				LibraryId: l.GetId(),
+				Type:      collectionpb.ItemType_COLLECTION,
+				ItemId:    "0123456789abcdef",
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+
+		t.Run("if the library already contains the item", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Collections(collectionmemory.New()), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+				Name: "Naruto",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			l, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			req := &AddLibraryItemRequest{
+				LibraryId: l.GetId(),
+				Type:      collectionpb.ItemType_COLLECTION,
+				ItemId:    col.GetId(),
+			}
+			_, err = c.AddLibraryItem(context.Background(), req)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AddLibraryItem(context.Background(), req)
+			if !assertStatusCode(t, humuspb.Code_ALREADY_EXISTS, err) {
+				return
+			}
+		})
+
+		t.Run("if the removed item is not in the library", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Libraries(librarymemory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			l, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = c.RemoveLibraryItem(context.Background(), &RemoveLibraryItemRequest{
+				LibraryId: l.GetId(),
+				ItemId:    "0123456789abcdef",
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+		})
+	})
+
+	t.Run("will organize the library", func(t *testing.T) {
+		record := indextest.NewRecord("Naruto S01E01", "video", "av1")
+		idx := memory.New()
+		err := idx.Put(context.Background(), record)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		srv := httptest.NewServer(NewServer(nil, idx, Collections(collectionmemory.New()), Libraries(librarymemory.New())))
+		defer srv.Close()
+
+		c := NewClient(http.DefaultClient, srv.URL)
+
+		col, err := c.CreateCollection(context.Background(), &CreateCollectionRequest{
+			Name: "Naruto",
+		})
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		l, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+			Name: "Anime",
+		})
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		t.Run("if items are added", func(t *testing.T) {
+			_, err := c.AddLibraryItem(context.Background(), &AddLibraryItemRequest{
+				LibraryId: l.GetId(),
+				Type:      collectionpb.ItemType_COLLECTION,
+				ItemId:    col.GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			got, err := c.AddLibraryItem(context.Background(), &AddLibraryItemRequest{
+				LibraryId: l.GetId(),
+				Type:      collectionpb.ItemType_CONTENT,
+				ItemId:    record.GetContentId().GetValue(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{col.GetId(), record.GetContentId().GetValue()}, libraryItemIds(got)) {
+				return
+			}
+		})
+
+		t.Run("if it is found by name", func(t *testing.T) {
+			listResp, err := c.ListLibraries(context.Background(), &ListLibrariesRequest{
+				Name: "Anime",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, listResp.Libraries, 1) {
+				return
+			}
+			if !assert.Equal(t, l.GetId(), listResp.Libraries[0].GetId()) {
+				return
+			}
+		})
+
+		t.Run("if an item is removed", func(t *testing.T) {
+			err := c.RemoveLibraryItem(context.Background(), &RemoveLibraryItemRequest{
+				LibraryId: l.GetId(),
+				ItemId:    col.GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			got, err := c.GetLibrary(context.Background(), &GetLibraryRequest{
+				Id: l.GetId(),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{record.GetContentId().GetValue()}, libraryItemIds(got)) {
+				return
+			}
+		})
+
+		t.Run("if another library is created", func(t *testing.T) {
+			_, err := c.CreateLibrary(context.Background(), &CreateLibraryRequest{
+				Name: "Movies",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			listResp, err := c.ListLibraries(context.Background(), &ListLibrariesRequest{})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, listResp.Libraries, 2) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package boltdb implements a persistent library.Store embedded
+// in a single file using bbolt, a pure Go key/value store.
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"time"
+
+	"github.com/z5labs/griot/services/content/library"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	bolt "go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	librariesBucket = []byte("libraries")
+
+	// namesBucket maps the name key of each library to its Library ID.
+	namesBucket = []byte("library_names")
+)
+
+// Store is a library.Store persisted to a bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens, or creates, the library database file at the given path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{librariesBucket, namesBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func putLibrary(tx *bolt.Tx, owner, id string, l *librarypb.Library) error {
+	b, err := proto.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(librariesBucket).Put([]byte(library.Key(owner, id)), b)
+}
+
+func getLibrary(tx *bolt.Tx, owner, id string) (*librarypb.Library, error) {
+	b := tx.Bucket(librariesBucket).Get([]byte(library.Key(owner, id)))
+	if b == nil {
+		return nil, library.ErrNotFound
+	}
+
+	var l librarypb.Library
+	err := proto.Unmarshal(b, &l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (s *Store) Create(ctx context.Context, l *librarypb.Library) error {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Create")
+	defer span.End()
+
+	if l.GetId() == "" {
+		return library.ErrMissingLibraryId
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		names := tx.Bucket(namesBucket)
+		nameKey := []byte(library.NameKey(l.GetOwner(), l.GetName()))
+		if names.Get(nameKey) != nil {
+			return library.ErrNameExists
+		}
+
+		err := names.Put(nameKey, []byte(l.GetId()))
+		if err != nil {
+			return err
+		}
+		return putLibrary(tx, l.GetOwner(), l.GetId(), l)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, owner, id string) (*librarypb.Library, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Get")
+	defer span.End()
+
+	var l *librarypb.Library
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		l, err = getLibrary(tx, owner, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *Store) GetByName(ctx context.Context, owner, name string) (*librarypb.Library, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.GetByName")
+	defer span.End()
+
+	var l *librarypb.Library
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		id := tx.Bucket(namesBucket).Get([]byte(library.NameKey(owner, name)))
+		if id == nil {
+			return library.ErrNotFound
+		}
+		l, err = getLibrary(tx, owner, string(id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// List reads every library of the owner in a single read transaction before
+// yielding any of them so consumers are free to modify the store while iterating.
+func (s *Store) List(ctx context.Context, owner string) iter.Seq2[*librarypb.Library, error] {
+	return func(yield func(*librarypb.Library, error) bool) {
+		prefix := []byte(library.Key(owner, ""))
+
+		var libraries []*librarypb.Library
+		err := s.db.View(func(tx *bolt.Tx) error {
+			cur := tx.Bucket(librariesBucket).Cursor()
+			for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+				var l librarypb.Library
+				err := proto.Unmarshal(v, &l)
+				if err != nil {
+					return err
+				}
+				libraries = append(libraries, &l)
+			}
+			return nil
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, l := range libraries {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(l, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Update(ctx context.Context, owner, id string, update func(*librarypb.Library) error) (*librarypb.Library, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Update")
+	defer span.End()
+
+	var l *librarypb.Library
+	err := s.db.Update(func(tx *bolt.Tx) (err error) {
+		l, err = getLibrary(tx, owner, id)
+		if err != nil {
+			return err
+		}
+		err = update(l)
+		if err != nil {
+			return err
+		}
+		return putLibrary(tx, owner, id, l)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return l, nil
+}
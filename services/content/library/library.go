@@ -0,0 +1,116 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package library defines the Libraries users organize their content into.
+//
+// A Library is a uniquely named set of items, each of which is either a piece
+// of content or a Collection. Names are only unique among the Libraries of the
+// same owner, so different users may each have a Library with the same name.
+package library
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"iter"
+	"slices"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/librarypb"
+)
+
+var (
+	ErrNotFound         = errors.New("library not found")
+	ErrMissingLibraryId = errors.New("library is missing library id")
+	ErrNameExists       = errors.New("a library with the same name already exists")
+	ErrItemNotFound     = errors.New("library item not found")
+	ErrItemExists       = errors.New("library already contains the item")
+)
+
+// Store keeps every Library keyed by its owner and Library ID, as well as by
+// its owner and name.
+type Store interface {
+	// Create stores a new Library, unless its owner already has a Library with
+	// the same name, in which case ErrNameExists is returned.
+	Create(ctx context.Context, l *librarypb.Library) error
+
+	Get(ctx context.Context, owner, id string) (*librarypb.Library, error)
+
+	GetByName(ctx context.Context, owner, name string) (*librarypb.Library, error)
+
+	// List returns all Libraries of the owner ordered by Library ID.
+	List(ctx context.Context, owner string) iter.Seq2[*librarypb.Library, error]
+
+	// Update atomically applies update to the owner's Library and stores the result.
+	// Nothing is stored if update returns an error, which is then returned by Update.
+	// The update must not change the name of the Library.
+	Update(ctx context.Context, owner, id string, update func(*librarypb.Library) error) (*librarypb.Library, error)
+}
+
+// Key returns the key a Store keeps the owner's Library under. The keys of
+// all Libraries of an owner share the prefix returned by Key(owner, "").
+func Key(owner, id string) string {
+	return owner + "\x00" + id
+}
+
+// NameKey returns the key a Store indexes the owner's Library name under.
+func NameKey(owner, name string) string {
+	return owner + "\x00" + name
+}
+
+// Create creates a new, empty Library with the given name for the owner.
+func Create(ctx context.Context, store Store, owner, name string) (*librarypb.Library, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &librarypb.Library{
+		Id:    ptr.Ref(hex.EncodeToString(id)),
+		Name:  ptr.Ref(name),
+		Owner: ptr.Ref(owner),
+	}
+	err = store.Create(ctx, l)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// AddItem adds the item to the Library.
+func AddItem(l *librarypb.Library, item *librarypb.LibraryItem) error {
+	if slices.ContainsFunc(l.Items, hasId(item.GetId())) {
+		return ErrItemExists
+	}
+	l.Items = append(l.Items, item)
+	return nil
+}
+
+// RemoveItem removes the item with the given ID.
+func RemoveItem(l *librarypb.Library, id string) error {
+	i := slices.IndexFunc(l.Items, hasId(id))
+	if i < 0 {
+		return ErrItemNotFound
+	}
+	l.Items = slices.Delete(l.Items, i, i+1)
+	return nil
+}
+
+func hasId(id string) func(*librarypb.LibraryItem) bool {
+	return func(item *librarypb.LibraryItem) bool {
+		return item.GetId() == id
+	}
+}
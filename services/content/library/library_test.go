@@ -0,0 +1,103 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newItem(id string) *librarypb.LibraryItem {
+	return &librarypb.LibraryItem{
+		Type: collectionpb.ItemType_CONTENT.Enum(),
+		Id:   ptr.Ref(id),
+	}
+}
+
+func itemIds(l *librarypb.Library) []string {
+	ids := make([]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		ids = append(ids, item.GetId())
+	}
+	return ids
+}
+
+func TestAddItem(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the library already contains the item", func(t *testing.T) {
+			l := &librarypb.Library{}
+			err := AddItem(l, newItem("a"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = AddItem(l, newItem("a"))
+			if !assert.ErrorIs(t, err, ErrItemExists) {
+				return
+			}
+		})
+	})
+
+	t.Run("will add the item", func(t *testing.T) {
+		t.Run("if the library does not contain it", func(t *testing.T) {
+			l := &librarypb.Library{}
+			for _, id := range []string{"a", "b"} {
+				err := AddItem(l, newItem(id))
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+			if !assert.Equal(t, []string{"a", "b"}, itemIds(l)) {
+				return
+			}
+		})
+	})
+}
+
+func TestRemoveItem(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the library does not contain the item", func(t *testing.T) {
+			err := RemoveItem(&librarypb.Library{}, "a")
+			if !assert.ErrorIs(t, err, ErrItemNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the item", func(t *testing.T) {
+		t.Run("if the library contains it", func(t *testing.T) {
+			l := &librarypb.Library{}
+			for _, id := range []string{"a", "b", "c"} {
+				err := AddItem(l, newItem(id))
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			err := RemoveItem(l, "b")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"a", "c"}, itemIds(l)) {
+				return
+			}
+		})
+	})
+}
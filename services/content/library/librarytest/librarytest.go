@@ -0,0 +1,286 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package librarytest provides a conformance test suite for library.Store implementations.
+package librarytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/collectionpb"
+	"github.com/z5labs/griot/services/content/library"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func names(t *testing.T, libraries func(yield func(*librarypb.Library, error) bool)) []string {
+	t.Helper()
+
+	var ns []string
+	for l, err := range libraries {
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		ns = append(ns, l.GetName())
+	}
+	return ns
+}
+
+func newItem(id string) *librarypb.LibraryItem {
+	return &librarypb.LibraryItem{
+		Type: collectionpb.ItemType_CONTENT.Enum(),
+		Id:   ptr.Ref(id),
+	}
+}
+
+// Run runs the conformance test suite against the library.Store returned by newStore.
+// A new library.Store is requested for every test.
+func Run(t *testing.T, newStore func(*testing.T) library.Store) {
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the library is missing a library id", func(t *testing.T) {
+				s := newStore(t)
+
+				err := s.Create(ctx, &librarypb.Library{})
+				if !assert.ErrorIs(t, err, library.ErrMissingLibraryId) {
+					return
+				}
+			})
+
+			t.Run("if the owner already has a library with the same name", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := library.Create(ctx, s, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = library.Create(ctx, s, "bob", "Anime")
+				if !assert.ErrorIs(t, err, library.ErrNameExists) {
+					return
+				}
+				if !assert.Equal(t, []string{"Anime"}, names(t, s.List(ctx, "bob"))) {
+					return
+				}
+			})
+		})
+
+		t.Run("will store the library", func(t *testing.T) {
+			t.Run("if another owner has a library with the same name", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := library.Create(ctx, s, "alice", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = library.Create(ctx, s, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the library does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := s.Get(ctx, "bob", "0123456789abcdef")
+				if !assert.ErrorIs(t, err, library.ErrNotFound) {
+					return
+				}
+			})
+
+			t.Run("if the library belongs to another owner", func(t *testing.T) {
+				s := newStore(t)
+
+				l, err := library.Create(ctx, s, "alice", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = s.Get(ctx, "bob", l.GetId())
+				if !assert.ErrorIs(t, err, library.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the library", func(t *testing.T) {
+			t.Run("if it was created", func(t *testing.T) {
+				s := newStore(t)
+
+				l, err := library.Create(ctx, s, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				got, err := s.Get(ctx, "bob", l.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(l, got)) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("GetByName", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the library does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := s.GetByName(ctx, "bob", "Anime")
+				if !assert.ErrorIs(t, err, library.ErrNotFound) {
+					return
+				}
+			})
+
+			t.Run("if the library belongs to another owner", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := library.Create(ctx, s, "alice", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = s.GetByName(ctx, "bob", "Anime")
+				if !assert.ErrorIs(t, err, library.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the library", func(t *testing.T) {
+			t.Run("if it was created", func(t *testing.T) {
+				s := newStore(t)
+
+				l, err := library.Create(ctx, s, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				got, err := s.GetByName(ctx, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(l, got)) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Run("will only return the libraries of the owner", func(t *testing.T) {
+			s := newStore(t)
+
+			for _, l := range []struct{ owner, name string }{
+				{owner: "bob", name: "Anime"},
+				{owner: "bobby", name: "Movies"},
+				{owner: "alice", name: "Music"},
+				{owner: "bob", name: "Documentaries"},
+			} {
+				_, err := library.Create(ctx, s, l.owner, l.name)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			if !assert.ElementsMatch(t, []string{"Anime", "Documentaries"}, names(t, s.List(ctx, "bob"))) {
+				return
+			}
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the library does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := s.Update(ctx, "bob", "0123456789abcdef", func(l *librarypb.Library) error {
+					return nil
+				})
+				if !assert.ErrorIs(t, err, library.ErrNotFound) {
+					return
+				}
+			})
+
+			t.Run("if the update fails", func(t *testing.T) {
+				s := newStore(t)
+
+				l, err := library.Create(ctx, s, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				updateErr := errors.New("failed to update")
+				_, err = s.Update(ctx, "bob", l.GetId(), func(l *librarypb.Library) error {
+					l.Items = append(l.Items, newItem("a"))
+					return updateErr
+				})
+				if !assert.ErrorIs(t, err, updateErr) {
+					return
+				}
+
+				got, err := s.Get(ctx, "bob", l.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Empty(t, got.GetItems()) {
+					return
+				}
+			})
+		})
+
+		t.Run("will store the updated library", func(t *testing.T) {
+			t.Run("if the update succeeds", func(t *testing.T) {
+				s := newStore(t)
+
+				l, err := library.Create(ctx, s, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				updated, err := s.Update(ctx, "bob", l.GetId(), func(l *librarypb.Library) error {
+					return library.AddItem(l, newItem("a"))
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Len(t, updated.GetItems(), 1) {
+					return
+				}
+
+				got, err := s.GetByName(ctx, "bob", "Anime")
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.True(t, proto.Equal(updated, got)) {
+					return
+				}
+			})
+		})
+	})
+}
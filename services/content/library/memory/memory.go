@@ -0,0 +1,139 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements an in-memory library.Store.
+package memory
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/z5labs/griot/services/content/library"
+	"github.com/z5labs/griot/services/content/librarypb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Store is a library.Store which keeps all libraries in memory.
+// It's primarily intended for testing and ephemeral deployments.
+type Store struct {
+	mu        sync.RWMutex
+	libraries map[string]*librarypb.Library
+
+	// names maps the name key of each library to its key in libraries.
+	names map[string]string
+}
+
+func New() *Store {
+	return &Store{
+		libraries: make(map[string]*librarypb.Library),
+		names:     make(map[string]string),
+	}
+}
+
+func (s *Store) Create(ctx context.Context, l *librarypb.Library) error {
+	if l.GetId() == "" {
+		return library.ErrMissingLibraryId
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nameKey := library.NameKey(l.GetOwner(), l.GetName())
+	if _, exists := s.names[nameKey]; exists {
+		return library.ErrNameExists
+	}
+
+	key := library.Key(l.GetOwner(), l.GetId())
+	s.libraries[key] = proto.Clone(l).(*librarypb.Library)
+	s.names[nameKey] = key
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, owner, id string) (*librarypb.Library, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(library.Key(owner, id))
+}
+
+func (s *Store) GetByName(ctx context.Context, owner, name string) (*librarypb.Library, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, exists := s.names[library.NameKey(owner, name)]
+	if !exists {
+		return nil, library.ErrNotFound
+	}
+	return s.get(key)
+}
+
+func (s *Store) get(key string) (*librarypb.Library, error) {
+	l, exists := s.libraries[key]
+	if !exists {
+		return nil, library.ErrNotFound
+	}
+	return proto.Clone(l).(*librarypb.Library), nil
+}
+
+// List snapshots the libraries before yielding any of them
+// so consumers are free to modify the store while iterating.
+func (s *Store) List(ctx context.Context, owner string) iter.Seq2[*librarypb.Library, error] {
+	return func(yield func(*librarypb.Library, error) bool) {
+		prefix := library.Key(owner, "")
+
+		s.mu.RLock()
+		var libraries []*librarypb.Library
+		for _, key := range slices.Sorted(maps.Keys(s.libraries)) {
+			if strings.HasPrefix(key, prefix) {
+				libraries = append(libraries, proto.Clone(s.libraries[key]).(*librarypb.Library))
+			}
+		}
+		s.mu.RUnlock()
+
+		for _, l := range libraries {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(l, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Update(ctx context.Context, owner, id string, update func(*librarypb.Library) error) (*librarypb.Library, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := library.Key(owner, id)
+	l, exists := s.libraries[key]
+	if !exists {
+		return nil, library.ErrNotFound
+	}
+
+	l = proto.Clone(l).(*librarypb.Library)
+	err := update(l)
+	if err != nil {
+		return nil, err
+	}
+	s.libraries[key] = l
+	return proto.Clone(l).(*librarypb.Library), nil
+}
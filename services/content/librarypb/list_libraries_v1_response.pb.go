@@ -0,0 +1,138 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: list_libraries_v1_response.proto
+
+package librarypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListLibrariesV1Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Libraries []*Library `protobuf:"bytes,1,rep,name=libraries" json:"libraries,omitempty"`
+}
+
+func (x *ListLibrariesV1Response) Reset() {
+	*x = ListLibrariesV1Response{}
+	mi := &file_list_libraries_v1_response_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLibrariesV1Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLibrariesV1Response) ProtoMessage() {}
+
+func (x *ListLibrariesV1Response) ProtoReflect() protoreflect.Message {
+	mi := &file_list_libraries_v1_response_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLibrariesV1Response.ProtoReflect.Descriptor instead.
+func (*ListLibrariesV1Response) Descriptor() ([]byte, []int) {
+	return file_list_libraries_v1_response_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListLibrariesV1Response) GetLibraries() []*Library {
+	if x != nil {
+		return x.Libraries
+	}
+	return nil
+}
+
+var File_list_libraries_v1_response_proto protoreflect.FileDescriptor
+
+var file_list_libraries_v1_response_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73,
+	0x5f, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x15, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x1a, 0x0d, 0x6c, 0x69, 0x62, 0x72, 0x61,
+	0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x57, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74,
+	0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73, 0x56, 0x31, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x2e, 0x4c,
+	0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x52, 0x09, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65,
+	0x73, 0x42, 0x3e, 0x5a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x7a, 0x35, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x6c, 0x69,
+	0x62, 0x72, 0x61, 0x72, 0x79, 0x70, 0x62, 0x3b, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x70,
+	0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
+}
+
+var (
+	file_list_libraries_v1_response_proto_rawDescOnce sync.Once
+	file_list_libraries_v1_response_proto_rawDescData = file_list_libraries_v1_response_proto_rawDesc
+)
+
+func file_list_libraries_v1_response_proto_rawDescGZIP() []byte {
+	file_list_libraries_v1_response_proto_rawDescOnce.Do(func() {
+		file_list_libraries_v1_response_proto_rawDescData = protoimpl.X.CompressGZIP(file_list_libraries_v1_response_proto_rawDescData)
+	})
+	return file_list_libraries_v1_response_proto_rawDescData
+}
+
+var file_list_libraries_v1_response_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_list_libraries_v1_response_proto_goTypes = []any{
+	(*ListLibrariesV1Response)(nil), // 0: griot.content.library.ListLibrariesV1Response
+	(*Library)(nil),                 // 1: griot.content.library.Library
+}
+var file_list_libraries_v1_response_proto_depIdxs = []int32{
+	1, // 0: griot.content.library.ListLibrariesV1Response.libraries:type_name -> griot.content.library.Library
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_list_libraries_v1_response_proto_init() }
+func file_list_libraries_v1_response_proto_init() {
+	if File_list_libraries_v1_response_proto != nil {
+		return
+	}
+	file_library_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_list_libraries_v1_response_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_list_libraries_v1_response_proto_goTypes,
+		DependencyIndexes: file_list_libraries_v1_response_proto_depIdxs,
+		MessageInfos:      file_list_libraries_v1_response_proto_msgTypes,
+	}.Build()
+	File_list_libraries_v1_response_proto = out.File
+	file_list_libraries_v1_response_proto_rawDesc = nil
+	file_list_libraries_v1_response_proto_goTypes = nil
+	file_list_libraries_v1_response_proto_depIdxs = nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/indexpb"
+
+	"github.com/z5labs/humus/humuspb"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultListPageSize = 100
+	MaxListPageSize     = 1000
+)
+
+var (
+	ErrInvalidPageSize  = errors.New("page size must be a positive integer")
+	ErrInvalidPageToken = errors.New("page token is not valid")
+)
+
+type listContentV1Handler struct {
+	log          *slog.Logger
+	index        index.Index
+	protoMarshal func(proto.Message) ([]byte, error)
+}
+
+func (h *listContentV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "listContentV1Handler.ServeHTTP")
+	defer span.End()
+
+	query := r.URL.Query()
+	typ, subtype, _ := strings.Cut(query.Get("media_type"), "/")
+	filter := index.Filter{
+		MediaType:    typ,
+		MediaSubtype: subtype,
+		NamePrefix:   query.Get("name_prefix"),
+	}
+
+	pageSize, err := parsePageSize(query.Get("page_size"))
+	if err != nil {
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	// the page token is the ListKey of the last record in the previous page
+	after, err := base64.RawURLEncoding.DecodeString(query.Get("page_token"))
+	if err != nil {
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, ErrInvalidPageToken.Error())
+		return
+	}
+
+	// one extra record is read to know if there's another page
+	records := make([]*indexpb.Record, 0, pageSize+1)
+	for record, err := range h.index.List(spanCtx, callerFromContext(spanCtx), filter, after) {
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list index records", slog.String("error", err.Error()))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to list content")
+			return
+		}
+		records = append(records, record)
+		if len(records) > pageSize {
+			break
+		}
+	}
+
+	resp := &indexpb.ListContentV1Response{}
+	if len(records) > pageSize {
+		records = records[:pageSize]
+		token := base64.RawURLEncoding.EncodeToString(filter.ListKey(records[pageSize-1]))
+		resp.NextPageToken = &token
+	}
+	resp.Records = records
+
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, resp)
+}
+
+func parsePageSize(s string) (int, error) {
+	if s == "" {
+		return DefaultListPageSize, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidPageSize
+	}
+	return min(n, MaxListPageSize), nil
+}
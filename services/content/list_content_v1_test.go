@@ -0,0 +1,212 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/index/indextest"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/indexpb"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func listNames(t *testing.T, records iter.Seq2[*indexpb.Record, error]) ([]string, error) {
+	t.Helper()
+
+	var names []string
+	for record, err := range records {
+		if err != nil {
+			return names, err
+		}
+		names = append(names, record.GetContentName())
+	}
+	return names, nil
+}
+
+func TestListContentV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the page size is not a positive integer", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/v1/content?page_size=-1")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the page token is not valid", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/v1/content?page_token=***")
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to list the index records", func(t *testing.T) {
+			idx := indexStub{
+				list: func(ctx context.Context, owner string, f index.Filter, b []byte) iter.Seq2[*indexpb.Record, error] {
+					return func(yield func(*indexpb.Record, error) bool) {
+						yield(nil, errors.New("failed to list"))
+					}
+				},
+			}
+
+			srv := httptest.NewServer(NewServer(nil, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return every matching record", func(t *testing.T) {
+		idx := memory.New()
+		for _, record := range []*indexpb.Record{
+			indextest.NewRecord("Naruto S01E01", "video", "av1"),
+			indextest.NewRecord("Naruto S01E02", "video", "av1"),
+			indextest.NewRecord("Naruto S01E03", "video", "mp4"),
+			indextest.NewRecord("Naruto OST", "audio", "flac"),
+			indextest.NewRecord("Bleach S01E01", "video", "av1"),
+		} {
+			err := idx.Put(context.Background(), record)
+			if !assert.Nil(t, err) {
+				return
+			}
+		}
+
+		srv := httptest.NewServer(NewServer(nil, idx))
+		defer srv.Close()
+
+		c := NewClient(http.DefaultClient, srv.URL)
+
+		t.Run("if no filters are provided", func(t *testing.T) {
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, names, 5) {
+				return
+			}
+		})
+
+		t.Run("if the media type and name prefix are provided", func(t *testing.T) {
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{
+				MediaType:  "video",
+				NamePrefix: "Naruto",
+			}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.ElementsMatch(t, []string{"Naruto S01E01", "Naruto S01E02", "Naruto S01E03"}, names) {
+				return
+			}
+		})
+
+		t.Run("if the media type includes a subtype", func(t *testing.T) {
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{
+				MediaType: "video/av1",
+			}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.ElementsMatch(t, []string{"Naruto S01E01", "Naruto S01E02", "Bleach S01E01"}, names) {
+				return
+			}
+		})
+
+		t.Run("if the records span multiple pages", func(t *testing.T) {
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{
+				NamePrefix: "Naruto",
+				PageSize:   1,
+			}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"Naruto OST", "Naruto S01E01", "Naruto S01E02", "Naruto S01E03"}, names) {
+				return
+			}
+		})
+	})
+	t.Run("will only return the records of the caller", func(t *testing.T) {
+		t.Run("if authentication is enabled", func(t *testing.T) {
+			idx := memory.New()
+			for _, record := range []*indexpb.Record{
+				indextest.Owned(indextest.NewRecord("Naruto S01E01", "video", "av1"), "bob"),
+				indextest.Owned(indextest.NewRecord("Naruto S01E01", "video", "av1"), "alice"),
+				indextest.Owned(indextest.NewRecord("Bleach S01E01", "video", "av1"), "alice"),
+			} {
+				err := idx.Put(context.Background(), record)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_READ)
+
+			srv := httptest.NewServer(NewServer(nil, idx, Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			names, err := listNames(t, c.ListContent(context.Background(), &ListContentRequest{}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []string{"Naruto S01E01"}, names) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"github.com/z5labs/griot/services/content/contentpb"
+)
+
+// formatMediaType formats the given media type for use in a Content-Type header.
+func formatMediaType(mediaType *contentpb.MediaType) string {
+	formatted := contentpb.FormatMediaType(mediaType)
+	if formatted == "" {
+		return "application/octet-stream"
+	}
+	return formatted
+}
+
+// normalizeMediaType re-parses the uploaded media type so every field is filled,
+// even if the client put the whole media type in the type field.
+func normalizeMediaType(meta *contentpb.Metadata) error {
+	if meta.GetMediaType().GetType() == "" {
+		return nil
+	}
+
+	mediaType, err := contentpb.ParseMediaType(contentpb.FormatMediaType(meta.MediaType))
+	if err != nil {
+		return err
+	}
+	meta.MediaType = mediaType
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/z5labs/humus/humuspb"
+)
+
+// RetryPolicy configures how the Client retries requests which failed with
+// a transient error. Only requests which are safe to repeat are retried, e.g.
+// content-addressed uploads whose content can be re-opened.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of times a request is sent,
+	// including the first time. Less than 2 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the max time to wait before the first retry. It's
+	// doubled for every following retry, up to MaxBackoff. The actual time
+	// waited is randomly chosen between 0 and the backoff. Negative backoffs
+	// are treated as 0.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// RetryableCodes are the humuspb.Status codes which are retried. Errors
+	// sending the request are only retried if they're transient, i.e. timeouts
+	// and connections which were refused, reset or unexpectedly closed.
+	RetryableCodes []humuspb.Code
+}
+
+// DefaultRetryPolicy is used by the Client unless the Retry ClientOption is given.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		RetryableCodes: []humuspb.Code{
+			humuspb.Code_DEADLINE_EXCEEDED,
+			humuspb.Code_RESOURCE_EXHAUSTED,
+			humuspb.Code_UNAVAILABLE,
+		},
+	}
+}
+
+// gatewayCodes maps the status codes of responses from proxies, which don't
+// contain a humuspb.Status, to the humuspb.Status code they're equivalent to.
+var gatewayCodes = map[int]humuspb.Code{
+	http.StatusTooManyRequests:    humuspb.Code_RESOURCE_EXHAUSTED,
+	http.StatusBadGateway:         humuspb.Code_UNAVAILABLE,
+	http.StatusServiceUnavailable: humuspb.Code_UNAVAILABLE,
+	http.StatusGatewayTimeout:     humuspb.Code_DEADLINE_EXCEEDED,
+}
+
+func (p RetryPolicy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var status *humuspb.Status
+	if errors.As(err, &status) {
+		return slices.Contains(p.RetryableCodes, status.GetCode())
+	}
+
+	var ctErr UnsupportedResponseContentTypeError
+	if errors.As(err, &ctErr) {
+		code, ok := gatewayCodes[ctErr.StatusCode]
+		return ok && slices.Contains(p.RetryableCodes, code)
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	return transient(urlErr.Err)
+}
+
+// transient reports whether an error sending a request may not happen again,
+// unlike e.g. an invalid URL, an untrusted certificate or an unknown host.
+func transient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// net/http reports a connection closed by the server before
+	// it responded as a plain io.EOF rather than an unexpected one
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// do calls f until it succeeds, fails with an error which isn't
+// retryable or the max attempts have been made.
+func (p RetryPolicy) do(ctx context.Context, f func(context.Context) error) error {
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := f(ctx)
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(ctx, err) {
+			return err
+		}
+
+		timer := time.NewTimer(rand.N(max(backoff, 0) + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, p.MaxBackoff)
+	}
+}
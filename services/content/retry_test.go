@@ -0,0 +1,329 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"google.golang.org/protobuf/proto"
+)
+
+// failFirst fails the first n requests matching the method with the
+// given status before passing requests on to the next handler.
+func failFirst(n int32, method string, code humuspb.Code, next http.Handler) http.Handler {
+	var failed atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method || failed.Add(1) > n {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// simulate the server failing part way through receiving the content
+		io.CopyN(io.Discard, r.Body, 3)
+
+		b, err := proto.Marshal(&humuspb.Status{
+			Code: code.Enum(),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", rest.ProtobufContentType)
+		w.WriteHeader(httpStatusCode(code))
+		io.Copy(w, bytes.NewReader(b))
+	})
+}
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = time.Millisecond
+	return policy
+}
+
+func openString(opened *int, s string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		*opened++
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+func TestClient_UploadContent_Retry(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content can not be re-opened", func(t *testing.T) {
+			srv := httptest.NewServer(failFirst(1, http.MethodPost, humuspb.Code_UNAVAILABLE, NewServer(storageStub{}, memory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Retry(testRetryPolicy()))
+
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				Content: strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNAVAILABLE, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the status code is not retryable", func(t *testing.T) {
+			srv := httptest.NewServer(failFirst(1, http.MethodPost, humuspb.Code_INTERNAL, NewServer(storageStub{}, memory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Retry(testRetryPolicy()))
+
+			var opened int
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+			if !assert.Equal(t, 1, opened) {
+				return
+			}
+		})
+
+		t.Run("if sending the request fails with an error which is not transient", func(t *testing.T) {
+			// the client doesn't trust the test server's self-signed certificate
+			srv := httptest.NewTLSServer(NewServer(storageStub{}, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Retry(testRetryPolicy()))
+
+			var opened int
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+			if !assert.NotNil(t, err) {
+				return
+			}
+			if !assert.Equal(t, 1, opened) {
+				return
+			}
+		})
+
+		t.Run("if every attempt is refused a connection", func(t *testing.T) {
+			ls, err := net.Listen("tcp", "127.0.0.1:0")
+			if !assert.Nil(t, err) {
+				return
+			}
+			addr := ls.Addr().String()
+			ls.Close()
+
+			policy := testRetryPolicy()
+			c := NewClient(http.DefaultClient, "http://"+addr, Retry(policy))
+
+			var opened int
+			_, err = c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+			if !assert.NotNil(t, err) {
+				return
+			}
+			if !assert.Equal(t, policy.MaxAttempts, opened) {
+				return
+			}
+		})
+
+		t.Run("if every attempt fails", func(t *testing.T) {
+			srv := httptest.NewServer(failFirst(10, http.MethodPost, humuspb.Code_UNAVAILABLE, NewServer(storageStub{}, memory.New())))
+			defer srv.Close()
+
+			policy := testRetryPolicy()
+			c := NewClient(http.DefaultClient, srv.URL, Retry(policy))
+
+			var opened int
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, policy.MaxAttempts, opened) {
+				return
+			}
+		})
+	})
+
+	t.Run("will store the content", func(t *testing.T) {
+		t.Run("if an attempt fails with a retryable status code", func(t *testing.T) {
+			var stored bytes.Buffer
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+
+			srv := httptest.NewServer(failFirst(2, http.MethodPost, humuspb.Code_UNAVAILABLE, NewServer(storageStub{put: store}, memory.New())))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Retry(testRetryPolicy()))
+
+			var opened int
+			checksum := sha256Checksum([]byte("hello world"))
+			resp, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: checksum,
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, NewContentId(checksum).GetValue(), resp.Id) {
+				return
+			}
+			if !assert.Equal(t, 3, opened) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+		})
+
+		t.Run("if the backoff is negative", func(t *testing.T) {
+			srv := httptest.NewServer(failFirst(1, http.MethodPost, humuspb.Code_UNAVAILABLE, NewServer(storageStub{put: storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(io.Discard, r)
+				return err
+			})}, memory.New())))
+			defer srv.Close()
+
+			policy := testRetryPolicy()
+			policy.InitialBackoff = -time.Second
+			policy.MaxBackoff = -time.Second
+			c := NewClient(http.DefaultClient, srv.URL, Retry(policy))
+
+			var opened int
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, 2, opened) {
+				return
+			}
+		})
+
+		t.Run("if a proxy responds with a bad gateway", func(t *testing.T) {
+			var failed atomic.Bool
+			next := NewServer(storageStub{put: storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(io.Discard, r)
+				return err
+			})}, memory.New())
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if failed.CompareAndSwap(false, true) {
+					http.Error(w, "bad gateway", http.StatusBadGateway)
+					return
+				}
+				next.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Retry(testRetryPolicy()))
+
+			var opened int
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				OpenContent: openString(&opened, "hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, 2, opened) {
+				return
+			}
+		})
+	})
+}
+
+func TestClient_ResumableUploadContent_Retry(t *testing.T) {
+	t.Run("will store the content", func(t *testing.T) {
+		t.Run("if appending a chunk fails with a retryable status code", func(t *testing.T) {
+			var stored bytes.Buffer
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+
+			next := NewServer(storageStub{put: store}, memory.New(), UploadSessions(newSessionStore(t)))
+			srv := httptest.NewServer(failFirst(1, http.MethodPatch, humuspb.Code_UNAVAILABLE, next))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Retry(testRetryPolicy()))
+
+			_, err := c.ResumableUploadContent(context.Background(), &ResumableUploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+				Content:   strings.NewReader("hello world"),
+				ChunkSize: 4,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,332 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/collection"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/library"
+	"github.com/z5labs/griot/services/content/session"
+	"github.com/z5labs/griot/services/content/storage"
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/z5labs/humus"
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server implements the Content Service REST API as an http.Handler.
+// See the package doc for why it's built on an http.ServeMux.
+type Server struct {
+	mux *http.ServeMux
+}
+
+type serverOptions struct {
+	sessions    session.Store
+	spoolDir    string
+	maxSpooled  int64
+	tokens      token.Store
+	bootstrap   *bootstrapToken
+	collections collection.Store
+	libraries   library.Store
+}
+
+type ServerOption func(*serverOptions)
+
+// UploadSessions enables the resumable upload APIs which stage content in the given session.Store.
+func UploadSessions(sessions session.Store) ServerOption {
+	return func(so *serverOptions) {
+		so.sessions = sessions
+	}
+}
+
+// SpoolDir sets where content uploaded with a trailing checksum is temporarily
+// written while it's verified. By default, the OS temp directory is used.
+func SpoolDir(dir string) ServerOption {
+	return func(so *serverOptions) {
+		so.spoolDir = dir
+	}
+}
+
+// MaxSpoolSize limits how many bytes of content uploaded with a trailing checksum are
+// temporarily written to the spool directory. Larger uploads are rejected with HTTP 413.
+// By default, the limit is 1 GiB.
+func MaxSpoolSize(n int64) ServerOption {
+	return func(so *serverOptions) {
+		so.maxSpooled = n
+	}
+}
+
+// Authentication requires every request to carry a bearer token minted into the given
+// token.Store and enables the admin APIs for minting and revoking tokens. Reading content
+// requires the READ scope, uploading or deleting your own content requires the UPLOAD scope
+// and managing tokens requires the ADMIN scope.
+func Authentication(tokens token.Store) ServerOption {
+	return func(so *serverOptions) {
+		so.tokens = tokens
+	}
+}
+
+// BootstrapAdmin accepts the given secret as an ADMIN token for the given user in addition
+// to the tokens in the token.Store, so the first tokens of a new deployment can be minted
+// with it. The secret should be long and random, e.g. from a secrets manager, since it
+// isn't stored in the token.Store and so can't be revoked. Instead, the option should be
+// removed once real ADMIN tokens have been minted. It has no effect without Authentication.
+func BootstrapAdmin(user, secret string) ServerOption {
+	return func(so *serverOptions) {
+		if secret == "" {
+			return
+		}
+		so.bootstrap = &bootstrapToken{
+			secretHash: sha256.Sum256([]byte(secret)),
+			token: &tokenpb.Token{
+				Name:  ptr.Ref("bootstrap"),
+				Scope: tokenpb.Scope_ADMIN.Enum(),
+				User:  ptr.Ref(user),
+			},
+		}
+	}
+}
+
+// Collections enables the APIs for curating content into Collections kept in the given collection.Store.
+// Viewing collections requires the READ scope and modifying them requires the UPLOAD scope.
+func Collections(collections collection.Store) ServerOption {
+	return func(so *serverOptions) {
+		so.collections = collections
+	}
+}
+
+// Libraries enables the APIs for organizing content and Collections into Libraries kept in the
+// given library.Store. Viewing libraries requires the READ scope and modifying them requires the
+// UPLOAD scope. Collections can only be added to a Library if Collections are enabled as well.
+func Libraries(libraries library.Store) ServerOption {
+	return func(so *serverOptions) {
+		so.libraries = libraries
+	}
+}
+
+func NewServer(store storage.Storage, idx index.Index, opts ...ServerOption) *Server {
+	so := &serverOptions{
+		maxSpooled: 1 << 30,
+	}
+	for _, opt := range opts {
+		opt(so)
+	}
+
+	log := humus.Logger("content")
+	// content is locked by its Content ID so it can't be stored while it's being deleted
+	contentLocks := &keyedLocks{}
+
+	// without a token.Store every request is allowed
+	authorize := func(scope tokenpb.Scope, h http.Handler) http.Handler {
+		if so.tokens == nil {
+			return h
+		}
+		return &authenticator{
+			log:          log,
+			tokens:       so.tokens,
+			bootstrap:    so.bootstrap,
+			scope:        scope,
+			next:         h,
+			protoMarshal: proto.Marshal,
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /v1/content", authorize(tokenpb.Scope_UPLOAD, &uploadContentV1Handler{
+		log:            log,
+		store:          store,
+		index:          idx,
+		locks:          contentLocks,
+		protoMarshal:   proto.Marshal,
+		protoUnmarshal: proto.Unmarshal,
+		spoolDir:       so.spoolDir,
+		maxSpooled:     so.maxSpooled,
+	}))
+	mux.Handle("GET /v1/content", authorize(tokenpb.Scope_READ, &listContentV1Handler{
+		log:          log,
+		index:        idx,
+		protoMarshal: proto.Marshal,
+	}))
+	mux.Handle("GET /v1/content/{id}", authorize(tokenpb.Scope_READ, &downloadContentV1Handler{
+		log:          log,
+		store:        store,
+		index:        idx,
+		protoMarshal: proto.Marshal,
+	}))
+
+	mux.Handle("DELETE /v1/content/{id}", authorize(tokenpb.Scope_UPLOAD, &deleteContentV1Handler{
+		log:          log,
+		store:        store,
+		index:        idx,
+		locks:        contentLocks,
+		protoMarshal: proto.Marshal,
+	}))
+	// GET patterns also match HEAD requests
+	mux.Handle("GET /v1/content/{id}/metadata", authorize(tokenpb.Scope_READ, &getContentMetadataV1Handler{
+		log:          log,
+		index:        idx,
+		protoMarshal: proto.Marshal,
+	}))
+	mux.Handle("GET /v1/checksums/{checksum}", authorize(tokenpb.Scope_READ, &findByChecksumV1Handler{
+		log:          log,
+		index:        idx,
+		protoMarshal: proto.Marshal,
+	}))
+
+	uploads := &uploadSessionV1Handler{
+		log:            log,
+		store:          store,
+		index:          idx,
+		locks:          contentLocks,
+		sessionLocks:   &keyedLocks{},
+		sessions:       so.sessions,
+		protoMarshal:   proto.Marshal,
+		protoUnmarshal: proto.Unmarshal,
+	}
+	// resumable uploads require somewhere to stage content until it's complete
+	sessionHandler := func(h http.HandlerFunc) http.Handler {
+		if so.sessions == nil {
+			return unimplemented(log, proto.Marshal, "resumable uploads are not enabled")
+		}
+		return authorize(tokenpb.Scope_UPLOAD, h)
+	}
+	mux.Handle("POST /v1/uploads", sessionHandler(uploads.create))
+	mux.Handle("GET /v1/uploads/{id}", sessionHandler(uploads.get))
+	mux.Handle("PATCH /v1/uploads/{id}", sessionHandler(uploads.append))
+	mux.Handle("POST /v1/uploads/{id}/complete", sessionHandler(uploads.complete))
+	mux.Handle("DELETE /v1/uploads/{id}", sessionHandler(uploads.abort))
+
+	tokens := &tokensV1Handler{
+		log:            log,
+		tokens:         so.tokens,
+		protoMarshal:   proto.Marshal,
+		protoUnmarshal: proto.Unmarshal,
+	}
+	tokenHandler := func(h http.HandlerFunc) http.Handler {
+		if so.tokens == nil {
+			return unimplemented(log, proto.Marshal, "token authentication is not enabled")
+		}
+		return authorize(tokenpb.Scope_ADMIN, h)
+	}
+	mux.Handle("POST /v1/tokens", tokenHandler(tokens.create))
+	mux.Handle("GET /v1/tokens", tokenHandler(tokens.list))
+	mux.Handle("DELETE /v1/tokens/{id}", tokenHandler(tokens.revoke))
+
+	collections := &collectionsV1Handler{
+		log:            log,
+		collections:    so.collections,
+		index:          idx,
+		owners:         &keyedLocks{},
+		protoMarshal:   proto.Marshal,
+		protoUnmarshal: proto.Unmarshal,
+	}
+	collectionHandler := func(scope tokenpb.Scope, h http.HandlerFunc) http.Handler {
+		if so.collections == nil {
+			return unimplemented(log, proto.Marshal, "collections are not enabled")
+		}
+		return authorize(scope, h)
+	}
+	mux.Handle("POST /v1/collections", collectionHandler(tokenpb.Scope_UPLOAD, collections.create))
+	mux.Handle("GET /v1/collections", collectionHandler(tokenpb.Scope_READ, collections.list))
+	mux.Handle("GET /v1/collections/{id}", collectionHandler(tokenpb.Scope_READ, collections.get))
+	mux.Handle("PATCH /v1/collections/{id}", collectionHandler(tokenpb.Scope_UPLOAD, collections.rename))
+	mux.Handle("POST /v1/collections/{id}/items", collectionHandler(tokenpb.Scope_UPLOAD, collections.addItem))
+	mux.Handle("PATCH /v1/collections/{id}/items/{item_id}", collectionHandler(tokenpb.Scope_UPLOAD, collections.reorderItem))
+	mux.Handle("DELETE /v1/collections/{id}/items/{item_id}", collectionHandler(tokenpb.Scope_UPLOAD, collections.removeItem))
+
+	libraries := &librariesV1Handler{
+		log:            log,
+		libraries:      so.libraries,
+		collections:    so.collections,
+		index:          idx,
+		protoMarshal:   proto.Marshal,
+		protoUnmarshal: proto.Unmarshal,
+	}
+	libraryHandler := func(scope tokenpb.Scope, h http.HandlerFunc) http.Handler {
+		if so.libraries == nil {
+			return unimplemented(log, proto.Marshal, "libraries are not enabled")
+		}
+		return authorize(scope, h)
+	}
+	mux.Handle("POST /v1/libraries", libraryHandler(tokenpb.Scope_UPLOAD, libraries.create))
+	mux.Handle("GET /v1/libraries", libraryHandler(tokenpb.Scope_READ, libraries.list))
+	mux.Handle("GET /v1/libraries/{id}", libraryHandler(tokenpb.Scope_READ, libraries.get))
+	mux.Handle("POST /v1/libraries/{id}/items", libraryHandler(tokenpb.Scope_UPLOAD, libraries.addItem))
+	mux.Handle("DELETE /v1/libraries/{id}/items/{item_id}", libraryHandler(tokenpb.Scope_UPLOAD, libraries.removeItem))
+
+	s := &Server{
+		mux: mux,
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+var httpStatusCodes = map[humuspb.Code]int{
+	humuspb.Code_INVALID_ARGUMENT:  http.StatusBadRequest,
+	humuspb.Code_UNAUTHENTICATED:   http.StatusUnauthorized,
+	humuspb.Code_PERMISSION_DENIED: http.StatusForbidden,
+	humuspb.Code_NOT_FOUND:         http.StatusNotFound,
+	humuspb.Code_ALREADY_EXISTS:    http.StatusConflict,
+	humuspb.Code_ABORTED:           http.StatusConflict,
+	humuspb.Code_OUT_OF_RANGE:      http.StatusRequestedRangeNotSatisfiable,
+	humuspb.Code_INTERNAL:          http.StatusInternalServerError,
+	humuspb.Code_UNIMPLEMENTED:     http.StatusNotImplemented,
+	humuspb.Code_UNAVAILABLE:       http.StatusServiceUnavailable,
+}
+
+func httpStatusCode(code humuspb.Code) int {
+	statusCode, exists := httpStatusCodes[code]
+	if !exists {
+		return http.StatusInternalServerError
+	}
+	return statusCode
+}
+
+func writeProto(log *slog.Logger, w http.ResponseWriter, statusCode int, marshal func(proto.Message) ([]byte, error), m proto.Message) {
+	b, err := marshal(m)
+	if err != nil {
+		log.Error("failed to marshal response", slog.String("error", err.Error()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", rest.ProtobufContentType)
+	w.WriteHeader(statusCode)
+	_, err = io.Copy(w, bytes.NewReader(b))
+	if err != nil {
+		log.Error("failed to write response", slog.String("error", err.Error()))
+	}
+}
+
+func writeStatus(log *slog.Logger, w http.ResponseWriter, marshal func(proto.Message) ([]byte, error), code humuspb.Code, msg string) {
+	status := &humuspb.Status{
+		Code:    code.Enum(),
+		Message: &msg,
+	}
+	writeProto(log, w, httpStatusCode(code), marshal, status)
+}
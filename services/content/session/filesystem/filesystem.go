@@ -0,0 +1,429 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filesystem implements an upload session Store on top of a local directory.
+//
+// Every session is a directory, named by its id, containing the session metadata,
+// its owner and the content committed so far. The committed offset is simply the size of
+// the content file, which is synced after every append.
+//
+// Sessions which haven't been appended to for longer than the TTL are considered
+// abandoned. They're treated as if they don't exist and are removed by RemoveExpired,
+// which is run by New and then periodically by Create.
+package filesystem
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/session"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	idSize       = 16
+	metadataFile = "metadata"
+	ownerFile    = "owner"
+	contentFile  = "content"
+)
+
+type Option func(*Store)
+
+// TTL sets how long a session can go without content being appended to it
+// before it expires. The default is 24 hours.
+func TTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// Store is a session.Store backed by the local filesystem.
+type Store struct {
+	root string
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	locks     map[string]*sessionLock
+	lastSweep time.Time
+}
+
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+// New initializes a Store rooted at the given directory, creating it if needed,
+// and removes any sessions which have already expired.
+func New(root string, opts ...Option) (*Store, error) {
+	err := os.MkdirAll(root, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		root:  root,
+		ttl:   24 * time.Hour,
+		locks: make(map[string]*sessionLock),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	err = s.RemoveExpired(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) dir(id string) (string, error) {
+	// ids are always generated by Create so anything else,
+	// especially path separators, can't refer to a session
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != idSize {
+		return "", session.ErrInvalidId
+	}
+	return filepath.Join(s.root, id), nil
+}
+
+// lock serializes appends to and deletion of a single session. The lock
+// is only kept for as long as it's held or waited on by anyone.
+func (s *Store) lock(id string) func() {
+	s.mu.Lock()
+	l, exists := s.locks[id]
+	if !exists {
+		l = &sessionLock{}
+		s.locks[id] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(s.locks, id)
+		}
+	}
+}
+
+func (s *Store) expired(info fs.FileInfo) bool {
+	return time.Since(info.ModTime()) >= s.ttl
+}
+
+// RemoveExpired removes every session which has expired.
+func (s *Store) RemoveExpired(ctx context.Context) error {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Store.RemoveExpired")
+	defer span.End()
+
+	s.mu.Lock()
+	s.lastSweep = time.Now()
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	for _, entry := range entries {
+		_, err := s.dir(entry.Name())
+		if err != nil {
+			// not a session
+			continue
+		}
+
+		err = s.removeIfExpired(entry.Name())
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) removeIfExpired(id string) error {
+	unlock := s.lock(id)
+	defer unlock()
+
+	dir := filepath.Join(s.root, id)
+	info, err := os.Stat(filepath.Join(dir, contentFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		// it was either removed in the meantime or never
+		// completely created, in which case its age is the directory's
+		info, err = os.Stat(dir)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !s.expired(info) {
+		return nil
+	}
+	return remove(dir)
+}
+
+// sweepDue reports whether it's been at least a TTL since expired sessions were last removed.
+func (s *Store) sweepDue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Since(s.lastSweep) >= s.ttl
+}
+
+func (s *Store) Create(ctx context.Context, owner string, meta *contentpb.Metadata) (*contentpb.UploadSession, error) {
+	spanCtx, span := otel.Tracer("filesystem").Start(ctx, "Store.Create")
+	defer span.End()
+
+	if s.sweepDue() {
+		// abandoned sessions are only removed on a best effort basis
+		// so failing to do so shouldn't fail creating a new one
+		err := s.RemoveExpired(spanCtx)
+		if err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	b := make([]byte, idSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	id := hex.EncodeToString(b)
+	dir := filepath.Join(s.root, id)
+
+	mb, err := proto.Marshal(meta)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	err = os.Mkdir(dir, 0o755)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	err = os.WriteFile(filepath.Join(dir, contentFile), nil, 0o644)
+	if err != nil {
+		span.RecordError(err)
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	err = os.WriteFile(filepath.Join(dir, ownerFile), []byte(owner), 0o644)
+	if err != nil {
+		span.RecordError(err)
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	// the metadata is written last since its existence marks the session as created
+	err = os.WriteFile(filepath.Join(dir, metadataFile), mb, 0o644)
+	if err != nil {
+		span.RecordError(err)
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	sess := &contentpb.UploadSession{
+		Id:       &id,
+		Metadata: meta,
+		Offset:   ptr.Ref(int64(0)),
+		Owner:    &owner,
+	}
+	return sess, nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*contentpb.UploadSession, error) {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Store.Get")
+	defer span.End()
+
+	sess, err := s.get(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return sess, nil
+}
+
+func (s *Store) get(id string) (*contentpb.UploadSession, error) {
+	dir, err := s.dir(id)
+	if err != nil {
+		return nil, err
+	}
+
+	mb, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, session.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var meta contentpb.Metadata
+	err = proto.Unmarshal(mb, &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := os.ReadFile(filepath.Join(dir, ownerFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, session.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filepath.Join(dir, contentFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, session.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.expired(info) {
+		return nil, session.ErrNotFound
+	}
+
+	sess := &contentpb.UploadSession{
+		Id:       &id,
+		Metadata: &meta,
+		Offset:   ptr.Ref(info.Size()),
+		Owner:    ptr.Ref(string(owner)),
+	}
+	return sess, nil
+}
+
+func (s *Store) Append(ctx context.Context, id string, offset int64, r io.Reader) (*contentpb.UploadSession, error) {
+	spanCtx, span := otel.Tracer("filesystem").Start(ctx, "Store.Append")
+	defer span.End()
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	sess, err := s.get(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if sess.GetOffset() != offset {
+		err = session.OffsetMismatchError{
+			Committed: sess.GetOffset(),
+			Requested: offset,
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.root, id, contentFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer f.Close()
+
+	// whatever was copied before a failure is still synced
+	// so that it's committed for the client to resume from
+	n, copyErr := io.Copy(f, storage.ContextReader(spanCtx, r))
+	err = f.Sync()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if copyErr != nil {
+		span.RecordError(copyErr)
+		return nil, copyErr
+	}
+
+	sess.Offset = ptr.Ref(offset + n)
+	return sess, nil
+}
+
+func (s *Store) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Store.Open")
+	defer span.End()
+
+	dir, err := s.dir(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, contentFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, session.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *Store) Delete(ctx context.Context, id string) error {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Store.Delete")
+	defer span.End()
+
+	dir, err := s.dir(id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	_, err = os.Stat(filepath.Join(dir, metadataFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return session.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = remove(dir)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func remove(dir string) error {
+	// the metadata is removed first so a partially removed
+	// session is never mistaken for a valid one
+	err := os.Remove(filepath.Join(dir, metadataFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
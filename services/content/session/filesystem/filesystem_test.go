@@ -0,0 +1,295 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/session"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type readFunc func([]byte) (int, error)
+
+func (f readFunc) Read(b []byte) (int, error) {
+	return f(b)
+}
+
+func newTestSession(t *testing.T) (*Store, *contentpb.UploadSession) {
+	t.Helper()
+
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess, err := s.Create(context.Background(), "bob", &contentpb.Metadata{
+		Name: ptr.Ref("hello"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, sess
+}
+
+// expire makes the session look like it was last appended to long enough ago to have expired.
+func expire(t *testing.T, s *Store, id string) {
+	t.Helper()
+
+	modTime := time.Now().Add(-2 * s.ttl)
+	err := os.Chtimes(filepath.Join(s.root, id, contentFile), modTime, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_Get(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the id is not valid", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.Get(context.Background(), "../hello")
+			if !assert.ErrorIs(t, err, session.ErrInvalidId) {
+				return
+			}
+		})
+
+		t.Run("if the session does not exist", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.Get(context.Background(), strings.Repeat("ab", idSize))
+			if !assert.ErrorIs(t, err, session.ErrNotFound) {
+				return
+			}
+		})
+
+		t.Run("if the session has expired", func(t *testing.T) {
+			s, sess := newTestSession(t)
+			expire(t, s, sess.GetId())
+
+			_, err := s.Get(context.Background(), sess.GetId())
+			if !assert.ErrorIs(t, err, session.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the session", func(t *testing.T) {
+		t.Run("if it was created", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			got, err := s.Get(context.Background(), sess.GetId())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello", got.GetMetadata().GetName()) {
+				return
+			}
+			if !assert.Equal(t, int64(0), got.GetOffset()) {
+				return
+			}
+			if !assert.Equal(t, "bob", got.GetOwner()) {
+				return
+			}
+		})
+	})
+}
+
+func TestStore_Append(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the offset does not match the committed offset", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			_, err := s.Append(context.Background(), sess.GetId(), 5, strings.NewReader("hello"))
+
+			var merr session.OffsetMismatchError
+			if !assert.ErrorAs(t, err, &merr) {
+				return
+			}
+			if !assert.Equal(t, int64(0), merr.Committed) {
+				return
+			}
+			if !assert.Equal(t, int64(5), merr.Requested) {
+				return
+			}
+		})
+	})
+
+	t.Run("will commit the content", func(t *testing.T) {
+		t.Run("if it is appended in multiple chunks", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			sess, err := s.Append(context.Background(), sess.GetId(), 0, strings.NewReader("hello "))
+			if !assert.Nil(t, err) {
+				return
+			}
+			sess, err = s.Append(context.Background(), sess.GetId(), sess.GetOffset(), strings.NewReader("world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), sess.GetOffset()) {
+				return
+			}
+
+			rc, err := s.Open(context.Background(), sess.GetId())
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer rc.Close()
+
+			b, err := io.ReadAll(rc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+		})
+
+		t.Run("if reading the chunk fails part way through", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			readErr := errors.New("connection reset")
+			r := io.MultiReader(strings.NewReader("hello"), readFunc(func(b []byte) (int, error) {
+				return 0, readErr
+			}))
+
+			_, err := s.Append(context.Background(), sess.GetId(), 0, r)
+			if !assert.ErrorIs(t, err, readErr) {
+				return
+			}
+
+			got, err := s.Get(context.Background(), sess.GetId())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello")), got.GetOffset()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will not keep the session lock", func(t *testing.T) {
+		t.Run("once the content has been appended", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			_, err := s.Append(context.Background(), sess.GetId(), 0, strings.NewReader("hello"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, s.locks) {
+				return
+			}
+		})
+	})
+}
+
+func TestStore_RemoveExpired(t *testing.T) {
+	t.Run("will remove the session", func(t *testing.T) {
+		t.Run("if it has expired", func(t *testing.T) {
+			s, sess := newTestSession(t)
+			expire(t, s, sess.GetId())
+
+			err := s.RemoveExpired(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = os.Stat(filepath.Join(s.root, sess.GetId()))
+			if !assert.ErrorIs(t, err, os.ErrNotExist) {
+				return
+			}
+		})
+
+		t.Run("if it expired before the store was initialized", func(t *testing.T) {
+			s, sess := newTestSession(t)
+			expire(t, s, sess.GetId())
+
+			_, err := New(s.root)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = os.Stat(filepath.Join(s.root, sess.GetId()))
+			if !assert.ErrorIs(t, err, os.ErrNotExist) {
+				return
+			}
+		})
+	})
+
+	t.Run("will keep the session", func(t *testing.T) {
+		t.Run("if it has not expired", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			err := s.RemoveExpired(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.Get(context.Background(), sess.GetId())
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+	})
+}
+
+func TestStore_Delete(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the session does not exist", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = s.Delete(context.Background(), strings.Repeat("ab", idSize))
+			if !assert.ErrorIs(t, err, session.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the session", func(t *testing.T) {
+		t.Run("if it exists", func(t *testing.T) {
+			s, sess := newTestSession(t)
+
+			err := s.Delete(context.Background(), sess.GetId())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.Get(context.Background(), sess.GetId())
+			if !assert.ErrorIs(t, err, session.ErrNotFound) {
+				return
+			}
+		})
+	})
+}
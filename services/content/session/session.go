@@ -0,0 +1,64 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package session defines where content is staged during a resumable upload.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+)
+
+var (
+	ErrNotFound  = errors.New("upload session not found")
+	ErrInvalidId = errors.New("invalid upload session id")
+)
+
+// OffsetMismatchError is returned when content is appended to an upload
+// session at an offset other than the currently committed offset.
+type OffsetMismatchError struct {
+	Committed int64
+	Requested int64
+}
+
+func (e OffsetMismatchError) Error() string {
+	return fmt.Sprintf("upload session offset mismatch: committed %d but requested %d", e.Committed, e.Requested)
+}
+
+// Store stages content for upload sessions until it's complete
+// and can be moved into Content Storage.
+//
+// Append must commit every byte it successfully writes, even if reading
+// from r fails part way through, so clients can resume from the committed
+// offset after a dropped connection instead of resending the whole chunk.
+//
+// Sessions are created for an owner which is returned with the session, so
+// the Content Service can ensure only the owner ever accesses the session.
+//
+// Stores may expire sessions which have been abandoned, after which they
+// must be treated as if they don't exist, i.e. return ErrNotFound.
+type Store interface {
+	Create(ctx context.Context, owner string, meta *contentpb.Metadata) (*contentpb.UploadSession, error)
+	Get(ctx context.Context, id string) (*contentpb.UploadSession, error)
+	Append(ctx context.Context, id string, offset int64, r io.Reader) (*contentpb.UploadSession, error)
+
+	// Open returns all content committed to the session.
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+
+	Delete(ctx context.Context, id string) error
+}
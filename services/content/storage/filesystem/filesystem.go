@@ -0,0 +1,338 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filesystem implements Content Storage on top of a local directory.
+//
+// Objects are sharded into nested directories derived from their Content ID
+// to avoid any single directory growing too large. Writes are first made to
+// a temporary file which is only renamed into place once the content has been
+// completely written, so a crash mid-upload never leaves a partially written
+// object under a real Content ID.
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	objectsDir = "objects"
+	tmpDir     = "tmp"
+
+	// keyLength is the length of the object key of a SHA-256 Content ID,
+	// which shards are taken from.
+	keyLength = 2 * sha256.Size
+)
+
+var (
+	ErrInvalidShardDepth = errors.New("shard depth must not be negative")
+	ErrInvalidShardWidth = errors.New("shard width must be positive")
+	ErrShardsTooLong     = fmt.Errorf("shard depth times width must be less than %d", keyLength)
+)
+
+type Option func(*Storage)
+
+// ShardDepth sets how many levels of nested directories objects are sharded into.
+// It must not be negative and the default is 2.
+func ShardDepth(depth int) Option {
+	return func(s *Storage) {
+		s.shardDepth = depth
+	}
+}
+
+// StaleTempAge sets how long a temporary file must go unmodified before New
+// considers it left behind by a crash and removes it. The default is 24 hours.
+func StaleTempAge(age time.Duration) Option {
+	return func(s *Storage) {
+		s.staleTempAge = age
+	}
+}
+
+// ShardWidth sets how many hex characters of the object key are used to name
+// each shard directory. It must be positive and the default is 2. Together, the
+// shards must be shorter than the 64 character key of a SHA-256 Content ID.
+func ShardWidth(width int) Option {
+	return func(s *Storage) {
+		s.shardWidth = width
+	}
+}
+
+// Storage is a storage.Storage backed by the local filesystem.
+type Storage struct {
+	root         string
+	shardDepth   int
+	shardWidth   int
+	staleTempAge time.Duration
+}
+
+// New initializes a Storage rooted at the given directory, creating it if needed.
+// Any temporary files left behind by a previous crash are removed once they're stale,
+// so writes in progress by another process sharing the same root aren't lost.
+func New(root string, opts ...Option) (*Storage, error) {
+	s := &Storage{
+		root:         root,
+		shardDepth:   2,
+		shardWidth:   2,
+		staleTempAge: 24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	switch {
+	case s.shardDepth < 0:
+		return nil, ErrInvalidShardDepth
+	case s.shardWidth < 1:
+		return nil, ErrInvalidShardWidth
+	case s.shardDepth*s.shardWidth >= keyLength:
+		return nil, ErrShardsTooLong
+	}
+
+	for _, dir := range []string{objectsDir, tmpDir} {
+		err := os.MkdirAll(filepath.Join(root, dir), 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := s.removeStaleTempFiles()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Storage) removeStaleTempFiles() error {
+	dir := filepath.Join(s.root, tmpDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// it was renamed into place or removed in the meantime
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if time.Since(info.ModTime()) < s.staleTempAge {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) path(id *contentpb.ContentId) (string, error) {
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		return "", err
+	}
+
+	elems := make([]string, 0, s.shardDepth+3)
+	elems = append(elems, s.root, objectsDir)
+	for i := 0; i < s.shardDepth && (i+1)*s.shardWidth < len(key); i++ {
+		elems = append(elems, key[i*s.shardWidth:(i+1)*s.shardWidth])
+	}
+	elems = append(elems, key)
+	return filepath.Join(elems...), nil
+}
+
+func (s *Storage) Put(ctx context.Context, id *contentpb.ContentId, r io.Reader) error {
+	spanCtx, span := otel.Tracer("filesystem").Start(ctx, "Storage.Put")
+	defer span.End()
+
+	name, err := s.path(id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Join(s.root, tmpDir), "put-*")
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	_, err = io.Copy(f, storage.ContextReader(spanCtx, r))
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = os.Rename(f.Name(), name)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	committed = true
+
+	// the rename itself is only durable once the directory it's in is synced
+	err = syncDir(filepath.Dir(name))
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func syncDir(name string) error {
+	dir, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	return errors.Join(dir.Sync(), dir.Close())
+}
+
+func (s *Storage) Get(ctx context.Context, id *contentpb.ContentId) (io.ReadCloser, error) {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Storage.Get")
+	defer span.End()
+
+	name, err := s.path(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return f, nil
+}
+
+type sectionReadCloser struct {
+	*io.SectionReader
+	io.Closer
+}
+
+func (s *Storage) GetRange(ctx context.Context, id *contentpb.ContentId, offset, length int64) (io.ReadCloser, error) {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Storage.GetRange")
+	defer span.End()
+
+	err := storage.ValidateRange(offset, length)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	name, err := s.path(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	rc := sectionReadCloser{
+		SectionReader: io.NewSectionReader(f, offset, length),
+		Closer:        f,
+	}
+	return rc, nil
+}
+
+func (s *Storage) Stat(ctx context.Context, id *contentpb.ContentId) (*storage.Info, error) {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Storage.Stat")
+	defer span.End()
+
+	name, err := s.path(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	info, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return &storage.Info{Size: info.Size()}, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, id *contentpb.ContentId) error {
+	_, span := otel.Tracer("filesystem").Start(ctx, "Storage.Delete")
+	defer span.End()
+
+	name, err := s.path(id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = os.Remove(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type readFunc func([]byte) (int, error)
+
+func (f readFunc) Read(b []byte) (int, error) {
+	return f(b)
+}
+
+func testContentId(s string) *contentpb.ContentId {
+	sum := sha256.Sum256([]byte(s))
+	return &contentpb.ContentId{
+		Value: ptr.Ref(base64.StdEncoding.EncodeToString(sum[:])),
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the shard depth is negative", func(t *testing.T) {
+			_, err := New(t.TempDir(), ShardDepth(-1))
+			if !assert.ErrorIs(t, err, ErrInvalidShardDepth) {
+				return
+			}
+		})
+
+		t.Run("if the shard width is not positive", func(t *testing.T) {
+			_, err := New(t.TempDir(), ShardWidth(0))
+			if !assert.ErrorIs(t, err, ErrInvalidShardWidth) {
+				return
+			}
+		})
+
+		t.Run("if the shards are as long as the object key", func(t *testing.T) {
+			_, err := New(t.TempDir(), ShardDepth(8), ShardWidth(8))
+			if !assert.ErrorIs(t, err, ErrShardsTooLong) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove temporary files", func(t *testing.T) {
+		t.Run("if they are stale", func(t *testing.T) {
+			root := t.TempDir()
+			err := os.MkdirAll(filepath.Join(root, tmpDir), 0o755)
+			if !assert.Nil(t, err) {
+				return
+			}
+			name := filepath.Join(root, tmpDir, "put-stale")
+			err = os.WriteFile(name, []byte("partial"), 0o644)
+			if !assert.Nil(t, err) {
+				return
+			}
+			modTime := time.Now().Add(-2 * time.Hour)
+			err = os.Chtimes(name, modTime, modTime)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = New(root, StaleTempAge(time.Hour))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = os.Stat(name)
+			if !assert.ErrorIs(t, err, os.ErrNotExist) {
+				return
+			}
+		})
+	})
+
+	t.Run("will keep temporary files", func(t *testing.T) {
+		t.Run("if they may still be written to by another process", func(t *testing.T) {
+			root := t.TempDir()
+			err := os.MkdirAll(filepath.Join(root, tmpDir), 0o755)
+			if !assert.Nil(t, err) {
+				return
+			}
+			name := filepath.Join(root, tmpDir, "put-active")
+			err = os.WriteFile(name, []byte("partial"), 0o644)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = New(root, StaleTempAge(time.Hour))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = os.Stat(name)
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_Put(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content id is not valid base64", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = s.Put(context.Background(), &contentpb.ContentId{Value: ptr.Ref("!")}, strings.NewReader("hello"))
+			if !assert.ErrorIs(t, err, storage.ErrInvalidId) {
+				return
+			}
+		})
+
+		t.Run("if it fails to read the content", func(t *testing.T) {
+			root := t.TempDir()
+			s, err := New(root)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			readErr := errors.New("failed to read")
+			id := testContentId("hello")
+			err = s.Put(context.Background(), id, io.MultiReader(
+				strings.NewReader("hello"),
+				readFunc(func(b []byte) (int, error) {
+					return 0, readErr
+				}),
+			))
+			if !assert.Equal(t, readErr, err) {
+				return
+			}
+
+			_, err = s.Stat(context.Background(), id)
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+
+			entries, err := os.ReadDir(filepath.Join(root, tmpDir))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, entries) {
+				return
+			}
+		})
+
+		t.Run("if the context is cancelled", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			id := testContentId("hello")
+			err = s.Put(ctx, id, strings.NewReader("hello"))
+			if !assert.ErrorIs(t, err, context.Canceled) {
+				return
+			}
+
+			_, err = s.Stat(context.Background(), id)
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will shard objects into nested directories", func(t *testing.T) {
+		t.Run("if the default shard options are used", func(t *testing.T) {
+			root := t.TempDir()
+			s, err := New(root)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			id := testContentId("hello")
+			err = s.Put(context.Background(), id, strings.NewReader("hello"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			name, err := s.path(id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			rel, err := filepath.Rel(filepath.Join(root, objectsDir), name)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			elems := strings.Split(rel, string(filepath.Separator))
+			if !assert.Len(t, elems, 3) {
+				return
+			}
+			if !assert.Equal(t, elems[2][:2], elems[0]) {
+				return
+			}
+			if !assert.Equal(t, elems[2][2:4], elems[1]) {
+				return
+			}
+
+			_, err = os.Stat(name)
+			if !assert.Nil(t, err) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_Get(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content does not exist", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.Get(context.Background(), testContentId("hello"))
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the content", func(t *testing.T) {
+		t.Run("if it was previously stored", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			id := testContentId("hello")
+			err = s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			rc, err := s.Get(context.Background(), id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer rc.Close()
+
+			b, err := io.ReadAll(rc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+
+			info, err := s.Stat(context.Background(), id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), info.Size) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_GetRange(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the range is empty", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.GetRange(context.Background(), testContentId("hello"), 0, 0)
+			if !assert.ErrorIs(t, err, storage.ErrInvalidRange) {
+				return
+			}
+		})
+
+		t.Run("if the content does not exist", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.GetRange(context.Background(), testContentId("hello"), 0, 5)
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the range of content", func(t *testing.T) {
+		t.Run("if it was previously stored", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			id := testContentId("hello")
+			err = s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			rc, err := s.GetRange(context.Background(), id, 6, 5)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer rc.Close()
+
+			b, err := io.ReadAll(rc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "world", string(b)) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_Delete(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content does not exist", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = s.Delete(context.Background(), testContentId("hello"))
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the content", func(t *testing.T) {
+		t.Run("if it was previously stored", func(t *testing.T) {
+			s, err := New(t.TempDir())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			id := testContentId("hello")
+			err = s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = s.Delete(context.Background(), id)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = s.Get(context.Background(), id)
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+}
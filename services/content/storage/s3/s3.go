@@ -0,0 +1,453 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package s3 implements Content Storage on top of any object storage
+// which supports the Amazon S3 API, e.g. AWS S3, MinIO, Ceph, etc.
+//
+// Content is streamed to object storage in parts using multipart uploads,
+// so only a single part is ever buffered in memory. An upload is only
+// completed once all content has been read without error, otherwise it is
+// aborted, so content never becomes visible under a Content ID unless it
+// was completely written.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// MinPartSize is the smallest part size allowed by the S3 API
+	// for all but the last part of a multipart upload.
+	MinPartSize = 5 << 20
+
+	// DefaultPartSize is the part size used if none is configured.
+	DefaultPartSize = 16 << 20
+)
+
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type Option func(*Storage)
+
+// Endpoint sets the base URL of the S3 API, e.g. http://localhost:9000 for a local MinIO.
+// The default is https://s3.{region}.amazonaws.com.
+func Endpoint(endpoint string) Option {
+	return func(s *Storage) {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// Region sets the region used when signing requests. The default is us-east-1.
+func Region(region string) Option {
+	return func(s *Storage) {
+		s.signer.region = region
+	}
+}
+
+// Credentials sets the credentials used for signing requests.
+// Requests are sent unsigned if no credentials are configured.
+func Credentials(accessKeyId, secretAccessKey, sessionToken string) Option {
+	return func(s *Storage) {
+		s.signer.accessKeyId = accessKeyId
+		s.signer.secretAccessKey = secretAccessKey
+		s.signer.sessionToken = sessionToken
+	}
+}
+
+// Prefix sets a prefix which is prepended to every object key.
+func Prefix(prefix string) Option {
+	return func(s *Storage) {
+		s.prefix = prefix
+	}
+}
+
+// PartSize sets the size of each part of a multipart upload.
+// Sizes smaller than MinPartSize are raised to MinPartSize.
+func PartSize(size int) Option {
+	return func(s *Storage) {
+		s.partSize = max(size, MinPartSize)
+	}
+}
+
+// Client sets the HTTP client used for making requests. The default is http.DefaultClient.
+func Client(hc HttpClient) Option {
+	return func(s *Storage) {
+		s.http = hc
+	}
+}
+
+// Storage is a storage.Storage backed by an S3 compatible object storage bucket.
+type Storage struct {
+	http     HttpClient
+	endpoint string
+	bucket   string
+	prefix   string
+	partSize int
+	signer   signer
+}
+
+func New(bucket string, opts ...Option) *Storage {
+	s := &Storage{
+		http:     http.DefaultClient,
+		bucket:   bucket,
+		partSize: DefaultPartSize,
+		signer: signer{
+			region:  "us-east-1",
+			service: "s3",
+			now:     time.Now,
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.endpoint == "" {
+		s.endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.signer.region)
+	}
+	return s
+}
+
+// ResponseError represents an error response returned by the S3 API.
+type ResponseError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e ResponseError) Error() string {
+	return fmt.Sprintf("s3 responded with %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (s *Storage) newRequest(ctx context.Context, method string, key string, query url.Values, body []byte) (*http.Request, error) {
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + s.prefix + key
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	var r io.Reader = http.NoBody
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	return req, nil
+}
+
+func (s *Storage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	if s.signer.accessKeyId != "" {
+		s.signer.sign(req, payloadHash)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	rerr := ResponseError{
+		StatusCode: resp.StatusCode,
+	}
+	if req.Method != http.MethodHead {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		xml.Unmarshal(b, &rerr)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.Join(storage.ErrNotFound, rerr)
+	}
+	return nil, rerr
+}
+
+func (s *Storage) send(ctx context.Context, method string, key string, query url.Values, body []byte) (*http.Response, error) {
+	req, err := s.newRequest(ctx, method, key, query, body)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hexSha256(body)
+	}
+	return s.do(req, payloadHash)
+}
+
+func (s *Storage) Put(ctx context.Context, id *contentpb.ContentId, r io.Reader) error {
+	spanCtx, span := otel.Tracer("s3").Start(ctx, "Storage.Put")
+	defer span.End()
+
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	r = storage.ContextReader(spanCtx, r)
+	part := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, part)
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		err = s.putObject(spanCtx, key, part[:n])
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.putMultipart(spanCtx, key, part, r)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) putObject(ctx context.Context, key string, body []byte) error {
+	resp, err := s.send(ctx, http.MethodPut, key, nil, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type initiateMultipartUploadResult struct {
+	UploadId string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// putMultipart uploads the already read first part followed by the remainder
+// of r. The upload is aborted if anything fails so no partial object is left behind.
+func (s *Storage) putMultipart(ctx context.Context, key string, part []byte, r io.Reader) (err error) {
+	resp, err := s.send(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	var initResult initiateMultipartUploadResult
+	err = decodeXML(resp.Body, &initResult)
+	if err != nil {
+		return err
+	}
+	uploadId := initResult.UploadId
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		// use a fresh context since ctx may be the reason for aborting
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		resp, abortErr := s.send(abortCtx, http.MethodDelete, key, url.Values{"uploadId": {uploadId}}, nil)
+		if abortErr != nil {
+			err = errors.Join(err, abortErr)
+			return
+		}
+		resp.Body.Close()
+	}()
+
+	var complete completeMultipartUpload
+	n := len(part)
+	for partNumber := 1; n > 0; partNumber++ {
+		partResp, err := s.send(ctx, http.MethodPut, key, url.Values{
+			"partNumber": {strconv.Itoa(partNumber)},
+			"uploadId":   {uploadId},
+		}, part[:n])
+		if err != nil {
+			return err
+		}
+		partResp.Body.Close()
+
+		complete.Parts = append(complete.Parts, completedPart{
+			PartNumber: partNumber,
+			ETag:       partResp.Header.Get("ETag"),
+		})
+
+		n, err = io.ReadFull(r, part)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	resp, err = s.send(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadId}}, body)
+	if err != nil {
+		return err
+	}
+
+	// S3 may report a failure to complete the upload with a 200 response
+	return decodeXML(resp.Body, &struct{}{})
+}
+
+// decodeXML decodes an XML response body into v unless
+// the body is an S3 error document.
+func decodeXML(body io.ReadCloser, v any) error {
+	defer body.Close()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		XMLName xml.Name
+	}
+	err = xml.Unmarshal(b, &doc)
+	if err != nil {
+		return err
+	}
+	if doc.XMLName.Local == "Error" {
+		rerr := ResponseError{
+			StatusCode: http.StatusOK,
+		}
+		xml.Unmarshal(b, &rerr)
+		return rerr
+	}
+	return xml.Unmarshal(b, v)
+}
+
+func (s *Storage) Get(ctx context.Context, id *contentpb.ContentId) (io.ReadCloser, error) {
+	spanCtx, span := otel.Tracer("s3").Start(ctx, "Storage.Get")
+	defer span.End()
+
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := s.send(spanCtx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *Storage) GetRange(ctx context.Context, id *contentpb.ContentId, offset, length int64) (io.ReadCloser, error) {
+	spanCtx, span := otel.Tracer("s3").Start(ctx, "Storage.GetRange")
+	defer span.End()
+
+	err := storage.ValidateRange(offset, length)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	req, err := s.newRequest(spanCtx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *Storage) Stat(ctx context.Context, id *contentpb.ContentId) (*storage.Info, error) {
+	spanCtx, span := otel.Tracer("s3").Start(ctx, "Storage.Stat")
+	defer span.End()
+
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp, err := s.send(spanCtx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	resp.Body.Close()
+	return &storage.Info{Size: resp.ContentLength}, nil
+}
+
+// Delete removes the object for the given Content ID. Since S3 reports
+// success when deleting a missing object, the object is checked for
+// existence first in order to report storage.ErrNotFound.
+func (s *Storage) Delete(ctx context.Context, id *contentpb.ContentId) error {
+	spanCtx, span := otel.Tracer("s3").Start(ctx, "Storage.Delete")
+	defer span.End()
+
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	resp, err := s.send(spanCtx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	resp.Body.Close()
+
+	resp, err = s.send(spanCtx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return resp.Body.Close()
+}
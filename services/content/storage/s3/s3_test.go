@@ -0,0 +1,420 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal in-process implementation of the S3 API
+// supporting path-style object and multipart upload requests.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	aborted []string
+	nextId  int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	if r.Header.Get("Authorization") == "" {
+		writeError(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		writeError(w, http.StatusBadRequest, "XAmzContentSHA256Mismatch")
+		return
+	}
+
+	key := r.URL.Path
+	query := r.URL.Query()
+	uploadId := query.Get("uploadId")
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.nextId++
+		uploadId = strconv.Itoa(f.nextId)
+		f.uploads[uploadId] = make(map[int][]byte)
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadId)
+	case r.Method == http.MethodPut && uploadId != "":
+		parts, exists := f.uploads[uploadId]
+		if !exists {
+			writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[partNumber] = body
+		etag := md5.Sum(body)
+		w.Header().Set("ETag", strconv.Quote(hex.EncodeToString(etag[:])))
+	case r.Method == http.MethodPost && uploadId != "":
+		parts, exists := f.uploads[uploadId]
+		if !exists {
+			writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		var complete completeMultipartUpload
+		err := xml.Unmarshal(body, &complete)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		var object []byte
+		for _, part := range complete.Parts {
+			object = append(object, parts[part.PartNumber]...)
+		}
+		delete(f.uploads, uploadId)
+		f.objects[key] = object
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete && uploadId != "":
+		delete(f.uploads, uploadId)
+		f.aborted = append(f.aborted, uploadId)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		object, exists := f.objects[key]
+		if !exists {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(object))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+type readFunc func([]byte) (int, error)
+
+func (f readFunc) Read(b []byte) (int, error) {
+	return f(b)
+}
+
+func testContentId(s string) *contentpb.ContentId {
+	sum := sha256.Sum256([]byte(s))
+	return &contentpb.ContentId{
+		Value: ptr.Ref(base64.StdEncoding.EncodeToString(sum[:])),
+	}
+}
+
+func newTestStorage(t *testing.T, fake *fakeS3, opts ...Option) *Storage {
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	opts = append([]Option{
+		Endpoint(srv.URL),
+		Credentials("access-key", "secret-key", ""),
+	}, opts...)
+	return New("bucket", opts...)
+}
+
+func TestStorage_Put(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content id is not valid base64", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			err := s.Put(context.Background(), &contentpb.ContentId{Value: ptr.Ref("!")}, strings.NewReader("hello"))
+			if !assert.ErrorIs(t, err, storage.ErrInvalidId) {
+				return
+			}
+		})
+
+		t.Run("if the request is rejected", func(t *testing.T) {
+			srv := httptest.NewServer(newFakeS3())
+			defer srv.Close()
+
+			s := New("bucket", Endpoint(srv.URL))
+
+			err := s.Put(context.Background(), testContentId("hello"), strings.NewReader("hello"))
+
+			var rerr ResponseError
+			if !assert.ErrorAs(t, err, &rerr) {
+				return
+			}
+			if !assert.Equal(t, http.StatusForbidden, rerr.StatusCode) {
+				return
+			}
+			if !assert.Equal(t, "AccessDenied", rerr.Code) {
+				return
+			}
+		})
+
+		t.Run("if it fails to read the content during a multipart upload", func(t *testing.T) {
+			fake := newFakeS3()
+			s := newTestStorage(t, fake)
+			s.partSize = 4
+
+			readErr := errors.New("failed to read")
+			id := testContentId("hello")
+			err := s.Put(context.Background(), id, io.MultiReader(
+				strings.NewReader("hello world"),
+				readFunc(func(b []byte) (int, error) {
+					return 0, readErr
+				}),
+			))
+			if !assert.ErrorIs(t, err, readErr) {
+				return
+			}
+			if !assert.Len(t, fake.aborted, 1) {
+				return
+			}
+			if !assert.Empty(t, fake.uploads) {
+				return
+			}
+
+			_, err = s.Stat(context.Background(), id)
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will store the content", func(t *testing.T) {
+		t.Run("if it fits in a single part", func(t *testing.T) {
+			fake := newFakeS3()
+			s := newTestStorage(t, fake, Prefix("content/"))
+
+			id := testContentId("hello")
+			err := s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			key, err := storage.ObjectKey(id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []byte("hello world"), fake.objects["/bucket/content/"+key]) {
+				return
+			}
+		})
+
+		t.Run("if it spans multiple parts", func(t *testing.T) {
+			fake := newFakeS3()
+			s := newTestStorage(t, fake)
+			s.partSize = 4
+
+			id := testContentId("hello")
+			err := s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, fake.uploads) {
+				return
+			}
+
+			rc, err := s.Get(context.Background(), id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer rc.Close()
+
+			b, err := io.ReadAll(rc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello world", string(b)) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_Get(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content does not exist", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			_, err := s.Get(context.Background(), testContentId("hello"))
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+
+			var rerr ResponseError
+			if !assert.ErrorAs(t, err, &rerr) {
+				return
+			}
+			if !assert.Equal(t, "NoSuchKey", rerr.Code) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_GetRange(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the range is empty", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			_, err := s.GetRange(context.Background(), testContentId("hello"), 0, 0)
+			if !assert.ErrorIs(t, err, storage.ErrInvalidRange) {
+				return
+			}
+		})
+
+		t.Run("if the content does not exist", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			_, err := s.GetRange(context.Background(), testContentId("hello"), 0, 5)
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the range of content", func(t *testing.T) {
+		t.Run("if the content exists", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			id := testContentId("hello")
+			err := s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			rc, err := s.GetRange(context.Background(), id, 6, 5)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer rc.Close()
+
+			b, err := io.ReadAll(rc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "world", string(b)) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_Stat(t *testing.T) {
+	t.Run("will return the content size", func(t *testing.T) {
+		t.Run("if the content exists", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			id := testContentId("hello")
+			err := s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			info, err := s.Stat(context.Background(), id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello world")), info.Size) {
+				return
+			}
+		})
+	})
+}
+
+func TestStorage_Delete(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the content does not exist", func(t *testing.T) {
+			s := newTestStorage(t, newFakeS3())
+
+			err := s.Delete(context.Background(), testContentId("hello"))
+			if !assert.ErrorIs(t, err, storage.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will remove the content", func(t *testing.T) {
+		t.Run("if it exists", func(t *testing.T) {
+			fake := newFakeS3()
+			s := newTestStorage(t, fake)
+
+			id := testContentId("hello")
+			err := s.Put(context.Background(), id, strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = s.Delete(context.Background(), id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.False(t, slices.Contains(keys(fake.objects), "/bucket/"+mustObjectKey(t, id))) {
+				return
+			}
+		})
+	})
+}
+
+func keys(m map[string][]byte) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+func mustObjectKey(t *testing.T, id *contentpb.ContentId) string {
+	key, err := storage.ObjectKey(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
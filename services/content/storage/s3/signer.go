@@ -0,0 +1,180 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	amzDateFormat   = "20060102T150405Z"
+	amzShortFormat  = "20060102"
+	signedAlgorithm = "AWS4-HMAC-SHA256"
+
+	// emptyPayloadHash is the hex encoded SHA-256 hash of an empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// signer implements AWS Signature Version 4 request signing.
+type signer struct {
+	accessKeyId     string
+	secretAccessKey string
+	sessionToken    string
+	region          string
+	service         string
+	now             func() time.Time
+}
+
+func (s *signer) sign(r *http.Request, payloadHash string) {
+	t := s.now().UTC()
+	amzDate := t.Format(amzDateFormat)
+	shortDate := t.Format(amzShortFormat)
+
+	r.Header.Set("X-Amz-Date", amzDate)
+	r.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		r.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers, signedHeaders := canonicalHeaders(r)
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		uriEncode(r.URL.Path, false),
+		canonicalQuery(r.URL.Query()),
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{shortDate, s.region, s.service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		signedAlgorithm,
+		amzDate,
+		scope,
+		hexSha256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+s.secretAccessKey), []byte(shortDate))
+	key = hmacSha256(key, []byte(s.region))
+	key = hmacSha256(key, []byte(s.service))
+	key = hmacSha256(key, []byte("aws4_request"))
+	signature := hex.EncodeToString(hmacSha256(key, []byte(stringToSign)))
+
+	r.Header.Set(
+		"Authorization",
+		signedAlgorithm+" Credential="+s.accessKeyId+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature,
+	)
+}
+
+func canonicalHeaders(r *http.Request) (string, string) {
+	values := map[string]string{
+		"host": r.URL.Host,
+	}
+	for name, vs := range r.Header {
+		name = strings.ToLower(name)
+		if !isSignedHeader(name) {
+			continue
+		}
+		trimmed := make([]string, len(vs))
+		for i, v := range vs {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		values[name] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(values[name])
+		sb.WriteByte('\n')
+	}
+	return sb.String(), strings.Join(names, ";")
+}
+
+func isSignedHeader(name string) bool {
+	switch name {
+	case "content-md5", "content-type", "range":
+		return true
+	default:
+		return strings.HasPrefix(name, "x-amz-")
+	}
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		vs := slices.Clone(query[key])
+		slices.Sort(vs)
+		for _, v := range vs {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent encodes every byte except the unreserved characters
+// as required by Signature Version 4. Slashes are only encoded if requested
+// so that object key paths can be encoded as is.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexChars = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(hexChars[c>>4])
+			sb.WriteByte(hexChars[c&0xf])
+		}
+	}
+	return sb.String()
+}
+
+func hexSha256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage defines the Content Storage abstraction used by the Content Service.
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+)
+
+var (
+	ErrNotFound     = errors.New("content not found")
+	ErrInvalidId    = errors.New("invalid content id")
+	ErrInvalidRange = errors.New("invalid range")
+)
+
+// Info describes a stored piece of content.
+type Info struct {
+	Size int64
+}
+
+// Storage is a key-value store where the key is a Content ID
+// and the value is the content blob.
+//
+// Put must only make content visible under the given Content ID once
+// the reader has been fully consumed without error. If reading fails,
+// nothing must be stored under the Content ID.
+//
+// GetRange returns length bytes of content starting at offset. Callers
+// are expected to keep the range within the bounds of the content, since
+// backends may either truncate or reject ranges extending beyond it.
+type Storage interface {
+	Put(ctx context.Context, id *contentpb.ContentId, r io.Reader) error
+	Get(ctx context.Context, id *contentpb.ContentId) (io.ReadCloser, error)
+	GetRange(ctx context.Context, id *contentpb.ContentId, offset, length int64) (io.ReadCloser, error)
+	Stat(ctx context.Context, id *contentpb.ContentId) (*Info, error)
+	Delete(ctx context.Context, id *contentpb.ContentId) error
+}
+
+// ObjectKey derives a key from the given Content ID which is safe to use
+// in filesystem paths and object storage keys. Content IDs are base64 encoded
+// and may contain characters, like '/', which are not safe in either.
+func ObjectKey(id *contentpb.ContentId) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(id.GetValue())
+	if err != nil || len(b) == 0 {
+		return "", ErrInvalidId
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ValidateRange checks that offset and length describe a non-empty range.
+func ValidateRange(offset, length int64) error {
+	if offset < 0 || length <= 0 {
+		return ErrInvalidRange
+	}
+	return nil
+}
+
+// ContextReader returns an io.Reader which fails with the context error
+// once ctx is done. It's intended for backends which copy from an io.Reader
+// without any other means of observing cancellation.
+func ContextReader(ctx context.Context, r io.Reader) io.Reader {
+	return &contextReader{
+		ctx: ctx,
+		r:   r,
+	}
+}
+
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(b []byte) (int, error) {
+	select {
+	case <-r.ctx.Done():
+		return 0, r.ctx.Err()
+	default:
+	}
+	return r.r.Read(b)
+}
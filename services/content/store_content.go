@@ -0,0 +1,135 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/indexpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/z5labs/humus/humuspb"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	errStoreContent = errors.New("failed to store content")
+	errIndexContent = errors.New("failed to index content")
+)
+
+// storeContent moves content into Content Storage, verifying it against the
+// checksum in the metadata as it's written, and then indexes it for the owner.
+// It's shared by every API which accepts content so they all store and index it
+// the same way. Content is addressed by its checksum so the same content uploaded
+// by different owners is only stored once, which is why it's stored and indexed
+// while holding a shared lock on the content so it can't be deleted in between.
+// If indexing fails, the content is removed from storage again unless another
+// owner has it indexed, so content is never left in storage without a record.
+func storeContent(ctx context.Context, locks *keyedLocks, store storage.Storage, idx index.Index, owner string, meta *contentpb.Metadata, r io.Reader) (*contentpb.ContentId, error) {
+	vr, err := newVerifyingReader(r, meta.GetChecksum())
+	if err != nil {
+		return nil, err
+	}
+
+	id := NewContentId(meta.GetChecksum())
+
+	unlock := locks.share(id.GetValue())
+	err = store.Put(ctx, id, vr)
+	var mismatchErr ChecksumMismatchError
+	if errors.As(err, &mismatchErr) {
+		unlock()
+		return nil, mismatchErr
+	}
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("%w: %w", errStoreContent, err)
+	}
+
+	record := &indexpb.Record{
+		ContentId:   id,
+		ContentType: meta.GetMediaType(),
+		ContentName: meta.Name,
+		ContentSize: &indexpb.ContentSize{
+			Value: ptr.Ref(uint64(vr.n)),
+			Unit:  indexpb.UnitOfInformation_BYTE.Enum(),
+		},
+		CheckSums: []*contentpb.Checksum{
+			meta.GetChecksum(),
+		},
+		Owner: ptr.Ref(owner),
+	}
+	err = idx.Put(ctx, record)
+	unlock()
+	if err != nil {
+		removeErr := removeUnindexedContent(ctx, locks, store, idx, id)
+		if removeErr != nil {
+			return nil, fmt.Errorf("%w: %w: %w", errIndexContent, err, removeErr)
+		}
+		return nil, fmt.Errorf("%w: %w", errIndexContent, err)
+	}
+	return id, nil
+}
+
+var errRemoveUnindexedContent = errors.New("failed to remove unindexed content from storage")
+
+// removeUnindexedContent deletes content from storage after it failed to be indexed,
+// unless another owner has it indexed. It holds an exclusive lock on the content,
+// rather than the shared one used to store it, so no other upload of the same
+// content can be in between storing and indexing it while it's checked.
+func removeUnindexedContent(ctx context.Context, locks *keyedLocks, store storage.Storage, idx index.Index, id *contentpb.ContentId) error {
+	unlock := locks.exclusive(id.GetValue())
+	defer unlock()
+
+	for _, err := range idx.QueryByContentId(ctx, id) {
+		if err != nil {
+			return fmt.Errorf("%w: %w", errRemoveUnindexedContent, err)
+		}
+		return nil
+	}
+
+	err := store.Delete(ctx, id)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("%w: %w", errRemoveUnindexedContent, err)
+	}
+	return nil
+}
+
+// writeStoreContentError responds with the humuspb.Status for an error returned by storeContent.
+func writeStoreContentError(ctx context.Context, log *slog.Logger, w http.ResponseWriter, marshal func(proto.Message) ([]byte, error), err error) {
+	var unsupportedErr UnsupportedHashFuncError
+	var mismatchErr ChecksumMismatchError
+	switch {
+	case errors.As(err, &unsupportedErr):
+		log.WarnContext(ctx, "unable to verify content checksum", slog.String("error", err.Error()))
+		writeStatus(log, w, marshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+	case errors.As(err, &mismatchErr):
+		log.WarnContext(ctx, "content checksum did not match", slog.String("error", err.Error()))
+		writeStatus(log, w, marshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+	case errors.Is(err, errIndexContent):
+		log.ErrorContext(ctx, "failed to index content", slog.String("error", err.Error()))
+		writeStatus(log, w, marshal, humuspb.Code_INTERNAL, errIndexContent.Error())
+	default:
+		log.ErrorContext(ctx, "failed to store content", slog.String("error", err.Error()))
+		writeStatus(log, w, marshal, humuspb.Code_INTERNAL, errStoreContent.Error())
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package boltdb implements a persistent token.Store embedded
+// in a single file using bbolt, a pure Go key/value store.
+package boltdb
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	bolt "go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var tokensBucket = []byte("tokens")
+
+// Store is a token.Store persisted to a bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens, or creates, the token database file at the given path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tokensBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Put(ctx context.Context, record *tokenpb.Record) error {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Put")
+	defer span.End()
+
+	id := record.GetToken().GetId()
+	if id == "" {
+		return token.ErrMissingTokenId
+	}
+
+	b, err := proto.Marshal(record)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokensBucket).Put([]byte(id), b)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*tokenpb.Record, error) {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Get")
+	defer span.End()
+
+	var record tokenpb.Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(tokensBucket).Get([]byte(id))
+		if b == nil {
+			return token.ErrNotFound
+		}
+		return proto.Unmarshal(b, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// List reads every record in a single read transaction before yielding
+// any of them so consumers are free to modify the store while iterating.
+func (s *Store) List(ctx context.Context) iter.Seq2[*tokenpb.Record, error] {
+	return func(yield func(*tokenpb.Record, error) bool) {
+		var records []*tokenpb.Record
+		err := s.db.View(func(tx *bolt.Tx) error {
+			return tx.Bucket(tokensBucket).ForEach(func(k, v []byte) error {
+				var record tokenpb.Record
+				err := proto.Unmarshal(v, &record)
+				if err != nil {
+					return err
+				}
+				records = append(records, &record)
+				return nil
+			})
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, record := range records {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Delete(ctx context.Context, id string) error {
+	_, span := otel.Tracer("boltdb").Start(ctx, "Store.Delete")
+	defer span.End()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tokensBucket)
+		if bucket.Get([]byte(id)) == nil {
+			return token.ErrNotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
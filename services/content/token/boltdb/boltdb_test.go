@@ -0,0 +1,74 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/token/tokentest"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	tokentest.Run(t, func(t *testing.T) token.Store {
+		s, err := Open(filepath.Join(t.TempDir(), "tokens.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			s.Close()
+		})
+		return s
+	})
+
+	t.Run("will persist tokens", func(t *testing.T) {
+		t.Run("if the store is reopened", func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "tokens.db")
+
+			s, err := Open(path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			tok, secret, err := token.Mint(ctx, s, "ci", "", tokenpb.Scope_UPLOAD)
+			if !assert.Nil(t, err) {
+				return
+			}
+			err = s.Close()
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			s, err = Open(path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer s.Close()
+
+			verified, err := token.Verify(ctx, s, secret)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, tok.GetId(), verified.GetId()) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements an in-memory token.Store.
+package memory
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Store is a token.Store which keeps all records in memory.
+// It's primarily intended for testing and ephemeral deployments.
+type Store struct {
+	mu      sync.RWMutex
+	records map[string]*tokenpb.Record
+}
+
+func New() *Store {
+	return &Store{
+		records: make(map[string]*tokenpb.Record),
+	}
+}
+
+func (s *Store) Put(ctx context.Context, record *tokenpb.Record) error {
+	id := record.GetToken().GetId()
+	if id == "" {
+		return token.ErrMissingTokenId
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[id] = proto.Clone(record).(*tokenpb.Record)
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*tokenpb.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, exists := s.records[id]
+	if !exists {
+		return nil, token.ErrNotFound
+	}
+	return proto.Clone(record).(*tokenpb.Record), nil
+}
+
+// List snapshots the records before yielding any of them
+// so consumers are free to modify the store while iterating.
+func (s *Store) List(ctx context.Context) iter.Seq2[*tokenpb.Record, error] {
+	return func(yield func(*tokenpb.Record, error) bool) {
+		s.mu.RLock()
+		records := make([]*tokenpb.Record, 0, len(s.records))
+		for _, id := range slices.Sorted(maps.Keys(s.records)) {
+			records = append(records, proto.Clone(s.records[id]).(*tokenpb.Record))
+		}
+		s.mu.RUnlock()
+
+		for _, record := range records {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.records[id]
+	if !exists {
+		return token.ErrNotFound
+	}
+	delete(s.records, id)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package token defines the API tokens used to authenticate requests to the Content Service.
+//
+// A token is identified by an ID which is embedded in its secret, so looking
+// up a token never requires comparing against every stored secret. Only the
+// SHA-256 hash of a secret is ever stored.
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"iter"
+	"strings"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/tokenpb"
+)
+
+var (
+	ErrNotFound       = errors.New("token not found")
+	ErrMissingTokenId = errors.New("token record is missing token id")
+	ErrInvalidSecret  = errors.New("invalid token secret")
+)
+
+// Store keeps a Record for every token which hasn't been revoked, keyed by its token ID.
+type Store interface {
+	// Put creates or replaces the Record for its token ID.
+	Put(ctx context.Context, record *tokenpb.Record) error
+
+	Get(ctx context.Context, id string) (*tokenpb.Record, error)
+
+	// List returns all Records ordered by token ID.
+	List(ctx context.Context) iter.Seq2[*tokenpb.Record, error]
+
+	Delete(ctx context.Context, id string) error
+}
+
+// secretPrefix makes secrets easy to recognize, e.g. by secret scanners.
+const secretPrefix = "griot_"
+
+// Mint creates a new token with the given name and scope, which authenticates
+// requests as the given user, and returns its secret. The secret can't be
+// recovered after Mint returns.
+func Mint(ctx context.Context, store Store, name, user string, scope tokenpb.Scope) (*tokenpb.Token, string, error) {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return nil, "", err
+	}
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tokenId := hex.EncodeToString(id)
+	secret := secretPrefix + tokenId + "_" + base64.RawURLEncoding.EncodeToString(key)
+	hash := sha256.Sum256([]byte(secret))
+
+	token := &tokenpb.Token{
+		Id:    ptr.Ref(tokenId),
+		Name:  ptr.Ref(name),
+		Scope: scope.Enum(),
+		User:  ptr.Ref(user),
+	}
+	err = store.Put(ctx, &tokenpb.Record{
+		Token:      token,
+		SecretHash: hash[:],
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// Verify returns the token the secret belongs to. ErrInvalidSecret is
+// returned if the secret is malformed, revoked or doesn't match.
+func Verify(ctx context.Context, store Store, secret string) (*tokenpb.Token, error) {
+	rest, ok := strings.CutPrefix(secret, secretPrefix)
+	if !ok {
+		return nil, ErrInvalidSecret
+	}
+	tokenId, _, ok := strings.Cut(rest, "_")
+	if !ok || tokenId == "" {
+		return nil, ErrInvalidSecret
+	}
+
+	record, err := store.Get(ctx, tokenId)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrInvalidSecret
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(hash[:], record.GetSecretHash()) != 1 {
+		return nil, ErrInvalidSecret
+	}
+	return record.GetToken(), nil
+}
+
+// Grants reports whether a token with the granted scope may be used
+// for a request requiring the other scope. Scopes are ordered so every
+// scope grants everything the scopes before it do.
+func Grants(granted, required tokenpb.Scope) bool {
+	return granted >= required
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokentest provides a conformance test suite for token.Store implementations.
+package tokentest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(t *testing.T, records func(yield func(*tokenpb.Record, error) bool)) []string {
+	t.Helper()
+
+	var ns []string
+	for record, err := range records {
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		ns = append(ns, record.GetToken().GetName())
+	}
+	return ns
+}
+
+// Run runs the conformance test suite against the token.Store returned by newStore.
+// A new token.Store is requested for every test.
+func Run(t *testing.T, newStore func(*testing.T) token.Store) {
+	ctx := context.Background()
+
+	t.Run("Put", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the record is missing a token id", func(t *testing.T) {
+				s := newStore(t)
+
+				err := s.Put(ctx, &tokenpb.Record{})
+				if !assert.ErrorIs(t, err, token.ErrMissingTokenId) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the record does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := s.Get(ctx, "0123456789abcdef")
+				if !assert.ErrorIs(t, err, token.ErrNotFound) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the record", func(t *testing.T) {
+			t.Run("if it was minted", func(t *testing.T) {
+				s := newStore(t)
+
+				tok, _, err := token.Mint(ctx, s, "ci", "bob", tokenpb.Scope_UPLOAD)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				record, err := s.Get(ctx, tok.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, "ci", record.GetToken().GetName()) {
+					return
+				}
+				if !assert.Equal(t, tokenpb.Scope_UPLOAD, record.GetToken().GetScope()) {
+					return
+				}
+				if !assert.Equal(t, "bob", record.GetToken().GetUser()) {
+					return
+				}
+				if !assert.NotEmpty(t, record.GetSecretHash()) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Run("will return every record", func(t *testing.T) {
+			s := newStore(t)
+
+			for _, name := range []string{"a", "b", "c"} {
+				_, _, err := token.Mint(ctx, s, name, "", tokenpb.Scope_READ)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			if !assert.ElementsMatch(t, []string{"a", "b", "c"}, names(t, s.List(ctx))) {
+				return
+			}
+		})
+
+		t.Run("will allow records to be deleted while iterating", func(t *testing.T) {
+			s := newStore(t)
+
+			for _, name := range []string{"a", "b"} {
+				_, _, err := token.Mint(ctx, s, name, "", tokenpb.Scope_READ)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			for record, err := range s.List(ctx) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				err = s.Delete(ctx, record.GetToken().GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+			if !assert.Empty(t, names(t, s.List(ctx))) {
+				return
+			}
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the record does not exist", func(t *testing.T) {
+				s := newStore(t)
+
+				err := s.Delete(ctx, "0123456789abcdef")
+				if !assert.ErrorIs(t, err, token.ErrNotFound) {
+					return
+				}
+			})
+		})
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		t.Run("will return an error", func(t *testing.T) {
+			t.Run("if the secret is malformed", func(t *testing.T) {
+				s := newStore(t)
+
+				_, err := token.Verify(ctx, s, "not a token")
+				if !assert.ErrorIs(t, err, token.ErrInvalidSecret) {
+					return
+				}
+			})
+
+			t.Run("if the secret does not match", func(t *testing.T) {
+				s := newStore(t)
+
+				_, secret, err := token.Mint(ctx, s, "ci", "", tokenpb.Scope_UPLOAD)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = token.Verify(ctx, s, secret+"x")
+				if !assert.ErrorIs(t, err, token.ErrInvalidSecret) {
+					return
+				}
+			})
+
+			t.Run("if the token has been revoked", func(t *testing.T) {
+				s := newStore(t)
+
+				tok, secret, err := token.Mint(ctx, s, "ci", "", tokenpb.Scope_UPLOAD)
+				if !assert.Nil(t, err) {
+					return
+				}
+				err = s.Delete(ctx, tok.GetId())
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				_, err = token.Verify(ctx, s, secret)
+				if !assert.ErrorIs(t, err, token.ErrInvalidSecret) {
+					return
+				}
+			})
+		})
+
+		t.Run("will return the token", func(t *testing.T) {
+			t.Run("if the secret matches", func(t *testing.T) {
+				s := newStore(t)
+
+				tok, secret, err := token.Mint(ctx, s, "ci", "", tokenpb.Scope_ADMIN)
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				verified, err := token.Verify(ctx, s, secret)
+				if !assert.Nil(t, err) {
+					return
+				}
+				if !assert.Equal(t, tok.GetId(), verified.GetId()) {
+					return
+				}
+				if !assert.Equal(t, tokenpb.Scope_ADMIN, verified.GetScope()) {
+					return
+				}
+			})
+		})
+	})
+}
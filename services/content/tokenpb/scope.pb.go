@@ -0,0 +1,135 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: scope.proto
+
+package tokenpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Scopes are ordered so every scope grants everything the scopes before it do.
+type Scope int32
+
+const (
+	Scope_READ   Scope = 0
+	Scope_UPLOAD Scope = 1
+	Scope_ADMIN  Scope = 2
+)
+
+// Enum value maps for Scope.
+var (
+	Scope_name = map[int32]string{
+		0: "READ",
+		1: "UPLOAD",
+		2: "ADMIN",
+	}
+	Scope_value = map[string]int32{
+		"READ":   0,
+		"UPLOAD": 1,
+		"ADMIN":  2,
+	}
+)
+
+func (x Scope) Enum() *Scope {
+	p := new(Scope)
+	*p = x
+	return p
+}
+
+func (x Scope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Scope) Descriptor() protoreflect.EnumDescriptor {
+	return file_scope_proto_enumTypes[0].Descriptor()
+}
+
+func (Scope) Type() protoreflect.EnumType {
+	return &file_scope_proto_enumTypes[0]
+}
+
+func (x Scope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Scope.Descriptor instead.
+func (Scope) EnumDescriptor() ([]byte, []int) {
+	return file_scope_proto_rawDescGZIP(), []int{0}
+}
+
+var File_scope_proto protoreflect.FileDescriptor
+
+var file_scope_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x67,
+	0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x2a, 0x28, 0x0a, 0x05, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x52,
+	0x45, 0x41, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10,
+	0x01, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x10, 0x02, 0x42, 0x3a, 0x5a, 0x38,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x35, 0x6c, 0x61, 0x62,
+	0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x70, 0x62,
+	0x3b, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x70, 0x62, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x70, 0xe8, 0x07,
+}
+
+var (
+	file_scope_proto_rawDescOnce sync.Once
+	file_scope_proto_rawDescData = file_scope_proto_rawDesc
+)
+
+func file_scope_proto_rawDescGZIP() []byte {
+	file_scope_proto_rawDescOnce.Do(func() {
+		file_scope_proto_rawDescData = protoimpl.X.CompressGZIP(file_scope_proto_rawDescData)
+	})
+	return file_scope_proto_rawDescData
+}
+
+var file_scope_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_scope_proto_goTypes = []any{
+	(Scope)(0), // 0: griot.content.token.Scope
+}
+var file_scope_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_scope_proto_init() }
+func file_scope_proto_init() {
+	if File_scope_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_scope_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_scope_proto_goTypes,
+		DependencyIndexes: file_scope_proto_depIdxs,
+		EnumInfos:         file_scope_proto_enumTypes,
+	}.Build()
+	File_scope_proto = out.File
+	file_scope_proto_rawDesc = nil
+	file_scope_proto_goTypes = nil
+	file_scope_proto_depIdxs = nil
+}
@@ -0,0 +1,146 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.30.0--dev
+// source: token_record.proto
+
+package tokenpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Record struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token *Token `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	// secret_hash is the SHA-256 hash of the token secret, which is never stored.
+	SecretHash []byte `protobuf:"bytes,2,opt,name=secret_hash,json=secretHash" json:"secret_hash,omitempty"`
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_token_record_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_token_record_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_token_record_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Record) GetToken() *Token {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *Record) GetSecretHash() []byte {
+	if x != nil {
+		return x.SecretHash
+	}
+	return nil
+}
+
+var File_token_record_proto protoreflect.FileDescriptor
+
+var file_token_record_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x2e, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x1a, 0x0b, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5b, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x12, 0x30, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2e,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x48,
+	0x61, 0x73, 0x68, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x7a, 0x35, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x67, 0x72, 0x69, 0x6f, 0x74, 0x2f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x2f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x70, 0x62, 0x3b, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x70, 0x62, 0x62,
+	0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
+}
+
+var (
+	file_token_record_proto_rawDescOnce sync.Once
+	file_token_record_proto_rawDescData = file_token_record_proto_rawDesc
+)
+
+func file_token_record_proto_rawDescGZIP() []byte {
+	file_token_record_proto_rawDescOnce.Do(func() {
+		file_token_record_proto_rawDescData = protoimpl.X.CompressGZIP(file_token_record_proto_rawDescData)
+	})
+	return file_token_record_proto_rawDescData
+}
+
+var file_token_record_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_token_record_proto_goTypes = []any{
+	(*Record)(nil), // 0: griot.content.token.Record
+	(*Token)(nil),  // 1: griot.content.token.Token
+}
+var file_token_record_proto_depIdxs = []int32{
+	1, // 0: griot.content.token.Record.token:type_name -> griot.content.token.Token
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_token_record_proto_init() }
+func file_token_record_proto_init() {
+	if File_token_record_proto != nil {
+		return
+	}
+	file_token_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_token_record_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_token_record_proto_goTypes,
+		DependencyIndexes: file_token_record_proto_depIdxs,
+		MessageInfos:      file_token_record_proto_msgTypes,
+	}.Build()
+	File_token_record_proto = out.File
+	file_token_record_proto_rawDesc = nil
+	file_token_record_proto_goTypes = nil
+	file_token_record_proto_depIdxs = nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/token"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrMissingTokenName = errors.New("token name is required")
+	ErrUnknownScope     = errors.New("unknown token scope")
+	ErrInvalidTokenUser = errors.New("token user must not contain a NUL character")
+)
+
+// tokensV1Handler implements the admin APIs for minting, listing and revoking API tokens.
+type tokensV1Handler struct {
+	log            *slog.Logger
+	tokens         token.Store
+	protoMarshal   func(proto.Message) ([]byte, error)
+	protoUnmarshal func([]byte, proto.Message) error
+}
+
+func (h *tokensV1Handler) create(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "tokensV1Handler.create")
+	defer span.End()
+
+	req, err := h.readRequest(r)
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read create token request", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	user := req.GetUser()
+	if user == "" {
+		user = callerFromContext(spanCtx)
+	}
+
+	tok, secret, err := token.Mint(spanCtx, h.tokens, req.GetName(), user, req.GetScope())
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to mint token", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to mint token")
+		return
+	}
+
+	w.Header().Set("Location", "/v1/tokens/"+url.PathEscape(tok.GetId()))
+	writeProto(h.log, w, http.StatusCreated, h.protoMarshal, &tokenpb.CreateTokenV1Response{
+		Token:  tok,
+		Secret: &secret,
+	})
+}
+
+func (h *tokensV1Handler) readRequest(r *http.Request) (*tokenpb.CreateTokenV1Request, error) {
+	if r.Header.Get("Content-Type") != rest.ProtobufContentType {
+		return nil, ErrUnsupportedContentType
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxMetadataSize {
+		return nil, ErrRequestTooLarge
+	}
+
+	var req tokenpb.CreateTokenV1Request
+	err = h.protoUnmarshal(b, &req)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetName() == "" {
+		return nil, ErrMissingTokenName
+	}
+	if _, known := tokenpb.Scope_name[int32(req.GetScope())]; !known {
+		return nil, ErrUnknownScope
+	}
+	if index.ValidateOwner(req.GetUser()) != nil {
+		return nil, ErrInvalidTokenUser
+	}
+	return &req, nil
+}
+
+func (h *tokensV1Handler) list(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "tokensV1Handler.list")
+	defer span.End()
+
+	resp := &tokenpb.ListTokensV1Response{}
+	for record, err := range h.tokens.List(spanCtx) {
+		if err != nil {
+			span.RecordError(err)
+			h.log.ErrorContext(spanCtx, "failed to list tokens", slog.String("error", err.Error()))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to list tokens")
+			return
+		}
+		resp.Tokens = append(resp.Tokens, record.GetToken())
+	}
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, resp)
+}
+
+func (h *tokensV1Handler) revoke(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "tokensV1Handler.revoke")
+	defer span.End()
+
+	err := h.tokens.Delete(spanCtx, r.PathValue("id"))
+	if errors.Is(err, token.ErrNotFound) {
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, err.Error())
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to revoke token", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to revoke token")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
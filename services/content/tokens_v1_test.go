@@ -0,0 +1,232 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/token"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func TestTokensV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if authentication is not enabled", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, memory.New()))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.ListTokens(context.Background())
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNIMPLEMENTED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the token name is missing", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			_, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Scope: tokenpb.Scope_READ,
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the scope is unknown", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			_, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "ci",
+				Scope: tokenpb.Scope(42),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the user contains a NUL character", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			_, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "ci",
+				Scope: tokenpb.Scope_READ,
+				User:  "alice\x00",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the caller is not an admin", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_UPLOAD)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			_, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "ci",
+				Scope: tokenpb.Scope_ADMIN,
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_PERMISSION_DENIED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the revoked token does not exist", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			err := c.RevokeToken(context.Background(), &RevokeTokenRequest{
+				Id: "0123456789abcdef",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will mint a token", func(t *testing.T) {
+		t.Run("whose secret authenticates with the requested scope", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			createResp, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "ci",
+				Scope: tokenpb.Scope_UPLOAD,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "ci", createResp.Token.GetName()) {
+				return
+			}
+			if !assert.Equal(t, tokenpb.Scope_UPLOAD, createResp.Token.GetScope()) {
+				return
+			}
+			if !assert.Equal(t, "bob", createResp.Token.GetUser()) {
+				return
+			}
+
+			tok, err := token.Verify(context.Background(), tokens, createResp.Secret)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, createResp.Token.GetId(), tok.GetId()) {
+				return
+			}
+
+			listResp, err := c.ListTokens(context.Background())
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, listResp.Tokens, 2) {
+				return
+			}
+		})
+
+		t.Run("for the requested user", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			secret := mintToken(t, tokens, "bob", tokenpb.Scope_ADMIN)
+
+			srv := httptest.NewServer(NewServer(nil, memory.New(), Authentication(tokens)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL, Credentials(secret))
+
+			createResp, err := c.CreateToken(context.Background(), &CreateTokenRequest{
+				Name:  "alice's laptop",
+				Scope: tokenpb.Scope_UPLOAD,
+				User:  "alice",
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "alice", createResp.Token.GetUser()) {
+				return
+			}
+		})
+	})
+}
@@ -0,0 +1,339 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxMetadataSize bounds how much of the metadata form field
+// will be read before the request is rejected.
+const maxMetadataSize = 1 << 20
+
+type uploadContentV1Handler struct {
+	log            *slog.Logger
+	store          storage.Storage
+	index          index.Index
+	locks          *keyedLocks
+	protoMarshal   func(proto.Message) ([]byte, error)
+	protoUnmarshal func([]byte, proto.Message) error
+
+	// spoolDir is where content uploaded with a trailing checksum is
+	// temporarily written. If empty, the default temp directory is used.
+	spoolDir string
+
+	// maxSpooled is the most content which will be written to spoolDir
+	// for a single upload before it's rejected.
+	maxSpooled int64
+}
+
+type InvalidFormFieldError struct {
+	Name  string
+	Cause error
+}
+
+func (e InvalidFormFieldError) Error() string {
+	return fmt.Sprintf("invalid form field: %s: %s", e.Name, e.Cause)
+}
+
+func (e InvalidFormFieldError) Unwrap() error {
+	return e.Cause
+}
+
+var (
+	ErrMissingFormField         = errors.New("missing form field")
+	ErrUnexpectedFormField      = errors.New("unexpected form field")
+	ErrUnsupportedFormFieldType = errors.New("unsupported content type")
+	ErrFormFieldTooLarge        = errors.New("form field too large")
+	ErrMissingChecksum          = errors.New("missing checksum")
+	ErrHashFuncMismatch         = errors.New("trailing checksum hash func does not match metadata")
+	ErrContentTooLarge          = errors.New("content too large")
+)
+
+func (h *uploadContentV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "uploadContentV1Handler.ServeHTTP")
+	defer span.End()
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "request is not a multipart form", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	meta, err := h.readMetadata(mr)
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read metadata", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	part, err := nextPart(mr, "content")
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read content", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+	defer part.Close()
+
+	content := io.Reader(part)
+	if len(meta.GetChecksum().GetHash()) == 0 {
+		spooled, err := h.spoolContent(spanCtx, mr, meta, part)
+		var ffErr InvalidFormFieldError
+		if errors.As(err, &ffErr) {
+			span.RecordError(err)
+			h.log.WarnContext(spanCtx, "failed to read trailing checksum", slog.String("error", err.Error()))
+			writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+			return
+		}
+		if errors.Is(err, ErrContentTooLarge) {
+			span.RecordError(err)
+			h.log.WarnContext(spanCtx, "content is too large to spool", slog.Int64("max_bytes", h.maxSpooled))
+			writeProto(h.log, w, http.StatusRequestEntityTooLarge, h.protoMarshal, &humuspb.Status{
+				Code:    humuspb.Code_INVALID_ARGUMENT.Enum(),
+				Message: ptr.Ref(err.Error()),
+			})
+			return
+		}
+		if err != nil {
+			span.RecordError(err)
+			writeStoreContentError(spanCtx, h.log, w, h.protoMarshal, err)
+			return
+		}
+		defer spooled.Close()
+
+		content = spooled
+	}
+
+	id, err := storeContent(spanCtx, h.locks, h.store, h.index, callerFromContext(spanCtx), meta, content)
+	if err != nil {
+		span.RecordError(err)
+		writeStoreContentError(spanCtx, h.log, w, h.protoMarshal, err)
+		return
+	}
+
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, &contentpb.UploadContentV1Response{
+		Id: id,
+	})
+}
+
+func nextPart(mr *multipart.Reader, name string) (*multipart.Part, error) {
+	part, err := mr.NextPart()
+	if err == io.EOF {
+		return nil, InvalidFormFieldError{
+			Name:  name,
+			Cause: ErrMissingFormField,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if part.FormName() != name {
+		part.Close()
+		return nil, InvalidFormFieldError{
+			Name:  part.FormName(),
+			Cause: ErrUnexpectedFormField,
+		}
+	}
+	return part, nil
+}
+
+func (h *uploadContentV1Handler) readMetadata(mr *multipart.Reader) (*contentpb.Metadata, error) {
+	part, err := nextPart(mr, "metadata")
+	if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	contentType := part.Header.Get("Content-Type")
+	if contentType != rest.ProtobufContentType {
+		return nil, InvalidFormFieldError{
+			Name:  "metadata",
+			Cause: ErrUnsupportedFormFieldType,
+		}
+	}
+
+	b, err := io.ReadAll(io.LimitReader(part, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxMetadataSize {
+		return nil, InvalidFormFieldError{
+			Name:  "metadata",
+			Cause: ErrFormFieldTooLarge,
+		}
+	}
+
+	var meta contentpb.Metadata
+	err = h.protoUnmarshal(b, &meta)
+	if err != nil {
+		return nil, InvalidFormFieldError{
+			Name:  "metadata",
+			Cause: err,
+		}
+	}
+	if meta.Checksum == nil {
+		return nil, InvalidFormFieldError{
+			Name:  "metadata",
+			Cause: ErrMissingChecksum,
+		}
+	}
+	err = normalizeMediaType(&meta)
+	if err != nil {
+		return nil, InvalidFormFieldError{
+			Name:  "metadata",
+			Cause: err,
+		}
+	}
+	return &meta, nil
+}
+
+// spooledFile is removed once it's closed.
+type spooledFile struct {
+	*os.File
+}
+
+func (f spooledFile) Close() error {
+	return errors.Join(f.File.Close(), os.Remove(f.Name()))
+}
+
+// spoolContent handles uploads whose metadata checksum has no hash, which
+// means the client computed it while sending the content and sent it in a
+// trailing checksum form field. Since the Content ID can't be known until
+// then, the content is written to a temporary file while it's hashed and
+// only stored once the trailing checksum has been read and verified. Content
+// larger than maxSpooled is rejected so uploads can't fill up the disk.
+func (h *uploadContentV1Handler) spoolContent(ctx context.Context, mr *multipart.Reader, meta *contentpb.Metadata, content io.Reader) (io.ReadCloser, error) {
+	_, span := otel.Tracer("content").Start(ctx, "uploadContentV1Handler.spoolContent")
+	defer span.End()
+
+	hashFunc := meta.GetChecksum().GetHashFunc()
+	hasher, err := NewHash(hashFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(h.spoolDir, "griot-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	spooled := spooledFile{File: f}
+
+	n, err := io.Copy(io.MultiWriter(f, hasher), io.LimitReader(content, h.maxSpooled+1))
+	if err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	if n > h.maxSpooled {
+		spooled.Close()
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrContentTooLarge, h.maxSpooled)
+	}
+
+	checksum, err := h.readChecksum(mr)
+	if err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	if checksum.GetHashFunc() != hashFunc {
+		spooled.Close()
+		return nil, InvalidFormFieldError{
+			Name:  "checksum",
+			Cause: ErrHashFuncMismatch,
+		}
+	}
+
+	sum := hasher.Sum(nil)
+	if !bytes.Equal(sum, checksum.GetHash()) {
+		spooled.Close()
+		return nil, ChecksumMismatchError{
+			HashFunc: hashFunc,
+			Expected: checksum.GetHash(),
+			Actual:   sum,
+		}
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		spooled.Close()
+		return nil, err
+	}
+
+	meta.Checksum = checksum
+	return spooled, nil
+}
+
+func (h *uploadContentV1Handler) readChecksum(mr *multipart.Reader) (*contentpb.Checksum, error) {
+	part, err := nextPart(mr, "checksum")
+	if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	contentType := part.Header.Get("Content-Type")
+	if contentType != rest.ProtobufContentType {
+		return nil, InvalidFormFieldError{
+			Name:  "checksum",
+			Cause: ErrUnsupportedFormFieldType,
+		}
+	}
+
+	b, err := io.ReadAll(io.LimitReader(part, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxMetadataSize {
+		return nil, InvalidFormFieldError{
+			Name:  "checksum",
+			Cause: ErrFormFieldTooLarge,
+		}
+	}
+
+	var checksum contentpb.Checksum
+	err = h.protoUnmarshal(b, &checksum)
+	if err != nil {
+		return nil, InvalidFormFieldError{
+			Name:  "checksum",
+			Cause: err,
+		}
+	}
+	if len(checksum.GetHash()) == 0 {
+		return nil, InvalidFormFieldError{
+			Name:  "checksum",
+			Cause: ErrMissingChecksum,
+		}
+	}
+	return &checksum, nil
+}
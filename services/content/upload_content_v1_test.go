@@ -0,0 +1,742 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/indexpb"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"google.golang.org/protobuf/proto"
+)
+
+type storagePutFunc func(context.Context, *contentpb.ContentId, io.Reader) error
+
+type storageGetFunc func(context.Context, *contentpb.ContentId) (io.ReadCloser, error)
+
+type storageGetRangeFunc func(context.Context, *contentpb.ContentId, int64, int64) (io.ReadCloser, error)
+
+type storageDeleteFunc func(context.Context, *contentpb.ContentId) error
+
+type storageStub struct {
+	storage.Storage
+
+	put      storagePutFunc
+	get      storageGetFunc
+	getRange storageGetRangeFunc
+	del      storageDeleteFunc
+}
+
+func (s storageStub) Put(ctx context.Context, id *contentpb.ContentId, r io.Reader) error {
+	return s.put(ctx, id, r)
+}
+
+func (s storageStub) Get(ctx context.Context, id *contentpb.ContentId) (io.ReadCloser, error) {
+	return s.get(ctx, id)
+}
+
+func (s storageStub) GetRange(ctx context.Context, id *contentpb.ContentId, offset, length int64) (io.ReadCloser, error) {
+	return s.getRange(ctx, id, offset, length)
+}
+
+func (s storageStub) Delete(ctx context.Context, id *contentpb.ContentId) error {
+	return s.del(ctx, id)
+}
+
+type indexPutFunc func(context.Context, *indexpb.Record) error
+
+type indexGetFunc func(context.Context, string, *contentpb.ContentId) (*indexpb.Record, error)
+
+type indexGetByChecksumFunc func(context.Context, string, *contentpb.Checksum) (*indexpb.Record, error)
+
+type indexQueryByContentIdFunc func(context.Context, *contentpb.ContentId) iter.Seq2[*indexpb.Record, error]
+
+type indexListFunc func(context.Context, string, index.Filter, []byte) iter.Seq2[*indexpb.Record, error]
+
+type indexDeleteFunc func(context.Context, string, *contentpb.ContentId) error
+
+type indexStub struct {
+	index.Index
+
+	put              indexPutFunc
+	get              indexGetFunc
+	getByChecksum    indexGetByChecksumFunc
+	queryByContentId indexQueryByContentIdFunc
+	list             indexListFunc
+	del              indexDeleteFunc
+}
+
+func (s indexStub) Put(ctx context.Context, record *indexpb.Record) error {
+	return s.put(ctx, record)
+}
+
+func (s indexStub) Get(ctx context.Context, owner string, id *contentpb.ContentId) (*indexpb.Record, error) {
+	return s.get(ctx, owner, id)
+}
+
+func (s indexStub) GetByChecksum(ctx context.Context, owner string, checksum *contentpb.Checksum) (*indexpb.Record, error) {
+	return s.getByChecksum(ctx, owner, checksum)
+}
+
+func (s indexStub) QueryByContentId(ctx context.Context, id *contentpb.ContentId) iter.Seq2[*indexpb.Record, error] {
+	return s.queryByContentId(ctx, id)
+}
+
+func (s indexStub) List(ctx context.Context, owner string, filter index.Filter, after []byte) iter.Seq2[*indexpb.Record, error] {
+	return s.list(ctx, owner, filter, after)
+}
+
+func (s indexStub) Delete(ctx context.Context, owner string, id *contentpb.ContentId) error {
+	return s.del(ctx, owner, id)
+}
+
+func readStatus(t *testing.T, resp *http.Response) *humuspb.Status {
+	t.Helper()
+
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	if !assert.Equal(t, rest.ProtobufContentType, resp.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	var status humuspb.Status
+	err = proto.Unmarshal(b, &status)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	return &status
+}
+
+// newTrailingChecksumForm builds an upload form whose checksum, if not nil,
+// is sent after the content instead of in the metadata.
+func newTrailingChecksumForm(t *testing.T, content string, checksum *contentpb.Checksum) (io.Reader, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	writePart := func(name, contentType string, b []byte) {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q`, name))
+		header.Set("Content-Type", contentType)
+
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = part.Write(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	meta, err := proto.Marshal(&contentpb.Metadata{
+		Checksum: &contentpb.Checksum{
+			HashFunc: contentpb.HashFunc_SHA256.Enum(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePart("metadata", rest.ProtobufContentType, meta)
+	writePart("content", "application/octet-stream", []byte(content))
+
+	if checksum != nil {
+		b, err := proto.Marshal(checksum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writePart("checksum", rest.ProtobufContentType, b)
+	}
+
+	err = mw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &body, mw.FormDataContentType()
+}
+
+func TestUploadContentV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the request is not a multipart form", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/v1/content", "text/plain", strings.NewReader("hello world"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the metadata form field is missing", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil))
+			defer srv.Close()
+
+			var body bytes.Buffer
+			body.WriteString("--boundary--\r\n")
+
+			resp, err := http.Post(srv.URL+"/v1/content", "multipart/form-data; boundary=boundary", &body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the metadata does not contain a checksum", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{},
+				Content:  strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the metadata media type is invalid", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			hash := sha256.Sum256([]byte("hello world"))
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					MediaType: &contentpb.MediaType{
+						Type: ptr.Ref("text"),
+					},
+					Checksum: &contentpb.Checksum{
+						HashFunc: contentpb.HashFunc_SHA256.Enum(),
+						Hash:     hash[:],
+					},
+				},
+				Content: strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the content does not match the checksum", func(t *testing.T) {
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(io.Discard, r)
+				return err
+			})
+
+			idx := memory.New()
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			hash := sha256.Sum256([]byte("goodbye world"))
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: &contentpb.Checksum{
+						HashFunc: contentpb.HashFunc_SHA256.Enum(),
+						Hash:     hash[:],
+					},
+				},
+				Content: strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to store the content", func(t *testing.T) {
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				return errors.New("failed to store")
+			})
+
+			idx := memory.New()
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			hash := sha256.Sum256([]byte("hello world"))
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: &contentpb.Checksum{
+						HashFunc: contentpb.HashFunc_SHA256.Enum(),
+						Hash:     hash[:],
+					},
+				},
+				Content: strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if it fails to index the content", func(t *testing.T) {
+			stored := make(map[string]bool)
+			store := storageStub{
+				put: func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+					_, err := io.Copy(io.Discard, r)
+					stored[ci.GetValue()] = true
+					return err
+				},
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					delete(stored, ci.GetValue())
+					return nil
+				},
+			}
+			mem := memory.New()
+			idx := indexStub{
+				put: func(ctx context.Context, r *indexpb.Record) error {
+					return errors.New("failed to index")
+				},
+				queryByContentId: mem.QueryByContentId,
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			hash := sha256.Sum256([]byte("hello world"))
+			_, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: &contentpb.Checksum{
+						HashFunc: contentpb.HashFunc_SHA256.Enum(),
+						Hash:     hash[:],
+					},
+				},
+				Content: strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+			if !assert.Empty(t, stored) {
+				return
+			}
+		})
+
+		t.Run("if the trailing checksum is missing", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil, SpoolDir(t.TempDir())))
+			defer srv.Close()
+
+			body, contentType := newTrailingChecksumForm(t, "hello world", nil)
+
+			resp, err := http.Post(srv.URL+"/v1/content", contentType, body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the content does not match the trailing checksum", func(t *testing.T) {
+			spoolDir := t.TempDir()
+			srv := httptest.NewServer(NewServer(nil, nil, SpoolDir(spoolDir)))
+			defer srv.Close()
+
+			hash := sha256.Sum256([]byte("goodbye world"))
+			body, contentType := newTrailingChecksumForm(t, "hello world", &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+				Hash:     hash[:],
+			})
+
+			resp, err := http.Post(srv.URL+"/v1/content", contentType, body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusBadRequest, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+
+			entries, err := os.ReadDir(spoolDir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, entries) {
+				return
+			}
+		})
+
+		t.Run("if the content is too large to spool", func(t *testing.T) {
+			spoolDir := t.TempDir()
+			srv := httptest.NewServer(NewServer(nil, nil, SpoolDir(spoolDir), MaxSpoolSize(5)))
+			defer srv.Close()
+
+			hash := sha256.Sum256([]byte("hello world"))
+			body, contentType := newTrailingChecksumForm(t, "hello world", &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+				Hash:     hash[:],
+			})
+
+			resp, err := http.Post(srv.URL+"/v1/content", contentType, body)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode) {
+				return
+			}
+
+			status := readStatus(t, resp)
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+
+			entries, err := os.ReadDir(spoolDir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, entries) {
+				return
+			}
+		})
+	})
+
+	t.Run("will keep the stored content", func(t *testing.T) {
+		t.Run("if it fails to index it but another user has it indexed", func(t *testing.T) {
+			deleted := false
+			store := storageStub{
+				put: func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+					_, err := io.Copy(io.Discard, r)
+					return err
+				},
+				del: func(ctx context.Context, ci *contentpb.ContentId) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			checksum := &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+			}
+			hash := sha256.Sum256([]byte("hello world"))
+			checksum.Hash = hash[:]
+
+			mem := memory.New()
+			err := mem.Put(context.Background(), &indexpb.Record{
+				ContentId: NewContentId(checksum),
+				CheckSums: []*contentpb.Checksum{checksum},
+				Owner:     ptr.Ref("alice"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			idx := indexStub{
+				put: func(ctx context.Context, r *indexpb.Record) error {
+					return errors.New("failed to index")
+				},
+				queryByContentId: mem.QueryByContentId,
+			}
+
+			srv := httptest.NewServer(NewServer(store, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err = c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: checksum,
+				},
+				Content: strings.NewReader("hello world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INTERNAL, status.GetCode()) {
+				return
+			}
+			if !assert.False(t, deleted) {
+				return
+			}
+		})
+	})
+
+	t.Run("will store the content", func(t *testing.T) {
+		t.Run("if the content matches the checksum", func(t *testing.T) {
+			var stored bytes.Buffer
+			var storedId string
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				storedId = ci.GetValue()
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+
+			idx := memory.New()
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			checksum := &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA256.Enum(),
+			}
+			hash := sha256.Sum256([]byte("hello world"))
+			checksum.Hash = hash[:]
+
+			resp, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Name:     ptr.Ref("hello"),
+					Checksum: checksum,
+				},
+				Content: strings.NewReader("hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, NewContentId(checksum).GetValue(), resp.Id) {
+				return
+			}
+			if !assert.Equal(t, resp.Id, storedId) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+
+			record, err := idx.Get(context.Background(), "", &contentpb.ContentId{Value: &resp.Id})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello", record.GetContentName()) {
+				return
+			}
+			if !assert.Equal(t, uint64(len("hello world")), record.GetContentSize().GetValue()) {
+				return
+			}
+			if !assert.Equal(t, checksum.GetHash(), record.GetCheckSums()[0].GetHash()) {
+				return
+			}
+		})
+
+		t.Run("with the media type split into type, subtype and suffix", func(t *testing.T) {
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(io.Discard, r)
+				return err
+			})
+
+			idx := memory.New()
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, idx))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			hash := sha256.Sum256([]byte("{}"))
+			resp, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Name: ptr.Ref("hello"),
+					MediaType: &contentpb.MediaType{
+						Type: ptr.Ref("application/ld+json"),
+					},
+					Checksum: &contentpb.Checksum{
+						HashFunc: contentpb.HashFunc_SHA256.Enum(),
+						Hash:     hash[:],
+					},
+				},
+				Content: strings.NewReader("{}"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			record, err := idx.Get(context.Background(), "", &contentpb.ContentId{Value: &resp.Id})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "application", record.GetContentType().GetType()) {
+				return
+			}
+			if !assert.Equal(t, "ld", record.GetContentType().GetSubtype()) {
+				return
+			}
+			if !assert.Equal(t, "json", record.GetContentType().GetSuffix()) {
+				return
+			}
+		})
+
+		t.Run("if the content matches the trailing checksum", func(t *testing.T) {
+			var stored bytes.Buffer
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+
+			idx := memory.New()
+			spoolDir := t.TempDir()
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, idx, SpoolDir(spoolDir)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			resp, err := c.UploadContent(context.Background(), &UploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: &contentpb.Checksum{
+						HashFunc: contentpb.HashFunc_SHA512.Enum(),
+					},
+				},
+				Content: strings.NewReader("hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			hash := sha512.Sum512([]byte("hello world"))
+			checksum := &contentpb.Checksum{
+				HashFunc: contentpb.HashFunc_SHA512.Enum(),
+				Hash:     hash[:],
+			}
+			if !assert.Equal(t, NewContentId(checksum).GetValue(), resp.Id) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+
+			record, err := idx.Get(context.Background(), "", &contentpb.ContentId{Value: &resp.Id})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, hash[:], record.GetCheckSums()[0].GetHash()) {
+				return
+			}
+
+			entries, err := os.ReadDir(spoolDir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Empty(t, entries) {
+				return
+			}
+		})
+
+		t.Run("if the checksum uses any supported hash func", func(t *testing.T) {
+			for _, hf := range SupportedHashFuncs() {
+				t.Run(hf.String(), func(t *testing.T) {
+					store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+						_, err := io.Copy(io.Discard, r)
+						return err
+					})
+
+					srv := httptest.NewServer(NewServer(storageStub{put: store}, memory.New()))
+					defer srv.Close()
+
+					c := NewClient(http.DefaultClient, srv.URL)
+
+					h, err := NewHash(hf)
+					if !assert.Nil(t, err) {
+						return
+					}
+					h.Write([]byte("hello world"))
+
+					checksum := &contentpb.Checksum{
+						HashFunc: hf.Enum(),
+						Hash:     h.Sum(nil),
+					}
+					resp, err := c.UploadContent(context.Background(), &UploadContentRequest{
+						Metadata: &contentpb.Metadata{
+							Checksum: checksum,
+						},
+						Content: strings.NewReader("hello world"),
+					})
+					if !assert.Nil(t, err) {
+						return
+					}
+					if !assert.Equal(t, NewContentId(checksum).GetValue(), resp.Id) {
+						return
+					}
+				})
+			}
+		})
+	})
+}
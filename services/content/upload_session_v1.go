@@ -0,0 +1,280 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index"
+	"github.com/z5labs/griot/services/content/session"
+	"github.com/z5labs/griot/services/content/storage"
+
+	"github.com/z5labs/humus/humuspb"
+	"github.com/z5labs/humus/rest"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+)
+
+// UploadOffsetHeader is the committed offset of an upload session in responses
+// and, when appending content, the offset the appended content begins at.
+const UploadOffsetHeader = "Upload-Offset"
+
+var (
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrRequestTooLarge        = errors.New("request too large")
+	ErrInvalidUploadOffset    = errors.New("upload offset must be a non-negative integer")
+)
+
+// uploadSessionV1Handler implements the resumable upload APIs. Content is
+// appended to an upload session in chunks, each of which is committed to the
+// session.Store, and only moved into Content Storage once the session is completed.
+// Sessions belong to the user who created them and are indexed for that user once
+// completed, so sessions of any other user are treated as if they don't exist.
+//
+// Appending to, completing and aborting a session are serialized by an exclusive
+// lock on the session, so content can't be appended while a session is being
+// verified against its checksum and a valid session is never mistaken for a mismatch.
+type uploadSessionV1Handler struct {
+	log            *slog.Logger
+	store          storage.Storage
+	index          index.Index
+	locks          *keyedLocks
+	sessionLocks   *keyedLocks
+	sessions       session.Store
+	protoMarshal   func(proto.Message) ([]byte, error)
+	protoUnmarshal func([]byte, proto.Message) error
+}
+
+func (h *uploadSessionV1Handler) create(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "uploadSessionV1Handler.create")
+	defer span.End()
+
+	meta, err := h.readMetadata(r)
+	if err != nil {
+		span.RecordError(err)
+		h.log.WarnContext(spanCtx, "failed to read metadata", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, err.Error())
+		return
+	}
+
+	sess, err := h.sessions.Create(spanCtx, callerFromContext(spanCtx), meta)
+	if err != nil {
+		span.RecordError(err)
+		h.log.ErrorContext(spanCtx, "failed to create upload session", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "failed to create upload session")
+		return
+	}
+
+	w.Header().Set("Location", "/v1/uploads/"+url.PathEscape(sess.GetId()))
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(sess.GetOffset(), 10))
+	writeProto(h.log, w, http.StatusCreated, h.protoMarshal, sess)
+}
+
+func (h *uploadSessionV1Handler) readMetadata(r *http.Request) (*contentpb.Metadata, error) {
+	if r.Header.Get("Content-Type") != rest.ProtobufContentType {
+		return nil, ErrUnsupportedContentType
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxMetadataSize {
+		return nil, ErrRequestTooLarge
+	}
+
+	var meta contentpb.Metadata
+	err = h.protoUnmarshal(b, &meta)
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.GetChecksum().GetHash()) == 0 {
+		return nil, ErrMissingChecksum
+	}
+	err = normalizeMediaType(&meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail now rather than after all the content has been uploaded
+	_, err = NewHash(meta.GetChecksum().GetHashFunc())
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func (h *uploadSessionV1Handler) get(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "uploadSessionV1Handler.get")
+	defer span.End()
+
+	sess, err := h.getOwned(spanCtx, r.PathValue("id"))
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(sess.GetOffset(), 10))
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, sess)
+}
+
+func (h *uploadSessionV1Handler) append(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "uploadSessionV1Handler.append")
+	defer span.End()
+
+	offset, err := strconv.ParseInt(r.Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INVALID_ARGUMENT, ErrInvalidUploadOffset.Error())
+		return
+	}
+
+	sessionId := r.PathValue("id")
+	unlock := h.sessionLocks.exclusive(sessionId)
+	defer unlock()
+
+	_, err = h.getOwned(spanCtx, sessionId)
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+
+	sess, err := h.sessions.Append(spanCtx, sessionId, offset, r.Body)
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(sess.GetOffset(), 10))
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, sess)
+}
+
+func (h *uploadSessionV1Handler) complete(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "uploadSessionV1Handler.complete")
+	defer span.End()
+
+	sessionId := r.PathValue("id")
+	unlock := h.sessionLocks.exclusive(sessionId)
+	defer unlock()
+
+	sess, err := h.getOwned(spanCtx, sessionId)
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+
+	rc, err := h.sessions.Open(spanCtx, sessionId)
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+	defer rc.Close()
+
+	id, err := storeContent(spanCtx, h.locks, h.store, h.index, sess.GetOwner(), sess.GetMetadata(), rc)
+	var mismatchErr ChecksumMismatchError
+	if errors.As(err, &mismatchErr) {
+		// the committed content can never match so there's no point in keeping it
+		h.deleteSession(spanCtx, sessionId)
+	}
+	if err != nil {
+		span.RecordError(err)
+		writeStoreContentError(spanCtx, h.log, w, h.protoMarshal, err)
+		return
+	}
+
+	h.deleteSession(spanCtx, sessionId)
+
+	writeProto(h.log, w, http.StatusOK, h.protoMarshal, &contentpb.UploadContentV1Response{
+		Id: id,
+	})
+}
+
+func (h *uploadSessionV1Handler) abort(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := otel.Tracer("content").Start(r.Context(), "uploadSessionV1Handler.abort")
+	defer span.End()
+
+	sessionId := r.PathValue("id")
+	unlock := h.sessionLocks.exclusive(sessionId)
+	defer unlock()
+
+	_, err := h.getOwned(spanCtx, sessionId)
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+
+	err = h.sessions.Delete(spanCtx, sessionId)
+	if err != nil {
+		span.RecordError(err)
+		h.writeSessionError(spanCtx, w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getOwned gets the session, treating a session owned by another user as if it doesn't exist.
+func (h *uploadSessionV1Handler) getOwned(ctx context.Context, id string) (*contentpb.UploadSession, error) {
+	sess, err := h.sessions.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sess.GetOwner() != callerFromContext(ctx) {
+		return nil, session.ErrNotFound
+	}
+	return sess, nil
+}
+
+// deleteSession only logs failures since the session is no longer needed
+// and failing to delete it doesn't affect the outcome of the request.
+func (h *uploadSessionV1Handler) deleteSession(ctx context.Context, id string) {
+	err := h.sessions.Delete(ctx, id)
+	if err != nil {
+		h.log.WarnContext(ctx, "failed to delete upload session", slog.String("session_id", id), slog.String("error", err.Error()))
+	}
+}
+
+func (h *uploadSessionV1Handler) writeSessionError(ctx context.Context, w http.ResponseWriter, err error) {
+	var mismatchErr session.OffsetMismatchError
+	switch {
+	case errors.Is(err, session.ErrNotFound), errors.Is(err, session.ErrInvalidId):
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_NOT_FOUND, "upload session not found")
+	case errors.As(err, &mismatchErr):
+		w.Header().Set(UploadOffsetHeader, strconv.FormatInt(mismatchErr.Committed, 10))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_ABORTED, err.Error())
+	default:
+		h.log.ErrorContext(ctx, "upload session failure", slog.String("error", err.Error()))
+		writeStatus(h.log, w, h.protoMarshal, humuspb.Code_INTERNAL, "upload session failure")
+	}
+}
+
+// unimplemented responds to optional APIs, e.g. resumable uploads, when
+// the server hasn't been configured with what they require.
+func unimplemented(log *slog.Logger, marshal func(proto.Message) ([]byte, error), msg string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(log, w, marshal, humuspb.Code_UNIMPLEMENTED, msg)
+	}
+}
@@ -0,0 +1,479 @@
+// Copyright 2024 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z5labs/griot/internal/ptr"
+	"github.com/z5labs/griot/services/content/contentpb"
+	"github.com/z5labs/griot/services/content/index/memory"
+	"github.com/z5labs/griot/services/content/session"
+	"github.com/z5labs/griot/services/content/session/filesystem"
+	tokenmemory "github.com/z5labs/griot/services/content/token/memory"
+	"github.com/z5labs/griot/services/content/tokenpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z5labs/humus/humuspb"
+)
+
+func newSessionStore(t *testing.T) *filesystem.Store {
+	t.Helper()
+
+	sessions, err := filesystem.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sessions
+}
+
+// blockingOpenStore waits to open an upload session until it's told to finish opening.
+type blockingOpenStore struct {
+	session.Store
+
+	opening    chan struct{}
+	finishOpen chan struct{}
+}
+
+func (s blockingOpenStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	close(s.opening)
+	<-s.finishOpen
+	return s.Store.Open(ctx, id)
+}
+
+func sha256Checksum(b []byte) *contentpb.Checksum {
+	hash := sha256.Sum256(b)
+	return &contentpb.Checksum{
+		HashFunc: contentpb.HashFunc_SHA256.Enum(),
+		Hash:     hash[:],
+	}
+}
+
+func TestUploadSessionV1Handler(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if upload sessions are not enabled", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_UNIMPLEMENTED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the metadata does not contain a checksum", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil, UploadSessions(newSessionStore(t))))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: &contentpb.Checksum{},
+				},
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the upload session does not exist", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil, UploadSessions(newSessionStore(t))))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			_, err := c.GetUploadSession(context.Background(), &GetUploadSessionRequest{
+				Id: "0123456789abcdef0123456789abcdef",
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the content is not appended at the committed offset", func(t *testing.T) {
+			srv := httptest.NewServer(NewServer(nil, nil, UploadSessions(newSessionStore(t))))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			sess, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AppendUploadSession(context.Background(), &AppendUploadSessionRequest{
+				Id:      sess.Id,
+				Offset:  5,
+				Content: strings.NewReader(" world"),
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_ABORTED, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if the upload session belongs to another user", func(t *testing.T) {
+			tokens := tokenmemory.New()
+			idx := memory.New()
+			sessions := newSessionStore(t)
+
+			srv := httptest.NewServer(NewServer(nil, idx, Authentication(tokens), UploadSessions(sessions)))
+			defer srv.Close()
+
+			bob := NewClient(http.DefaultClient, srv.URL, Credentials(mintToken(t, tokens, "bob", tokenpb.Scope_UPLOAD)))
+			alice := NewClient(http.DefaultClient, srv.URL, Credentials(mintToken(t, tokens, "alice", tokenpb.Scope_UPLOAD)))
+
+			sess, err := bob.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("hello world")),
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = alice.GetUploadSession(context.Background(), &GetUploadSessionRequest{
+				Id: sess.Id,
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+
+			_, err = alice.AppendUploadSession(context.Background(), &AppendUploadSessionRequest{
+				Id:      sess.Id,
+				Content: strings.NewReader("hello world"),
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+
+			_, err = alice.CompleteUploadSession(context.Background(), &CompleteUploadSessionRequest{
+				Id: sess.Id,
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+
+			err = alice.AbortUploadSession(context.Background(), &AbortUploadSessionRequest{
+				Id: sess.Id,
+			})
+			if !assertStatusCode(t, humuspb.Code_NOT_FOUND, err) {
+				return
+			}
+
+			got, err := sessions.Get(context.Background(), sess.Id)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, int64(0), got.GetOffset()) {
+				return
+			}
+			if !assert.Equal(t, "bob", got.GetOwner()) {
+				return
+			}
+		})
+
+		t.Run("if the content does not match the checksum", func(t *testing.T) {
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(io.Discard, r)
+				return err
+			})
+			sessions := newSessionStore(t)
+
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, memory.New(), UploadSessions(sessions)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			sess, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: sha256Checksum([]byte("goodbye world")),
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.AppendUploadSession(context.Background(), &AppendUploadSessionRequest{
+				Id:      sess.Id,
+				Content: strings.NewReader("hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = c.CompleteUploadSession(context.Background(), &CompleteUploadSessionRequest{
+				Id: sess.Id,
+			})
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, err, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_INVALID_ARGUMENT, status.GetCode()) {
+				return
+			}
+
+			_, err = sessions.Get(context.Background(), sess.Id)
+			if !assert.ErrorIs(t, err, session.ErrNotFound) {
+				return
+			}
+		})
+	})
+
+	t.Run("will store the content", func(t *testing.T) {
+		t.Run("if all the content has been appended", func(t *testing.T) {
+			var stored bytes.Buffer
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+			idx := memory.New()
+			sessions := newSessionStore(t)
+
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, idx, UploadSessions(sessions)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			checksum := sha256Checksum([]byte("hello world"))
+
+			var sessionId string
+			resp, err := c.ResumableUploadContent(context.Background(), &ResumableUploadContentRequest{
+				Metadata: &contentpb.Metadata{
+					Name:     ptr.Ref("hello"),
+					Checksum: checksum,
+				},
+				Content:   strings.NewReader("hello world"),
+				ChunkSize: 3,
+				OnSession: func(id string) error {
+					sessionId = id
+					return nil
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, NewContentId(checksum).GetValue(), resp.Id) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+
+			record, err := idx.Get(context.Background(), "", &contentpb.ContentId{Value: &resp.Id})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, "hello", record.GetContentName()) {
+				return
+			}
+
+			_, err = sessions.Get(context.Background(), sessionId)
+			if !assert.ErrorIs(t, err, session.ErrNotFound) {
+				return
+			}
+		})
+
+		t.Run("if content is appended while the upload session is being completed", func(t *testing.T) {
+			var stored bytes.Buffer
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+			sessions := blockingOpenStore{
+				Store:      newSessionStore(t),
+				opening:    make(chan struct{}),
+				finishOpen: make(chan struct{}),
+			}
+
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, memory.New(), UploadSessions(sessions)))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			checksum := sha256Checksum([]byte("hello world"))
+			sess, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: &contentpb.Metadata{
+					Checksum: checksum,
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			sess, err = c.AppendUploadSession(context.Background(), &AppendUploadSessionRequest{
+				Id:      sess.Id,
+				Content: strings.NewReader("hello world"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			type completed struct {
+				resp *UploadContentResponse
+				err  error
+			}
+			completeResult := make(chan completed, 1)
+			go func() {
+				resp, err := c.CompleteUploadSession(context.Background(), &CompleteUploadSessionRequest{
+					Id: sess.Id,
+				})
+				completeResult <- completed{resp: resp, err: err}
+			}()
+			<-sessions.opening
+
+			appendErr := make(chan error, 1)
+			go func() {
+				_, err := c.AppendUploadSession(context.Background(), &AppendUploadSessionRequest{
+					Id:      sess.Id,
+					Offset:  sess.Offset,
+					Content: strings.NewReader("!"),
+				})
+				appendErr <- err
+			}()
+
+			select {
+			case err := <-appendErr:
+				close(sessions.finishOpen)
+				<-completeResult
+				assert.Fail(t, "expected append to wait for the upload session to be completed", "append error: %v", err)
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+			close(sessions.finishOpen)
+
+			result := <-completeResult
+			if !assert.Nil(t, result.err) {
+				return
+			}
+			if !assert.Equal(t, NewContentId(checksum).GetValue(), result.resp.Id) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+
+			var status *humuspb.Status
+			if !assert.ErrorAs(t, <-appendErr, &status) {
+				return
+			}
+			if !assert.Equal(t, humuspb.Code_NOT_FOUND, status.GetCode()) {
+				return
+			}
+		})
+
+		t.Run("if resuming a partially appended upload session", func(t *testing.T) {
+			var stored bytes.Buffer
+			store := storagePutFunc(func(ctx context.Context, ci *contentpb.ContentId, r io.Reader) error {
+				_, err := io.Copy(&stored, r)
+				return err
+			})
+
+			srv := httptest.NewServer(NewServer(storageStub{put: store}, memory.New(), UploadSessions(newSessionStore(t))))
+			defer srv.Close()
+
+			c := NewClient(http.DefaultClient, srv.URL)
+
+			meta := &contentpb.Metadata{
+				Checksum: sha256Checksum([]byte("hello world")),
+			}
+			sess, err := c.CreateUploadSession(context.Background(), &CreateUploadSessionRequest{
+				Metadata: meta,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			sess, err = c.AppendUploadSession(context.Background(), &AppendUploadSessionRequest{
+				Id:      sess.Id,
+				Content: strings.NewReader("hello"),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, int64(len("hello")), sess.Offset) {
+				return
+			}
+
+			var resumedId string
+			var progress []Progress
+			_, err = c.ResumableUploadContent(context.Background(), &ResumableUploadContentRequest{
+				Metadata:  meta,
+				SessionId: sess.Id,
+				Content:   strings.NewReader("hello world"),
+				OnSession: func(id string) error {
+					resumedId = id
+					return nil
+				},
+				OnProgress: func(p Progress) {
+					progress = append(progress, p)
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, sess.Id, resumedId) {
+				return
+			}
+			if !assert.Equal(t, Progress{BytesSent: 5, TotalBytes: 11}, progress[0]) {
+				return
+			}
+			if !assert.Equal(t, Progress{BytesSent: 11, TotalBytes: 11}, progress[len(progress)-1]) {
+				return
+			}
+			if !assert.Equal(t, "hello world", stored.String()) {
+				return
+			}
+		})
+	})
+}